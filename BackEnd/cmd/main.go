@@ -49,7 +49,26 @@ func main() {
 
 	userService := services.NewUserService(repos.User)
 	authService := services.NewAuthService(repos.User, jwtService, passwordService, emailService)
-	schemaService := services.NewSchemaService(repos.Schema, repos.User)
+	schemaService := services.NewSchemaService(repos.Schema, repos.SchemaVersion, repos.SchemaBranch, repos.ChangeRequest, repos.CommentThread, repos.ShareLink, repos.SchemaStar, repos.User)
+	exportService := services.NewExportService(schemaService)
+	importService := services.NewImportService(schemaService)
+	verifyService := services.NewVerifyService(schemaService, &cfg.Verify)
+	commentService := services.NewCommentService(schemaService, repos.CommentThread, repos.User, emailService)
+	shareService := services.NewShareService(schemaService, commentService, repos.ShareLink, passwordService)
+	organizeService := services.NewOrganizeService(repos.Schema, repos.Folder)
+
+	if _, err := schemaService.MigrateKeyDefinitions(context.Background()); err != nil {
+		loggerInstance.Errorf("Failed to migrate schema keys: %v", err)
+	}
+	if err := schemaService.EnsureSearchIndex(context.Background()); err != nil {
+		loggerInstance.Errorf("Failed to create schema search index: %v", err)
+	}
+	if err := schemaService.EnsureStarIndex(context.Background()); err != nil {
+		loggerInstance.Errorf("Failed to create schema star index: %v", err)
+	}
+	if err := shareService.EnsureTokenIndex(context.Background()); err != nil {
+		loggerInstance.Errorf("Failed to create share link token index: %v", err)
+	}
 
 	aiService, err := services.NewAIService(cfg)
 	if err != nil {
@@ -62,7 +81,12 @@ func main() {
 
 	authHandler := handlers.NewAuthHandler(authService, userService)
 	schemaHandler := handlers.NewSchemaHandler(schemaService)
+	exportHandler := handlers.NewExportHandler(exportService, verifyService)
+	importHandler := handlers.NewImportHandler(importService)
 	aiHandler := handlers.NewAIHandler(aiService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	shareHandler := handlers.NewShareHandler(shareService)
+	organizeHandler := handlers.NewOrganizeHandler(organizeService)
 
 	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
@@ -70,7 +94,7 @@ func main() {
 
 	r := gin.New()
 
-	routes.SetupRoutes(r, authHandler, schemaHandler, aiHandler, authMiddleware, securityMiddleware)
+	routes.SetupRoutes(r, authHandler, schemaHandler, exportHandler, importHandler, aiHandler, commentHandler, shareHandler, organizeHandler, authMiddleware, securityMiddleware)
 
 	server := &http.Server{
 		Addr:    ":" + cfg.Server.Port,
@@ -18,6 +18,7 @@ type Config struct {
 	Security SecurityConfig
 	AI       AIConfig
 	Email    EmailConfig
+	Verify   VerifyConfig
 }
 
 type ServerConfig struct {
@@ -53,6 +54,16 @@ type AIConfig struct {
 	GeminiAPIKey string
 }
 
+// VerifyConfig points DDL verification at database servers. SQLite runs
+// embedded; Postgres and MySQL are only verified when their URLs are set,
+// and should point at disposable servers: statements run as the configured
+// user.
+type VerifyConfig struct {
+	PostgresURL string
+	MySQLURL    string
+	Timeout     time.Duration
+}
+
 type EmailConfig struct {
 	Host     string
 	Port     int
@@ -91,6 +102,11 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid EMAIL_PORT value: %v", err)
 	}
 
+	verifyTimeout, err := time.ParseDuration(getEnv("DDL_VERIFY_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DDL_VERIFY_TIMEOUT value: %v", err)
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port: getEnv("PORT", "8080"),
@@ -125,6 +141,11 @@ func Load() (*Config, error) {
 			Password: getEnv("EMAIL_PASS", ""),
 			FromName: getEnv("EMAIL_FROM_NAME", "Schema Builder"),
 		},
+		Verify: VerifyConfig{
+			PostgresURL: getEnv("DDL_VERIFY_POSTGRES_URL", ""),
+			MySQLURL:    getEnv("DDL_VERIFY_MYSQL_URL", ""),
+			Timeout:     verifyTimeout,
+		},
 	}
 
 	if err := config.Validate(); err != nil {
@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/middleware"
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/services"
+	"schema-builder-backend/internal/utils"
+)
+
+func (h *SchemaHandler) CreateChangeRequest(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	var req models.CreateChangeRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	changeRequest, err := h.schemaService.CreateChangeRequest(c.Request.Context(), id, user.ID, &req)
+	if err != nil {
+		h.respondChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Change request created successfully",
+		Data:    changeRequest,
+	})
+}
+
+func (h *SchemaHandler) ListChangeRequests(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.ChangeRequestOpen, models.ChangeRequestMerged, models.ChangeRequestClosed:
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be open, merged or closed",
+		})
+		return
+	}
+
+	changeRequests, err := h.schemaService.ListChangeRequests(c.Request.Context(), id, user.ID, status)
+	if err != nil {
+		h.respondChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Change requests retrieved successfully",
+		Data:    changeRequests,
+	})
+}
+
+func (h *SchemaHandler) GetChangeRequest(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	requestID, err := primitive.ObjectIDFromHex(c.Param("request"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid change request ID format",
+		})
+		return
+	}
+
+	changeRequest, err := h.schemaService.GetChangeRequest(c.Request.Context(), id, requestID, user.ID)
+	if err != nil {
+		h.respondChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Change request retrieved successfully",
+		Data:    changeRequest,
+	})
+}
+
+func (h *SchemaHandler) UpdateChangeRequest(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	requestID, err := primitive.ObjectIDFromHex(c.Param("request"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid change request ID format",
+		})
+		return
+	}
+
+	var req models.UpdateChangeRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	changeRequest, err := h.schemaService.UpdateChangeRequest(c.Request.Context(), id, requestID, user.ID, &req)
+	if err != nil {
+		h.respondChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Change request updated successfully",
+		Data:    changeRequest,
+	})
+}
+
+func (h *SchemaHandler) ReviewChangeRequest(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	requestID, err := primitive.ObjectIDFromHex(c.Param("request"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid change request ID format",
+		})
+		return
+	}
+
+	var req models.ReviewChangeRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	changeRequest, err := h.schemaService.ReviewChangeRequest(c.Request.Context(), id, requestID, user.ID, &req)
+	if err != nil {
+		h.respondChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Review added successfully",
+		Data:    changeRequest,
+	})
+}
+
+func (h *SchemaHandler) MergeChangeRequest(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	requestID, err := primitive.ObjectIDFromHex(c.Param("request"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid change request ID format",
+		})
+		return
+	}
+
+	schema, err := h.schemaService.MergeChangeRequest(c.Request.Context(), id, requestID, user.ID)
+	if err != nil {
+		h.respondChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Change request merged successfully",
+		Data:    schema,
+	})
+}
+
+func (h *SchemaHandler) CloseChangeRequest(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	requestID, err := primitive.ObjectIDFromHex(c.Param("request"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid change request ID format",
+		})
+		return
+	}
+
+	changeRequest, err := h.schemaService.CloseChangeRequest(c.Request.Context(), id, requestID, user.ID)
+	if err != nil {
+		h.respondChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Change request closed successfully",
+		Data:    changeRequest,
+	})
+}
+
+func (h *SchemaHandler) respondChangeRequestError(c *gin.Context, err error) {
+	if validationErr, ok := err.(*services.SchemaValidationError); ok {
+		respondSchemaValidationError(c, validationErr)
+		return
+	}
+
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "access denied"):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema not found"),
+		strings.HasPrefix(message, "change request not found"),
+		strings.HasPrefix(message, "schema version not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "invalid change request"),
+		strings.HasPrefix(message, "invalid review"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "change request is not open"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "change_request_closed",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "change request not approved"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "not_approved",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "merge conflicts"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "merge_conflicts",
+			Message: message,
+		})
+	default:
+		h.log.Errorf("Change request operation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "change_request_failed",
+			Message: "Failed to process change request",
+		})
+	}
+}
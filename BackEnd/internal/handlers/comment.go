@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/middleware"
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/repository"
+	"schema-builder-backend/internal/services"
+	"schema-builder-backend/internal/utils"
+	"schema-builder-backend/pkg/logger"
+)
+
+type CommentHandler struct {
+	commentService *services.CommentService
+	log            *logrus.Logger
+}
+
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+		log:            logger.GetLogger(),
+	}
+}
+
+func (h *CommentHandler) CreateThread(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	var req models.CreateCommentThreadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	thread, err := h.commentService.CreateThread(c.Request.Context(), id, user.ID, &req)
+	if err != nil {
+		h.respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Comment thread created successfully",
+		Data:    thread,
+	})
+}
+
+// ListThreads lists the threads of a schema. status=unresolved lists the
+// open discussions only; table_id and field_id narrow the list to an anchor.
+func (h *CommentHandler) ListThreads(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	filter := repository.CommentThreadFilter{
+		TableID: c.Query("table_id"),
+		FieldID: c.Query("field_id"),
+	}
+	switch c.Query("status") {
+	case "":
+	case "unresolved":
+		resolved := false
+		filter.Resolved = &resolved
+	case "resolved":
+		resolved := true
+		filter.Resolved = &resolved
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be resolved or unresolved",
+		})
+		return
+	}
+
+	threads, err := h.commentService.ListThreads(c.Request.Context(), id, user.ID, filter)
+	if err != nil {
+		h.respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Comment threads retrieved successfully",
+		Data:    threads,
+	})
+}
+
+func (h *CommentHandler) GetThread(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	threadID, err := primitive.ObjectIDFromHex(c.Param("thread"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid thread ID format",
+		})
+		return
+	}
+
+	thread, err := h.commentService.GetThread(c.Request.Context(), id, threadID, user.ID)
+	if err != nil {
+		h.respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Comment thread retrieved successfully",
+		Data:    thread,
+	})
+}
+
+func (h *CommentHandler) AddComment(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	threadID, err := primitive.ObjectIDFromHex(c.Param("thread"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid thread ID format",
+		})
+		return
+	}
+
+	var req models.AddCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	thread, err := h.commentService.AddComment(c.Request.Context(), id, threadID, user.ID, &req)
+	if err != nil {
+		h.respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Comment added successfully",
+		Data:    thread,
+	})
+}
+
+func (h *CommentHandler) ResolveThread(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	threadID, err := primitive.ObjectIDFromHex(c.Param("thread"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid thread ID format",
+		})
+		return
+	}
+
+	thread, err := h.commentService.SetResolved(c.Request.Context(), id, threadID, user.ID, true)
+	if err != nil {
+		h.respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Comment thread resolved successfully",
+		Data:    thread,
+	})
+}
+
+func (h *CommentHandler) UnresolveThread(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	threadID, err := primitive.ObjectIDFromHex(c.Param("thread"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid thread ID format",
+		})
+		return
+	}
+
+	thread, err := h.commentService.SetResolved(c.Request.Context(), id, threadID, user.ID, false)
+	if err != nil {
+		h.respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Comment thread reopened successfully",
+		Data:    thread,
+	})
+}
+
+func (h *CommentHandler) respondCommentError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "access denied"):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema not found"),
+		strings.HasPrefix(message, "comment thread not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "invalid anchor"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_anchor",
+			Message: message,
+		})
+	default:
+		h.log.Errorf("Comment operation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "comment_failed",
+			Message: "Failed to process comment",
+		})
+	}
+}
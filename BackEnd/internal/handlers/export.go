@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/middleware"
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/services"
+	"schema-builder-backend/internal/utils"
+	"schema-builder-backend/pkg/logger"
+)
+
+type ExportHandler struct {
+	exportService *services.ExportService
+	verifyService *services.VerifyService
+	log           *logrus.Logger
+}
+
+func NewExportHandler(exportService *services.ExportService, verifyService *services.VerifyService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		verifyService: verifyService,
+		log:           logger.GetLogger(),
+	}
+}
+
+func (h *ExportHandler) ExportSchema(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	opts := services.ExportOptions{
+		Format:    c.DefaultQuery("format", "postgresql"),
+		Namespace: c.Query("namespace"),
+		Package:   c.Query("package"),
+		Nullable:  c.Query("nullable"),
+	}
+	if tags := c.Query("tags"); tags != "" {
+		opts.Tags = strings.Split(tags, ",")
+	}
+
+	result, err := h.exportService.ExportSchema(c.Request.Context(), id, user.ID, opts)
+	if err != nil {
+		h.respondExportError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.FileName))
+	c.Data(http.StatusOK, result.ContentType, result.Content)
+}
+
+func (h *ExportHandler) GenerateDocs(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	opts := services.ExportOptions{
+		Format:    c.DefaultQuery("format", "markdown"),
+		Namespace: c.Query("namespace"),
+	}
+
+	result, err := h.exportService.GenerateDocs(c.Request.Context(), id, user.ID, opts)
+	if err != nil {
+		h.respondExportError(c, err)
+		return
+	}
+
+	if c.Query("download") == "true" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.FileName))
+	}
+	c.Data(http.StatusOK, result.ContentType, result.Content)
+}
+
+func (h *ExportHandler) RenderDiagram(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	opts := services.DiagramOptions{
+		Format:      strings.TrimPrefix(path.Ext(c.FullPath()), "."),
+		Theme:       c.Query("theme"),
+		Namespace:   c.Query("namespace"),
+		HideColumns: c.Query("hide_columns") == "true",
+	}
+	if tables := c.Query("tables"); tables != "" {
+		opts.Tables = strings.Split(tables, ",")
+	}
+
+	result, err := h.exportService.RenderDiagram(c.Request.Context(), id, user.ID, opts)
+	if err != nil {
+		h.respondExportError(c, err)
+		return
+	}
+
+	if c.Query("download") == "true" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.FileName))
+	}
+	c.Data(http.StatusOK, result.ContentType, result.Content)
+}
+
+func (h *ExportHandler) GenerateSeedData(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	var req models.SeedDataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	opts := services.SeedOptions{
+		Format:      req.Format,
+		Seed:        req.Seed,
+		Rows:        req.Rows,
+		DefaultRows: req.DefaultRows,
+	}
+
+	result, err := h.exportService.GenerateSeedData(c.Request.Context(), id, user.ID, opts)
+	if err != nil {
+		h.respondExportError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.FileName))
+	c.Data(http.StatusOK, result.ContentType, result.Content)
+}
+
+func (h *ExportHandler) VerifyDDL(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	// The body is optional.
+	var req models.VerifyDDLRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	result, err := h.verifyService.VerifyDDL(c.Request.Context(), id, user.ID, req.Dialects)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unsupported dialect") {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unsupported_dialect",
+				Message: err.Error(),
+			})
+			return
+		}
+		h.respondExportError(c, err)
+		return
+	}
+
+	message := "DDL verification passed"
+	if !result.Passed {
+		message = "DDL verification did not pass"
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: message,
+		Data:    result,
+	})
+}
+
+func (h *ExportHandler) respondExportError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "access denied: schema is private":
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: "You don't have permission to view this schema",
+		})
+	case strings.HasPrefix(err.Error(), "access denied"):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "schema not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: "Schema not found",
+		})
+	case strings.HasPrefix(err.Error(), "unsupported export format"),
+		strings.HasPrefix(err.Error(), "unsupported docs format"),
+		strings.HasPrefix(err.Error(), "unsupported seed format"),
+		strings.HasPrefix(err.Error(), "unsupported diagram"),
+		strings.HasPrefix(err.Error(), "unknown diagram table"),
+		strings.HasPrefix(err.Error(), "diagram is too large"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "unsupported_format",
+			Message: err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid go "),
+		strings.HasPrefix(err.Error(), "invalid seed options"),
+		strings.HasPrefix(err.Error(), "unknown seed table"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_options",
+			Message: err.Error(),
+		})
+	default:
+		h.log.Errorf("Schema export failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "export_failed",
+			Message: "Failed to export schema",
+		})
+	}
+}
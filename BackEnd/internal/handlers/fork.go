@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/middleware"
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/services"
+	"schema-builder-backend/internal/utils"
+)
+
+func (h *SchemaHandler) ForkSchema(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	// The body is optional.
+	var req models.ForkSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	schema, err := h.schemaService.ForkSchema(c.Request.Context(), id, user.ID, &req)
+	if err != nil {
+		h.respondForkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Schema forked successfully",
+		Data:    schema,
+	})
+}
+
+func (h *SchemaHandler) ListForks(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	forks, err := h.schemaService.ListForks(c.Request.Context(), id, user.ID)
+	if err != nil {
+		h.respondForkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Forks retrieved successfully",
+		Data:    forks,
+	})
+}
+
+func (h *SchemaHandler) PreviewUpstream(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	preview, err := h.schemaService.PreviewUpstream(c.Request.Context(), id, user.ID)
+	if err != nil {
+		h.respondForkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Upstream changes retrieved successfully",
+		Data:    preview,
+	})
+}
+
+func (h *SchemaHandler) PullUpstream(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	// The body is optional.
+	var req models.PullUpstreamRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	schema, err := h.schemaService.PullUpstream(c.Request.Context(), id, user.ID, &req)
+	if err != nil {
+		h.respondForkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Upstream changes pulled successfully",
+		Data:    schema,
+	})
+}
+
+func (h *SchemaHandler) GetStarStatus(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	status, err := h.schemaService.GetStarStatus(c.Request.Context(), id, user.ID)
+	if err != nil {
+		h.respondForkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Star status retrieved successfully",
+		Data:    status,
+	})
+}
+
+func (h *SchemaHandler) StarSchema(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	status, err := h.schemaService.StarSchema(c.Request.Context(), id, user.ID, true)
+	if err != nil {
+		h.respondForkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schema starred successfully",
+		Data:    status,
+	})
+}
+
+func (h *SchemaHandler) UnstarSchema(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	status, err := h.schemaService.StarSchema(c.Request.Context(), id, user.ID, false)
+	if err != nil {
+		h.respondForkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schema unstarred successfully",
+		Data:    status,
+	})
+}
+
+func (h *SchemaHandler) respondForkError(c *gin.Context, err error) {
+	if validationErr, ok := err.(*services.SchemaValidationError); ok {
+		respondSchemaValidationError(c, validationErr)
+		return
+	}
+
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "access denied"):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema not found"),
+		strings.HasPrefix(message, "upstream schema not found"),
+		strings.HasPrefix(message, "schema version not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema is not a fork"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "not_a_fork",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema version mismatch"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "version_conflict",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "unresolved merge conflicts"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "unresolved_conflicts",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "unknown merge conflict"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_resolution",
+			Message: message,
+		})
+	default:
+		h.log.Errorf("Fork operation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "fork_failed",
+			Message: "Failed to process fork",
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"schema-builder-backend/internal/middleware"
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/services"
+	"schema-builder-backend/internal/utils"
+	"schema-builder-backend/pkg/logger"
+)
+
+type ImportHandler struct {
+	importService *services.ImportService
+	log           *logrus.Logger
+}
+
+func NewImportHandler(importService *services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		log:           logger.GetLogger(),
+	}
+}
+
+func (h *ImportHandler) ImportSchema(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req models.ImportSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	schema, err := h.importService.ImportSchema(c.Request.Context(), user.ID, c.Param("format"), &req)
+	if err != nil {
+		if validationErr, ok := err.(*services.SchemaValidationError); ok {
+			respondSchemaValidationError(c, validationErr)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "unsupported import format") || strings.HasPrefix(err.Error(), "failed to parse") {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "import_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		h.log.Errorf("Schema import failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "import_failed",
+			Message: "Failed to import schema",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Schema imported successfully",
+		Data:    schema,
+	})
+}
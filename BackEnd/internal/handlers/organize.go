@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/middleware"
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/services"
+	"schema-builder-backend/internal/utils"
+	"schema-builder-backend/pkg/logger"
+)
+
+type OrganizeHandler struct {
+	organizeService *services.OrganizeService
+	log             *logrus.Logger
+}
+
+func NewOrganizeHandler(organizeService *services.OrganizeService) *OrganizeHandler {
+	return &OrganizeHandler{
+		organizeService: organizeService,
+		log:             logger.GetLogger(),
+	}
+}
+
+func (h *OrganizeHandler) ListFolders(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	tree, err := h.organizeService.ListFolders(c.Request.Context(), user.ID)
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Folders retrieved successfully",
+		Data:    tree,
+	})
+}
+
+func (h *OrganizeHandler) CreateFolder(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req models.CreateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	folder, err := h.organizeService.CreateFolder(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Folder created successfully",
+		Data:    folder,
+	})
+}
+
+func (h *OrganizeHandler) UpdateFolder(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	folderParam := c.Param("folder")
+	folderID, err := primitive.ObjectIDFromHex(folderParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid folder ID format",
+		})
+		return
+	}
+
+	var req models.UpdateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	folder, err := h.organizeService.UpdateFolder(c.Request.Context(), folderID, user.ID, &req)
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Folder updated successfully",
+		Data:    folder,
+	})
+}
+
+func (h *OrganizeHandler) DeleteFolder(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	folderParam := c.Param("folder")
+	folderID, err := primitive.ObjectIDFromHex(folderParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid folder ID format",
+		})
+		return
+	}
+
+	if err := h.organizeService.DeleteFolder(c.Request.Context(), folderID, user.ID); err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Folder deleted successfully",
+	})
+}
+
+func (h *OrganizeHandler) SetSchemaTags(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	var req models.SetSchemaTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	tags, err := h.organizeService.SetSchemaTags(c.Request.Context(), id, user.ID, &req)
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schema tags updated successfully",
+		Data:    gin.H{"tags": tags},
+	})
+}
+
+func (h *OrganizeHandler) MoveSchemas(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req models.MoveSchemasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	moved, err := h.organizeService.MoveSchemas(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schemas moved successfully",
+		Data:    gin.H{"moved": moved},
+	})
+}
+
+func (h *OrganizeHandler) TagSchemas(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req models.TagSchemasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	updated, err := h.organizeService.TagSchemas(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schemas tagged successfully",
+		Data:    gin.H{"updated": updated},
+	})
+}
+
+func (h *OrganizeHandler) ListTags(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	tags, err := h.organizeService.ListTags(c.Request.Context(), user.ID)
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Tags retrieved successfully",
+		Data:    tags,
+	})
+}
+
+func (h *OrganizeHandler) RenameTag(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req models.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	updated, err := h.organizeService.RenameTag(c.Request.Context(), user.ID, c.Param("tag"), &req)
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Tag renamed successfully",
+		Data:    gin.H{"updated": updated},
+	})
+}
+
+func (h *OrganizeHandler) DeleteTag(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	updated, err := h.organizeService.DeleteTag(c.Request.Context(), user.ID, c.Param("tag"))
+	if err != nil {
+		h.respondOrganizeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Tag deleted successfully",
+		Data:    gin.H{"updated": updated},
+	})
+}
+
+func (h *OrganizeHandler) respondOrganizeError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "access denied"):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema not found"),
+		strings.HasPrefix(message, "folder not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "folder already exists"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "folder_exists",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "invalid folder"),
+		strings.HasPrefix(message, "invalid tags"),
+		strings.HasPrefix(message, "invalid schema ID"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: message,
+		})
+	default:
+		h.log.Errorf("Organize operation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to organize schemas",
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -10,6 +13,7 @@ import (
 
 	"schema-builder-backend/internal/middleware"
 	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/repository"
 	"schema-builder-backend/internal/services"
 	"schema-builder-backend/internal/utils"
 	"schema-builder-backend/pkg/logger"
@@ -57,6 +61,10 @@ func (h *SchemaHandler) CreateSchema(c *gin.Context) {
 
 	schema, err := h.schemaService.CreateSchema(c.Request.Context(), user.ID, &req)
 	if err != nil {
+		if validationErr, ok := err.(*services.SchemaValidationError); ok {
+			respondSchemaValidationError(c, validationErr)
+			return
+		}
 		h.log.Errorf("Schema creation failed: %v", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "creation_failed",
@@ -107,12 +115,58 @@ func (h *SchemaHandler) GetSchema(c *gin.Context) {
 		return
 	}
 
+	if namespace, ok := c.GetQuery("namespace"); ok {
+		schema = services.FilterSchemaByNamespace(schema, namespace)
+	}
+
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Message: "Schema retrieved successfully",
 		Data:    schema,
 	})
 }
 
+func (h *SchemaHandler) ListNamespaces(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	schema, err := h.schemaService.GetSchemaByID(c.Request.Context(), id, user.ID)
+	if err != nil {
+		if err.Error() == "access denied: schema is private" {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "access_denied",
+				Message: "You don't have permission to view this schema",
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: "Schema not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Namespaces retrieved successfully",
+		Data:    services.SchemaNamespaces(schema),
+	})
+}
+
 func (h *SchemaHandler) ListUserSchemas(c *gin.Context) {
 	user, exists := middleware.GetUserFromContext(c)
 	if !exists {
@@ -126,7 +180,25 @@ func (h *SchemaHandler) ListUserSchemas(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	schemas, total, err := h.schemaService.GetUserSchemas(c.Request.Context(), user.ID, page, limit)
+	// folder=none lists the schemas in no folder.
+	filter := repository.SchemaListFilter{Tag: services.NormalizeTag(c.Query("tag"))}
+	switch folder := c.Query("folder"); folder {
+	case "":
+	case "none":
+		filter.Unfiled = true
+	default:
+		folderID, err := primitive.ObjectIDFromHex(folder)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "invalid_id",
+				Message: "Invalid folder ID format",
+			})
+			return
+		}
+		filter.FolderID = &folderID
+	}
+
+	schemas, total, err := h.schemaService.GetUserSchemas(c.Request.Context(), user.ID, page, limit, filter)
 	if err != nil {
 		h.log.Errorf("Failed to list user schemas: %v", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -150,11 +222,80 @@ func (h *SchemaHandler) ListUserSchemas(c *gin.Context) {
 	})
 }
 
+// SearchSchemas searches the public schemas and the caller's own by text,
+// owner, table count and table name.
+func (h *SchemaHandler) SearchSchemas(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req models.SearchSchemasRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid search parameters",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	results, total, err := h.schemaService.SearchSchemas(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "search_failed",
+			Message: "Failed to search schemas",
+		})
+		return
+	}
+
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schemas retrieved successfully",
+		Data: map[string]interface{}{
+			"schemas": results,
+			"pagination": map[string]interface{}{
+				"page":       page,
+				"limit":      limit,
+				"total":      total,
+				"totalPages": (total + int64(limit) - 1) / int64(limit),
+			},
+		},
+	})
+}
+
 func (h *SchemaHandler) ListPublicSchemas(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	sort := c.DefaultQuery("sort", "recent")
+	if sort != "recent" && sort != "stars" && sort != "forks" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_sort",
+			Message: "Sort must be recent, stars or forks",
+		})
+		return
+	}
 
-	schemas, total, err := h.schemaService.GetPublicSchemas(c.Request.Context(), page, limit)
+	schemas, total, err := h.schemaService.GetPublicSchemas(c.Request.Context(), page, limit, sort)
 	if err != nil {
 		h.log.Errorf("Failed to list public schemas: %v", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -255,6 +396,10 @@ func (h *SchemaHandler) UpdateSchema(c *gin.Context) {
 
 	schema, err := h.schemaService.UpdateSchema(c.Request.Context(), id, user.ID, &req)
 	if err != nil {
+		if validationErr, ok := err.(*services.SchemaValidationError); ok {
+			respondSchemaValidationError(c, validationErr)
+			return
+		}
 		if err.Error() == "access denied: you can only update your own schemas" {
 			c.JSON(http.StatusForbidden, models.ErrorResponse{
 				Error:   "access_denied",
@@ -430,3 +575,592 @@ func (h *SchemaHandler) ToggleSchemaVisibility(c *gin.Context) {
 		Data:    schema,
 	})
 }
+
+func (h *SchemaHandler) AnalyzeNormalization(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	report, err := h.schemaService.AnalyzeNormalization(c.Request.Context(), id, user.ID)
+	if err != nil {
+		if err.Error() == "access denied: schema is private" {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "access_denied",
+				Message: "You don't have permission to view this schema",
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: "Schema not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Normalization analysis completed",
+		Data:    report,
+	})
+}
+
+func (h *SchemaHandler) ApplyNormalization(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	var req models.ApplyNormalizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	schema, err := h.schemaService.ApplyNormalization(c.Request.Context(), id, user.ID, &req)
+	if err != nil {
+		if validationErr, ok := err.(*services.SchemaValidationError); ok {
+			respondSchemaValidationError(c, validationErr)
+			return
+		}
+		message := err.Error()
+		switch {
+		case message == "access denied: you can only update your own schemas":
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "access_denied",
+				Message: "You don't have permission to update this schema",
+			})
+		case strings.HasPrefix(message, "schema not found"):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "not_found",
+				Message: "Schema not found",
+			})
+		case strings.HasPrefix(message, "schema version mismatch"), strings.HasPrefix(message, "conflicting normalization suggestions"):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "version_conflict",
+				Message: message,
+			})
+		case strings.HasPrefix(message, "unknown normalization suggestion"), strings.HasPrefix(message, "invalid normalization suggestion"):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "invalid_suggestion",
+				Message: message,
+			})
+		default:
+			h.log.Errorf("Applying normalization failed: %v", err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "update_failed",
+				Message: "Failed to apply normalization suggestions",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Normalization suggestions applied successfully",
+		Data:    schema,
+	})
+}
+
+func (h *SchemaHandler) ListSchemaVersions(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	versions, err := h.schemaService.ListSchemaVersions(c.Request.Context(), id, user.ID)
+	if err != nil {
+		h.respondSchemaVersionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schema versions retrieved successfully",
+		Data:    versions,
+	})
+}
+
+func (h *SchemaHandler) GetSchemaVersion(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_version",
+			Message: "Version must be a positive integer",
+		})
+		return
+	}
+
+	snapshot, err := h.schemaService.GetSchemaVersion(c.Request.Context(), id, user.ID, version)
+	if err != nil {
+		h.respondSchemaVersionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schema version retrieved successfully",
+		Data:    snapshot,
+	})
+}
+
+func (h *SchemaHandler) AnalyzeMigrationSafety(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	to, err := strconv.Atoi(c.Param("version"))
+	if err != nil || to < 1 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_version",
+			Message: "Version must be a positive integer",
+		})
+		return
+	}
+
+	from := 0
+	if fromParam := c.Query("from"); fromParam != "" {
+		from, err = strconv.Atoi(fromParam)
+		if err != nil || from < 1 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "invalid_version",
+				Message: "From must be a positive integer",
+			})
+			return
+		}
+	} else if to == 1 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_version",
+			Message: "Version 1 has no previous version; pass from to compare",
+		})
+		return
+	}
+
+	report, err := h.schemaService.AnalyzeMigrationSafety(c.Request.Context(), id, user.ID, from, to, c.Query("dialect"))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unsupported dialect") {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unsupported_dialect",
+				Message: err.Error(),
+			})
+			return
+		}
+		h.respondSchemaVersionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Migration safety analysis completed",
+		Data:    report,
+	})
+}
+
+func (h *SchemaHandler) respondSchemaVersionError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case message == "access denied: schema is private":
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: "You don't have permission to view this schema",
+		})
+	case strings.HasPrefix(message, "schema version not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: "Schema version not found",
+		})
+	case strings.HasPrefix(message, "schema not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: "Schema not found",
+		})
+	default:
+		h.log.Errorf("Reading schema versions failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to read schema versions",
+		})
+	}
+}
+
+func (h *SchemaHandler) CreateBranch(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	var req models.CreateBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	branch, err := h.schemaService.CreateBranch(c.Request.Context(), id, user.ID, &req)
+	if err != nil {
+		h.respondBranchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Schema branch created successfully",
+		Data:    branch,
+	})
+}
+
+func (h *SchemaHandler) ListBranches(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	branches, err := h.schemaService.ListBranches(c.Request.Context(), id, user.ID)
+	if err != nil {
+		h.respondBranchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Schema branches retrieved successfully",
+		Data:    branches,
+	})
+}
+
+func (h *SchemaHandler) PreviewMerge(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("branch"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid branch ID format",
+		})
+		return
+	}
+
+	preview, err := h.schemaService.PreviewMerge(c.Request.Context(), id, branchID, user.ID)
+	if err != nil {
+		h.respondBranchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Merge preview generated",
+		Data:    preview,
+	})
+}
+
+func (h *SchemaHandler) ResolveMerge(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("branch"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid branch ID format",
+		})
+		return
+	}
+
+	var req models.ResolveMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	preview, err := h.schemaService.ResolveMerge(c.Request.Context(), id, branchID, user.ID, &req)
+	if err != nil {
+		h.respondBranchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Merge conflicts resolved",
+		Data:    preview,
+	})
+}
+
+func (h *SchemaHandler) CompleteMerge(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	branchID, err := primitive.ObjectIDFromHex(c.Param("branch"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid branch ID format",
+		})
+		return
+	}
+
+	// The body is optional.
+	var req models.CompleteMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	schema, err := h.schemaService.CompleteMerge(c.Request.Context(), id, branchID, user.ID, &req)
+	if err != nil {
+		h.respondBranchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Branch merged successfully",
+		Data:    schema,
+	})
+}
+
+func (h *SchemaHandler) respondBranchError(c *gin.Context, err error) {
+	if validationErr, ok := err.(*services.SchemaValidationError); ok {
+		respondSchemaValidationError(c, validationErr)
+		return
+	}
+
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "access denied"):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema not found"),
+		strings.HasPrefix(message, "schema branch not found"),
+		strings.HasPrefix(message, "schema version not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema branch is not open"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "branch_closed",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema version mismatch"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "version_conflict",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "unresolved merge conflicts"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "unresolved_conflicts",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "unknown merge conflict"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_resolution",
+			Message: message,
+		})
+	default:
+		h.log.Errorf("Schema branch operation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "branch_failed",
+			Message: "Failed to process schema branch",
+		})
+	}
+}
+
+func respondSchemaValidationError(c *gin.Context, err *services.SchemaValidationError) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error:   "validation_error",
+		Message: "Schema validation failed",
+		Details: map[string]interface{}{"errors": err.Issues},
+	})
+}
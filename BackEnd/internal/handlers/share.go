@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/middleware"
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/services"
+	"schema-builder-backend/internal/utils"
+	"schema-builder-backend/pkg/logger"
+)
+
+// sharePasswordHeader carries the password of a password-protected share
+// link.
+const sharePasswordHeader = "X-Share-Password"
+
+type ShareHandler struct {
+	shareService *services.ShareService
+	log          *logrus.Logger
+}
+
+func NewShareHandler(shareService *services.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+		log:          logger.GetLogger(),
+	}
+}
+
+func (h *ShareHandler) CreateShareLink(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	var req models.CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	link, err := h.shareService.CreateShareLink(c.Request.Context(), id, user.ID, &req)
+	if err != nil {
+		h.respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Share link created successfully",
+		Data:    link,
+	})
+}
+
+func (h *ShareHandler) ListShareLinks(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	links, err := h.shareService.ListShareLinks(c.Request.Context(), id, user.ID)
+	if err != nil {
+		h.respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Share links retrieved successfully",
+		Data:    links,
+	})
+}
+
+func (h *ShareHandler) RevokeShareLink(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schema ID format",
+		})
+		return
+	}
+
+	shareID, err := primitive.ObjectIDFromHex(c.Param("share"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid share link ID format",
+		})
+		return
+	}
+
+	if err := h.shareService.RevokeShareLink(c.Request.Context(), id, shareID, user.ID); err != nil {
+		h.respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Share link revoked successfully",
+	})
+}
+
+// GetSharedSchema is public: the token is the credential.
+func (h *ShareHandler) GetSharedSchema(c *gin.Context) {
+	shared, err := h.shareService.GetSharedSchema(c.Request.Context(), c.Param("token"), c.GetHeader(sharePasswordHeader))
+	if err != nil {
+		h.respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Shared schema retrieved successfully",
+		Data:    shared,
+	})
+}
+
+func (h *ShareHandler) CreateSharedThread(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req models.CreateCommentThreadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	thread, err := h.shareService.CreateSharedThread(c.Request.Context(), c.Param("token"), c.GetHeader(sharePasswordHeader), user.ID, &req)
+	if err != nil {
+		h.respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Comment thread created successfully",
+		Data:    thread,
+	})
+}
+
+func (h *ShareHandler) AddSharedComment(c *gin.Context) {
+	user, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	threadID, err := primitive.ObjectIDFromHex(c.Param("thread"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid thread ID format",
+		})
+		return
+	}
+
+	var req models.AddCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if errors := utils.ValidateStruct(&req); errors != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: map[string]interface{}{"errors": errors},
+		})
+		return
+	}
+
+	thread, err := h.shareService.AddSharedComment(c.Request.Context(), c.Param("token"), c.GetHeader(sharePasswordHeader), threadID, user.ID, &req)
+	if err != nil {
+		h.respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Comment added successfully",
+		Data:    thread,
+	})
+}
+
+func (h *ShareHandler) respondShareError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "access denied"):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "access_denied",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "schema not found"),
+		strings.HasPrefix(message, "share link not found"),
+		strings.HasPrefix(message, "comment thread not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "share link revoked"),
+		strings.HasPrefix(message, "share link expired"):
+		c.JSON(http.StatusGone, models.ErrorResponse{
+			Error:   "link_unavailable",
+			Message: message,
+		})
+	case strings.HasPrefix(message, "password required"):
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "password_required",
+			Message: "This share link is password protected",
+		})
+	case strings.HasPrefix(message, "invalid password"):
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "invalid_password",
+			Message: "Invalid share link password",
+		})
+	case strings.HasPrefix(message, "invalid share link"),
+		strings.HasPrefix(message, "invalid anchor"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: message,
+		})
+	default:
+		h.log.Errorf("Share link operation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "share_failed",
+			Message: "Failed to process share link",
+		})
+	}
+}
@@ -96,24 +96,230 @@ type UpdateUserRequest struct {
 }
 
 type Schema struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Name        string             `bson:"name" json:"name"`
-	Description string             `bson:"description,omitempty" json:"description,omitempty"`
-	Tables      []Table            `bson:"tables" json:"tables"`
-	Version     int                `bson:"version" json:"version"`
-	IsPublic    bool               `bson:"is_public" json:"is_public"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Name         string              `bson:"name" json:"name"`
+	Description  string              `bson:"description,omitempty" json:"description,omitempty"`
+	DatabaseType string              `bson:"database_type,omitempty" json:"database_type,omitempty"`
+	Namespaces   []Namespace         `bson:"namespaces,omitempty" json:"namespaces,omitempty"`
+	Tables       []Table             `bson:"tables" json:"tables"`
+	Enums        []Enum              `bson:"enums,omitempty" json:"enums,omitempty"`
+	Views        []View              `bson:"views,omitempty" json:"views,omitempty"`
+	Version      int                 `bson:"version" json:"version"`
+	IsPublic     bool                `bson:"is_public" json:"is_public"`
+	ForkedFrom   *ForkSource         `bson:"forked_from,omitempty" json:"forked_from,omitempty"`
+	Tags         []string            `bson:"tags,omitempty" json:"tags,omitempty"`
+	FolderID     *primitive.ObjectID `bson:"folder_id,omitempty" json:"folder_id,omitempty"`
+	ForkCount    int                 `bson:"fork_count" json:"fork_count"`
+	StarCount    int                 `bson:"star_count" json:"star_count"`
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// ForkSource records where a fork came from. Version is the upstream
+// version it was forked at and SyncedVersion the upstream version it last
+// pulled, the base for the next pull.
+type ForkSource struct {
+	SchemaID      primitive.ObjectID `bson:"schema_id" json:"schema_id"`
+	UserID        primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name          string             `bson:"name" json:"name"`
+	Version       int                `bson:"version" json:"version"`
+	SyncedVersion int                `bson:"synced_version" json:"synced_version"`
+}
+
+// Folder organizes a user's schemas. Folders nest through ParentID; root
+// folders have none.
+type Folder struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Name      string              `bson:"name" json:"name"`
+	ParentID  *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+type SchemaStar struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SchemaID  primitive.ObjectID `bson:"schema_id" json:"schema_id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// SchemaVersion is a snapshot of a schema definition, recorded whenever the
+// schema's version number changes.
+type SchemaVersion struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SchemaID     primitive.ObjectID `bson:"schema_id" json:"schema_id"`
+	Version      int                `bson:"version" json:"version"`
+	DatabaseType string             `bson:"database_type,omitempty" json:"database_type,omitempty"`
+	Namespaces   []Namespace        `bson:"namespaces,omitempty" json:"namespaces,omitempty"`
+	Tables       []Table            `bson:"tables,omitempty" json:"tables,omitempty"`
+	Enums        []Enum             `bson:"enums,omitempty" json:"enums,omitempty"`
+	Views        []View             `bson:"views,omitempty" json:"views,omitempty"`
+	CreatedBy    primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
+
+const (
+	BranchStatusOpen   = "open"
+	BranchStatusMerged = "merged"
+)
+
+// SchemaBranch links a branch, itself stored as a schema, to the schema it
+// was branched from. BaseVersion is the version it was branched at; merge
+// conflict resolutions are kept by conflict ID until the merge completes.
+type SchemaBranch struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SchemaID       primitive.ObjectID `bson:"schema_id" json:"schema_id"`
+	BranchSchemaID primitive.ObjectID `bson:"branch_schema_id" json:"branch_schema_id"`
+	Name           string             `bson:"name" json:"name"`
+	BaseVersion    int                `bson:"base_version" json:"base_version"`
+	Status         string             `bson:"status" json:"status"`
+	Resolutions    map[string]string  `bson:"resolutions,omitempty" json:"resolutions,omitempty"`
+	MergedVersion  int                `bson:"merged_version,omitempty" json:"merged_version,omitempty"`
+	MergedAt       *time.Time         `bson:"merged_at,omitempty" json:"merged_at,omitempty"`
+	CreatedBy      primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+const (
+	ChangeRequestOpen   = "open"
+	ChangeRequestMerged = "merged"
+	ChangeRequestClosed = "closed"
+
+	ReviewCommented        = "commented"
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+)
+
+// ChangeRequest proposes new tables for a schema, made against BaseVersion.
+// Revision counts edits to the proposal; approvals only count for the
+// revision they were given on.
+type ChangeRequest struct {
+	ID            primitive.ObjectID    `bson:"_id,omitempty" json:"id"`
+	SchemaID      primitive.ObjectID    `bson:"schema_id" json:"schema_id"`
+	AuthorID      primitive.ObjectID    `bson:"author_id" json:"author_id"`
+	Title         string                `bson:"title" json:"title"`
+	Description   string                `bson:"description,omitempty" json:"description,omitempty"`
+	BaseVersion   int                   `bson:"base_version" json:"base_version"`
+	Revision      int                   `bson:"revision" json:"revision"`
+	Tables        []Table               `bson:"tables" json:"tables"`
+	Reviewers     []primitive.ObjectID  `bson:"reviewers" json:"reviewers"`
+	Reviews       []ChangeRequestReview `bson:"reviews" json:"reviews"`
+	Status        string                `bson:"status" json:"status"`
+	MergedVersion int                   `bson:"merged_version,omitempty" json:"merged_version,omitempty"`
+	CreatedAt     time.Time             `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time             `bson:"updated_at" json:"updated_at"`
+}
+
+type ChangeRequestReview struct {
+	ReviewerID primitive.ObjectID `bson:"reviewer_id" json:"reviewer_id"`
+	State      string             `bson:"state" json:"state"`
+	Body       string             `bson:"body,omitempty" json:"body,omitempty"`
+	Revision   int                `bson:"revision" json:"revision"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+const (
+	AnchorSchema = "schema"
+	AnchorTable  = "table"
+	AnchorField  = "field"
+)
+
+// CommentThread is a discussion about a schema, one of its tables or one
+// field of a table. Anchors are IDs, so threads follow renames.
+type CommentThread struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	SchemaID   primitive.ObjectID  `bson:"schema_id" json:"schema_id"`
+	AnchorType string              `bson:"anchor_type" json:"anchor_type"`
+	TableID    string              `bson:"table_id,omitempty" json:"table_id,omitempty"`
+	FieldID    string              `bson:"field_id,omitempty" json:"field_id,omitempty"`
+	Comments   []Comment           `bson:"comments" json:"comments"`
+	Resolved   bool                `bson:"resolved" json:"resolved"`
+	ResolvedBy *primitive.ObjectID `bson:"resolved_by,omitempty" json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time          `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	CreatedBy  primitive.ObjectID  `bson:"created_by" json:"created_by"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+type Comment struct {
+	ID        primitive.ObjectID   `bson:"id" json:"id"`
+	AuthorID  primitive.ObjectID   `bson:"author_id" json:"author_id"`
+	Body      string               `bson:"body" json:"body"`
+	Mentions  []primitive.ObjectID `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	CreatedAt time.Time            `bson:"created_at" json:"created_at"`
+}
+
+const (
+	ShareAccessRead    = "read"
+	ShareAccessComment = "comment"
+)
+
+// ShareLink gives anyone holding its token read or comment access to a
+// schema. Only a hash of the token is stored; Token is set once, when the
+// link is created.
+type ShareLink struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SchemaID     primitive.ObjectID `bson:"schema_id" json:"schema_id"`
+	Token        string             `bson:"-" json:"token,omitempty"`
+	TokenHash    string             `bson:"token_hash" json:"-"`
+	Access       string             `bson:"access" json:"access"`
+	PasswordHash string             `bson:"password_hash,omitempty" json:"-"`
+	HasPassword  bool               `bson:"has_password" json:"has_password"`
+	ExpiresAt    *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	RevokedAt    *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	Views        int64              `bson:"views" json:"views"`
+	LastViewedAt *time.Time         `bson:"last_viewed_at,omitempty" json:"last_viewed_at,omitempty"`
+	CreatedBy    primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type Namespace struct {
+	Name    string `bson:"name" json:"name"`
+	Comment string `bson:"comment,omitempty" json:"comment,omitempty"`
 }
 
 type Table struct {
-	ID          string       `bson:"id" json:"id"`
-	Name        string       `bson:"name" json:"name"`
-	Position    Position     `bson:"position" json:"position"`
-	Fields      []Field      `bson:"fields" json:"fields"`
-	Indexes     []Index      `bson:"indexes,omitempty" json:"indexes,omitempty"`
-	Constraints []Constraint `bson:"constraints,omitempty" json:"constraints,omitempty"`
+	ID          string        `bson:"id" json:"id"`
+	Namespace   string        `bson:"namespace,omitempty" json:"namespace,omitempty"`
+	Name        string        `bson:"name" json:"name"`
+	Description string        `bson:"description,omitempty" json:"description,omitempty"`
+	Position    Position      `bson:"position" json:"position"`
+	Fields      []Field       `bson:"fields" json:"fields"`
+	PrimaryKey  []string      `bson:"primary_key,omitempty" json:"primary_key,omitempty"`
+	Indexes     []Index       `bson:"indexes,omitempty" json:"indexes,omitempty"`
+	Constraints []Constraint  `bson:"constraints,omitempty" json:"constraints,omitempty"`
+	Options     *TableOptions `bson:"options,omitempty" json:"options,omitempty"`
+}
+
+// Engine, Charset and Collation apply to MySQL; Tablespace and Partitioning
+// to PostgreSQL.
+type TableOptions struct {
+	Engine       string        `bson:"engine,omitempty" json:"engine,omitempty"`
+	Charset      string        `bson:"charset,omitempty" json:"charset,omitempty"`
+	Collation    string        `bson:"collation,omitempty" json:"collation,omitempty"`
+	Tablespace   string        `bson:"tablespace,omitempty" json:"tablespace,omitempty"`
+	Partitioning *Partitioning `bson:"partitioning,omitempty" json:"partitioning,omitempty"`
+}
+
+// Bounds are SQL literals, e.g. From: ["'2024-01-01'"] or ["MINVALUE"].
+type Partitioning struct {
+	Strategy   string      `bson:"strategy" json:"strategy"`
+	Fields     []string    `bson:"fields" json:"fields"`
+	Partitions []Partition `bson:"partitions,omitempty" json:"partitions,omitempty"`
+}
+
+type Partition struct {
+	Name       string   `bson:"name" json:"name"`
+	From       []string `bson:"from,omitempty" json:"from,omitempty"`
+	To         []string `bson:"to,omitempty" json:"to,omitempty"`
+	In         []string `bson:"in,omitempty" json:"in,omitempty"`
+	Modulus    int      `bson:"modulus,omitempty" json:"modulus,omitempty"`
+	Remainder  int      `bson:"remainder,omitempty" json:"remainder,omitempty"`
+	IsDefault  bool     `bson:"is_default,omitempty" json:"is_default,omitempty"`
+	Tablespace string   `bson:"tablespace,omitempty" json:"tablespace,omitempty"`
 }
 
 type Field struct {
@@ -130,11 +336,14 @@ type Field struct {
 	IsForeignKey bool       `bson:"is_foreign_key" json:"is_foreign_key"`
 	References   *Reference `bson:"references,omitempty" json:"references,omitempty"`
 	Comment      string     `bson:"comment,omitempty" json:"comment,omitempty"`
+	Charset      string     `bson:"charset,omitempty" json:"charset,omitempty"`
+	Collation    string     `bson:"collation,omitempty" json:"collation,omitempty"`
 }
 
 type Reference struct {
-	TableID string `bson:"table_id" json:"table_id"`
-	FieldID string `bson:"field_id" json:"field_id"`
+	Namespace string `bson:"namespace,omitempty" json:"namespace,omitempty"`
+	TableID   string `bson:"table_id" json:"table_id"`
+	FieldID   string `bson:"field_id" json:"field_id"`
 }
 
 type Position struct {
@@ -142,36 +351,232 @@ type Position struct {
 	Y float64 `bson:"y" json:"y"`
 }
 
+// Type holds the index method (btree, hash, gin, gist, fulltext, spatial...).
+// Fields lists plain field IDs; Columns, when present, takes precedence and
+// allows sort order, collation and expressions per key part.
 type Index struct {
-	Name     string   `bson:"name" json:"name"`
-	Type     string   `bson:"type" json:"type"`
-	Fields   []string `bson:"fields" json:"fields"`
-	IsUnique bool     `bson:"is_unique" json:"is_unique"`
+	Name     string        `bson:"name" json:"name"`
+	Type     string        `bson:"type" json:"type"`
+	Fields   []string      `bson:"fields" json:"fields"`
+	Columns  []IndexColumn `bson:"columns,omitempty" json:"columns,omitempty"`
+	Include  []string      `bson:"include,omitempty" json:"include,omitempty"`
+	Where    string        `bson:"where,omitempty" json:"where,omitempty"`
+	IsUnique bool          `bson:"is_unique" json:"is_unique"`
 }
 
+type IndexColumn struct {
+	FieldID    string `bson:"field_id,omitempty" json:"field_id,omitempty"`
+	Expression string `bson:"expression,omitempty" json:"expression,omitempty"`
+	Order      string `bson:"order,omitempty" json:"order,omitempty"`
+	Nulls      string `bson:"nulls,omitempty" json:"nulls,omitempty"`
+	Collation  string `bson:"collation,omitempty" json:"collation,omitempty"`
+	OpClass    string `bson:"op_class,omitempty" json:"op_class,omitempty"`
+}
+
+// Field and ReferenceField are the single-column forms stored before
+// composite keys existed; they are folded into Fields and ReferenceFields.
 type Constraint struct {
-	Name           string `bson:"name" json:"name"`
-	Type           string `bson:"type" json:"type"`
-	Field          string `bson:"field" json:"field"`
-	ReferenceTable string `bson:"reference_table,omitempty" json:"reference_table,omitempty"`
-	ReferenceField string `bson:"reference_field,omitempty" json:"reference_field,omitempty"`
-	OnUpdate       string `bson:"on_update,omitempty" json:"on_update,omitempty"`
-	OnDelete       string `bson:"on_delete,omitempty" json:"on_delete,omitempty"`
-	CheckCondition string `bson:"check_condition,omitempty" json:"check_condition,omitempty"`
+	Name               string   `bson:"name" json:"name"`
+	Type               string   `bson:"type" json:"type"`
+	Fields             []string `bson:"fields,omitempty" json:"fields,omitempty"`
+	Field              string   `bson:"field,omitempty" json:"field,omitempty"`
+	ReferenceNamespace string   `bson:"reference_namespace,omitempty" json:"reference_namespace,omitempty"`
+	ReferenceTable     string   `bson:"reference_table,omitempty" json:"reference_table,omitempty"`
+	ReferenceFields    []string `bson:"reference_fields,omitempty" json:"reference_fields,omitempty"`
+	ReferenceField     string   `bson:"reference_field,omitempty" json:"reference_field,omitempty"`
+	OnUpdate           string   `bson:"on_update,omitempty" json:"on_update,omitempty"`
+	OnDelete           string   `bson:"on_delete,omitempty" json:"on_delete,omitempty"`
+	CheckCondition     string   `bson:"check_condition,omitempty" json:"check_condition,omitempty"`
+}
+
+type Enum struct {
+	ID        string   `bson:"id" json:"id"`
+	Namespace string   `bson:"namespace,omitempty" json:"namespace,omitempty"`
+	Name      string   `bson:"name" json:"name"`
+	Values    []string `bson:"values" json:"values"`
+	Comment   string   `bson:"comment,omitempty" json:"comment,omitempty"`
+}
+
+type View struct {
+	ID         string `bson:"id" json:"id"`
+	Namespace  string `bson:"namespace,omitempty" json:"namespace,omitempty"`
+	Name       string `bson:"name" json:"name"`
+	Definition string `bson:"definition" json:"definition"`
+	Comment    string `bson:"comment,omitempty" json:"comment,omitempty"`
 }
 
 type CreateSchemaRequest struct {
-	Name        string  `json:"name" validate:"required,min=1,max=100"`
-	Description string  `json:"description" validate:"omitempty,max=500"`
-	Tables      []Table `json:"tables" validate:"omitempty,dive"`
-	IsPublic    bool    `json:"is_public"`
+	Name         string      `json:"name" validate:"required,min=1,max=100"`
+	Description  string      `json:"description" validate:"omitempty,max=500"`
+	DatabaseType string      `json:"database_type" validate:"omitempty,oneof=postgresql mysql sqlite graphql"`
+	Namespaces   []Namespace `json:"namespaces" validate:"omitempty,dive"`
+	Tables       []Table     `json:"tables" validate:"omitempty,dive"`
+	Enums        []Enum      `json:"enums" validate:"omitempty,dive"`
+	Views        []View      `json:"views" validate:"omitempty,dive"`
+	IsPublic     bool        `json:"is_public"`
 }
 
 type UpdateSchemaRequest struct {
-	Name        string  `json:"name" validate:"omitempty,min=1,max=100"`
-	Description string  `json:"description" validate:"omitempty,max=500"`
-	Tables      []Table `json:"tables" validate:"omitempty,dive"`
-	IsPublic    *bool   `json:"is_public" validate:"omitempty"`
+	Name         string      `json:"name" validate:"omitempty,min=1,max=100"`
+	Description  string      `json:"description" validate:"omitempty,max=500"`
+	DatabaseType string      `json:"database_type" validate:"omitempty,oneof=postgresql mysql sqlite graphql"`
+	Namespaces   []Namespace `json:"namespaces" validate:"omitempty,dive"`
+	Tables       []Table     `json:"tables" validate:"omitempty,dive"`
+	Enums        []Enum      `json:"enums" validate:"omitempty,dive"`
+	Views        []View      `json:"views" validate:"omitempty,dive"`
+	IsPublic     *bool       `json:"is_public" validate:"omitempty"`
+}
+
+type ImportSchemaRequest struct {
+	Name        string `json:"name" validate:"omitempty,min=1,max=100"`
+	Description string `json:"description" validate:"omitempty,max=500"`
+	Content     string `json:"content" validate:"required"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// ApplyNormalizationRequest selects suggestions by ID from a normalization
+// report. Version, when set, must match the schema version the report was
+// made for.
+type ApplyNormalizationRequest struct {
+	Version     int      `json:"version" validate:"omitempty,min=1"`
+	Suggestions []string `json:"suggestions" validate:"required,min=1,dive,required"`
+}
+
+type CreateBranchRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+// ResolveMergeRequest picks a side for merge conflicts by conflict ID.
+type ResolveMergeRequest struct {
+	Resolutions []MergeResolution `json:"resolutions" validate:"required,min=1,dive"`
+}
+
+type MergeResolution struct {
+	ConflictID string `json:"conflict_id" validate:"required"`
+	Choice     string `json:"choice" validate:"required,oneof=target branch"`
+}
+
+// CompleteMergeRequest versions, when set, must match the ones the merge
+// preview was made for.
+type CompleteMergeRequest struct {
+	TargetVersion int `json:"target_version" validate:"omitempty,min=1"`
+	BranchVersion int `json:"branch_version" validate:"omitempty,min=1"`
+}
+
+// CreateChangeRequestRequest proposes tables against BaseVersion, the
+// current version when omitted. Reviewers are given by email.
+type CreateChangeRequestRequest struct {
+	Title       string   `json:"title" validate:"required,min=1,max=200"`
+	Description string   `json:"description" validate:"omitempty,max=5000"`
+	BaseVersion int      `json:"base_version" validate:"omitempty,min=1"`
+	Tables      []Table  `json:"tables" validate:"required,dive"`
+	Reviewers   []string `json:"reviewers" validate:"omitempty,max=20,dive,required,email"`
+}
+
+// UpdateChangeRequestRequest edits a change request. New tables start a new
+// revision, optionally against a newer BaseVersion; Reviewers, when set,
+// replace the reviewer list.
+type UpdateChangeRequestRequest struct {
+	Title       string   `json:"title" validate:"omitempty,min=1,max=200"`
+	Description string   `json:"description" validate:"omitempty,max=5000"`
+	BaseVersion int      `json:"base_version" validate:"omitempty,min=1"`
+	Tables      []Table  `json:"tables" validate:"omitempty,dive"`
+	Reviewers   []string `json:"reviewers" validate:"omitempty,max=20,dive,required,email"`
+}
+
+type ReviewChangeRequestRequest struct {
+	State string `json:"state" validate:"required,oneof=commented approved changes_requested"`
+	Body  string `json:"body" validate:"omitempty,max=5000"`
+}
+
+// CreateCommentThreadRequest starts a thread on the schema, or on a table
+// when TableID is set, or on a field of it when FieldID is set too.
+type CreateCommentThreadRequest struct {
+	TableID string `json:"table_id" validate:"omitempty,max=100"`
+	FieldID string `json:"field_id" validate:"omitempty,max=100"`
+	Body    string `json:"body" validate:"required,min=1,max=5000"`
+}
+
+type AddCommentRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=5000"`
+}
+
+// SearchSchemasRequest is read from the query string. Owner is a username,
+// or "me" for the caller's own schemas.
+type SearchSchemasRequest struct {
+	Query     string `form:"q" validate:"omitempty,max=200"`
+	Owner     string `form:"owner" validate:"omitempty,max=30"`
+	HasTable  string `form:"has_table" validate:"omitempty,max=100"`
+	MinTables int    `form:"min_tables" validate:"omitempty,min=0"`
+	MaxTables int    `form:"max_tables" validate:"omitempty,min=0"`
+	Page      int    `form:"page" validate:"omitempty,min=1"`
+	Limit     int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type CreateFolderRequest struct {
+	Name     string `json:"name" validate:"required,min=1,max=100"`
+	ParentID string `json:"parent_id" validate:"omitempty,len=24,hexadecimal"`
+}
+
+// UpdateFolderRequest renames or moves a folder. A ParentID of "" moves the
+// folder to the root; leaving it out keeps the folder where it is.
+type UpdateFolderRequest struct {
+	Name     string  `json:"name" validate:"omitempty,min=1,max=100"`
+	ParentID *string `json:"parent_id" validate:"omitempty,max=24"`
+}
+
+type SetSchemaTagsRequest struct {
+	Tags []string `json:"tags" validate:"max=20,dive,required,max=50"`
+}
+
+// MoveSchemasRequest moves schemas into a folder, or to the root when
+// FolderID is empty.
+type MoveSchemasRequest struct {
+	SchemaIDs []string `json:"schema_ids" validate:"required,min=1,max=100,dive,len=24,hexadecimal"`
+	FolderID  string   `json:"folder_id" validate:"omitempty,len=24,hexadecimal"`
+}
+
+type TagSchemasRequest struct {
+	SchemaIDs []string `json:"schema_ids" validate:"required,min=1,max=100,dive,len=24,hexadecimal"`
+	Add       []string `json:"add" validate:"max=20,dive,required,max=50"`
+	Remove    []string `json:"remove" validate:"max=20,dive,required,max=50"`
+}
+
+type RenameTagRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=50"`
+}
+
+type ForkSchemaRequest struct {
+	Name string `json:"name" validate:"omitempty,min=1,max=100"`
+}
+
+// PullUpstreamRequest merges upstream changes into a fork. UpstreamVersion,
+// when set, has to match the upstream version the pull was previewed for.
+// A "branch" resolution takes the upstream's side, "target" the fork's.
+type PullUpstreamRequest struct {
+	UpstreamVersion int               `json:"upstream_version" validate:"omitempty,min=1"`
+	Resolutions     []MergeResolution `json:"resolutions" validate:"omitempty,dive"`
+}
+
+type CreateShareLinkRequest struct {
+	Access    string     `json:"access" validate:"required,oneof=read comment"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Password  string     `json:"password" validate:"omitempty,min=4,max=128"`
+}
+
+// SeedDataRequest asks for generated rows. Rows maps table IDs or names to
+// row counts; unlisted tables get DefaultRows.
+type SeedDataRequest struct {
+	Format      string         `json:"format" validate:"omitempty,max=20"`
+	Seed        int64          `json:"seed"`
+	Rows        map[string]int `json:"rows" validate:"omitempty,dive,min=0,max=10000"`
+	DefaultRows int            `json:"default_rows" validate:"omitempty,min=0,max=10000"`
+}
+
+// VerifyDDLRequest selects the dialects to execute; empty means SQLite
+// plus every configured server.
+type VerifyDDLRequest struct {
+	Dialects []string `json:"dialects" validate:"omitempty,max=3,dive,required,max=20"`
 }
 
 type ErrorResponse struct {
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/database"
+)
+
+type changeRequestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewChangeRequestRepository(db *database.MongoDB) ChangeRequestRepository {
+	return &changeRequestRepository{
+		collection: db.GetCollection("change_requests"),
+	}
+}
+
+func (r *changeRequestRepository) Create(ctx context.Context, changeRequest *models.ChangeRequest) error {
+	changeRequest.CreatedAt = time.Now()
+	changeRequest.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, changeRequest)
+	if err != nil {
+		return fmt.Errorf("failed to create change request: %v", err)
+	}
+
+	changeRequest.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *changeRequestRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.ChangeRequest, error) {
+	var changeRequest models.ChangeRequest
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&changeRequest)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("change request not found")
+		}
+		return nil, fmt.Errorf("failed to get change request: %v", err)
+	}
+
+	return &changeRequest, nil
+}
+
+// ListBySchema returns the change requests of a schema, newest first,
+// without their proposed tables. An empty status lists all of them.
+func (r *changeRequestRepository) ListBySchema(ctx context.Context, schemaID primitive.ObjectID, status string) ([]*models.ChangeRequest, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetProjection(bson.M{"tables": 0})
+
+	filter := bson.M{"schema_id": schemaID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find change requests: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var changeRequests []*models.ChangeRequest
+	if err := cursor.All(ctx, &changeRequests); err != nil {
+		return nil, fmt.Errorf("failed to decode change requests: %v", err)
+	}
+
+	return changeRequests, nil
+}
+
+// Update saves the editable parts of an open change request.
+func (r *changeRequestRepository) Update(ctx context.Context, changeRequest *models.ChangeRequest) error {
+	changeRequest.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{
+		"title":       changeRequest.Title,
+		"description": changeRequest.Description,
+		"revision":    changeRequest.Revision,
+		"tables":      changeRequest.Tables,
+		"reviewers":   changeRequest.Reviewers,
+		"updated_at":  changeRequest.UpdatedAt,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": changeRequest.ID, "status": models.ChangeRequestOpen}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update change request: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("change request is not open")
+	}
+
+	return nil
+}
+
+func (r *changeRequestRepository) AddReview(ctx context.Context, id primitive.ObjectID, review *models.ChangeRequestReview) error {
+	review.CreatedAt = time.Now()
+	update := bson.M{
+		"$push": bson.M{"reviews": review},
+		"$set":  bson.M{"updated_at": review.CreatedAt},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "status": models.ChangeRequestOpen}, update)
+	if err != nil {
+		return fmt.Errorf("failed to add review: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("change request is not open")
+	}
+
+	return nil
+}
+
+// SetStatus moves an open change request to merged or closed.
+func (r *changeRequestRepository) SetStatus(ctx context.Context, id primitive.ObjectID, status string, mergedVersion int) error {
+	set := bson.M{"status": status, "updated_at": time.Now()}
+	if mergedVersion > 0 {
+		set["merged_version"] = mergedVersion
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "status": models.ChangeRequestOpen}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update change request: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("change request is not open")
+	}
+
+	return nil
+}
+
+func (r *changeRequestRepository) DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"schema_id": schemaID}); err != nil {
+		return fmt.Errorf("failed to delete change requests: %v", err)
+	}
+
+	return nil
+}
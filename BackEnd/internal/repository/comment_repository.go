@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/database"
+)
+
+// CommentThreadFilter selects the threads of a schema. Resolved, TableID and
+// FieldID narrow the selection when set.
+type CommentThreadFilter struct {
+	SchemaID primitive.ObjectID
+	Resolved *bool
+	TableID  string
+	FieldID  string
+}
+
+type commentThreadRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentThreadRepository(db *database.MongoDB) CommentThreadRepository {
+	return &commentThreadRepository{
+		collection: db.GetCollection("comment_threads"),
+	}
+}
+
+func (r *commentThreadRepository) Create(ctx context.Context, thread *models.CommentThread) error {
+	thread.CreatedAt = time.Now()
+	thread.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, thread)
+	if err != nil {
+		return fmt.Errorf("failed to create comment thread: %v", err)
+	}
+
+	thread.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *commentThreadRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.CommentThread, error) {
+	var thread models.CommentThread
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&thread)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("comment thread not found")
+		}
+		return nil, fmt.Errorf("failed to get comment thread: %v", err)
+	}
+
+	return &thread, nil
+}
+
+// List returns the matching threads, most recently active first.
+func (r *commentThreadRepository) List(ctx context.Context, filter CommentThreadFilter) ([]*models.CommentThread, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+
+	query := bson.M{"schema_id": filter.SchemaID}
+	if filter.Resolved != nil {
+		query["resolved"] = *filter.Resolved
+	}
+	if filter.TableID != "" {
+		query["table_id"] = filter.TableID
+	}
+	if filter.FieldID != "" {
+		query["field_id"] = filter.FieldID
+	}
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comment threads: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var threads []*models.CommentThread
+	if err := cursor.All(ctx, &threads); err != nil {
+		return nil, fmt.Errorf("failed to decode comment threads: %v", err)
+	}
+
+	return threads, nil
+}
+
+func (r *commentThreadRepository) AddComment(ctx context.Context, id primitive.ObjectID, comment *models.Comment) error {
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+	update := bson.M{
+		"$push": bson.M{"comments": comment},
+		"$set":  bson.M{"updated_at": comment.CreatedAt},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to add comment: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("comment thread not found")
+	}
+
+	return nil
+}
+
+func (r *commentThreadRepository) SetResolved(ctx context.Context, id primitive.ObjectID, resolved bool, userID primitive.ObjectID) error {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{
+		"resolved":    true,
+		"resolved_by": userID,
+		"resolved_at": now,
+		"updated_at":  now,
+	}}
+	if !resolved {
+		update = bson.M{
+			"$set":   bson.M{"resolved": false, "updated_at": now},
+			"$unset": bson.M{"resolved_by": "", "resolved_at": ""},
+		}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update comment thread: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("comment thread not found")
+	}
+
+	return nil
+}
+
+func (r *commentThreadRepository) DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"schema_id": schemaID}); err != nil {
+		return fmt.Errorf("failed to delete comment threads: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/database"
+)
+
+type folderRepository struct {
+	collection *mongo.Collection
+}
+
+func NewFolderRepository(db *database.MongoDB) FolderRepository {
+	return &folderRepository{
+		collection: db.GetCollection("schema_folders"),
+	}
+}
+
+func (r *folderRepository) Create(ctx context.Context, folder *models.Folder) error {
+	folder.CreatedAt = time.Now()
+	folder.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, folder)
+	if err != nil {
+		return fmt.Errorf("failed to create folder: %v", err)
+	}
+
+	folder.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *folderRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Folder, error) {
+	var folder models.Folder
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&folder)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("folder not found")
+		}
+		return nil, fmt.Errorf("failed to get folder: %v", err)
+	}
+
+	return &folder, nil
+}
+
+func (r *folderRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]*models.Folder, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find folders: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var folders []*models.Folder
+	if err := cursor.All(ctx, &folders); err != nil {
+		return nil, fmt.Errorf("failed to decode folders: %v", err)
+	}
+
+	return folders, nil
+}
+
+func (r *folderRepository) Update(ctx context.Context, folder *models.Folder) error {
+	folder.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{"name": folder.Name, "updated_at": folder.UpdatedAt}}
+	if folder.ParentID != nil {
+		update["$set"].(bson.M)["parent_id"] = *folder.ParentID
+	} else {
+		update["$unset"] = bson.M{"parent_id": ""}
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": folder.ID}, update); err != nil {
+		return fmt.Errorf("failed to update folder: %v", err)
+	}
+
+	return nil
+}
+
+func (r *folderRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("failed to delete folder: %v", err)
+	}
+
+	return nil
+}
+
+// Reparent moves the subfolders of one folder to another, or to the root.
+func (r *folderRepository) Reparent(ctx context.Context, userID primitive.ObjectID, from primitive.ObjectID, to *primitive.ObjectID) error {
+	update := bson.M{"$unset": bson.M{"parent_id": ""}}
+	if to != nil {
+		update = bson.M{"$set": bson.M{"parent_id": *to}}
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, bson.M{"user_id": userID, "parent_id": from}, update); err != nil {
+		return fmt.Errorf("failed to move folders: %v", err)
+	}
+
+	return nil
+}
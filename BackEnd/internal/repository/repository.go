@@ -30,21 +30,112 @@ type UserRepository interface {
 type SchemaRepository interface {
 	Create(ctx context.Context, schema *models.Schema) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*models.Schema, error)
-	GetByUserID(ctx context.Context, userID primitive.ObjectID, page, limit int) ([]*models.Schema, int64, error)
+	GetByUserID(ctx context.Context, userID primitive.ObjectID, page, limit int, filter SchemaListFilter) ([]*models.Schema, int64, error)
 	Update(ctx context.Context, id primitive.ObjectID, update *models.UpdateSchemaRequest) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
-	GetPublicSchemas(ctx context.Context, page, limit int) ([]*models.Schema, int64, error)
+	GetPublicSchemas(ctx context.Context, page, limit int, sort string) ([]*models.Schema, int64, error)
 	GetOtherUsersSchemas(ctx context.Context, excludeUserID primitive.ObjectID, page, limit int) ([]*models.Schema, int64, error)
+	ForEach(ctx context.Context, fn func(schema *models.Schema) error) error
+	ReplaceTables(ctx context.Context, id primitive.ObjectID, tables []models.Table) error
+	ListForks(ctx context.Context, id primitive.ObjectID, viewerID primitive.ObjectID) ([]*models.Schema, error)
+	SetForkSync(ctx context.Context, id primitive.ObjectID, syncedVersion int) error
+	AdjustCount(ctx context.Context, id primitive.ObjectID, field string, delta int) error
+	EnsureSearchIndex(ctx context.Context) error
+	Search(ctx context.Context, query SchemaSearchQuery, page, limit int) ([]*SchemaSearchHit, int64, error)
+	SetTags(ctx context.Context, id primitive.ObjectID, tags []string) error
+	AddTags(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, tags []string) (int64, error)
+	RemoveTags(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, tags []string) (int64, error)
+	RenameTag(ctx context.Context, userID primitive.ObjectID, from, to string) (int64, error)
+	CountByTag(ctx context.Context, userID primitive.ObjectID) (map[string]int64, error)
+	MoveToFolder(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, folderID *primitive.ObjectID) (int64, error)
+	ReassignFolder(ctx context.Context, userID primitive.ObjectID, from primitive.ObjectID, to *primitive.ObjectID) error
+	CountByFolder(ctx context.Context, userID primitive.ObjectID) (map[primitive.ObjectID]int64, error)
+}
+
+type SchemaVersionRepository interface {
+	Save(ctx context.Context, version *models.SchemaVersion) error
+	Get(ctx context.Context, schemaID primitive.ObjectID, version int) (*models.SchemaVersion, error)
+	List(ctx context.Context, schemaID primitive.ObjectID) ([]*models.SchemaVersion, error)
+	DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error
+}
+
+type SchemaBranchRepository interface {
+	Create(ctx context.Context, branch *models.SchemaBranch) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.SchemaBranch, error)
+	ListBySchema(ctx context.Context, schemaID primitive.ObjectID) ([]*models.SchemaBranch, error)
+	SetResolutions(ctx context.Context, id primitive.ObjectID, resolutions map[string]string) error
+	MarkMerged(ctx context.Context, id primitive.ObjectID, version int) error
+	DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error
+}
+
+type ChangeRequestRepository interface {
+	Create(ctx context.Context, changeRequest *models.ChangeRequest) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.ChangeRequest, error)
+	ListBySchema(ctx context.Context, schemaID primitive.ObjectID, status string) ([]*models.ChangeRequest, error)
+	Update(ctx context.Context, changeRequest *models.ChangeRequest) error
+	AddReview(ctx context.Context, id primitive.ObjectID, review *models.ChangeRequestReview) error
+	SetStatus(ctx context.Context, id primitive.ObjectID, status string, mergedVersion int) error
+	DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error
+}
+
+type CommentThreadRepository interface {
+	Create(ctx context.Context, thread *models.CommentThread) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.CommentThread, error)
+	List(ctx context.Context, filter CommentThreadFilter) ([]*models.CommentThread, error)
+	AddComment(ctx context.Context, id primitive.ObjectID, comment *models.Comment) error
+	SetResolved(ctx context.Context, id primitive.ObjectID, resolved bool, userID primitive.ObjectID) error
+	DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error
+}
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *models.ShareLink) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.ShareLink, error)
+	ListBySchema(ctx context.Context, schemaID primitive.ObjectID) ([]*models.ShareLink, error)
+	Revoke(ctx context.Context, id, schemaID primitive.ObjectID) error
+	RecordView(ctx context.Context, id primitive.ObjectID) error
+	DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error
+	EnsureTokenIndex(ctx context.Context) error
+}
+
+type SchemaStarRepository interface {
+	Add(ctx context.Context, schemaID, userID primitive.ObjectID) (bool, error)
+	Remove(ctx context.Context, schemaID, userID primitive.ObjectID) (bool, error)
+	IsStarred(ctx context.Context, schemaID, userID primitive.ObjectID) (bool, error)
+	DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error
+	EnsureIndex(ctx context.Context) error
+}
+
+type FolderRepository interface {
+	Create(ctx context.Context, folder *models.Folder) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.Folder, error)
+	ListByUser(ctx context.Context, userID primitive.ObjectID) ([]*models.Folder, error)
+	Update(ctx context.Context, folder *models.Folder) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	Reparent(ctx context.Context, userID primitive.ObjectID, from primitive.ObjectID, to *primitive.ObjectID) error
 }
 
 type Repositories struct {
-	User   UserRepository
-	Schema SchemaRepository
+	User          UserRepository
+	Schema        SchemaRepository
+	SchemaVersion SchemaVersionRepository
+	SchemaBranch  SchemaBranchRepository
+	ChangeRequest ChangeRequestRepository
+	CommentThread CommentThreadRepository
+	ShareLink     ShareLinkRepository
+	SchemaStar    SchemaStarRepository
+	Folder        FolderRepository
 }
 
 func NewRepositories(db *database.MongoDB) *Repositories {
 	return &Repositories{
-		User:   NewUserRepository(db),
-		Schema: NewSchemaRepository(db),
+		User:          NewUserRepository(db),
+		Schema:        NewSchemaRepository(db),
+		SchemaVersion: NewSchemaVersionRepository(db),
+		SchemaBranch:  NewSchemaBranchRepository(db),
+		ChangeRequest: NewChangeRequestRepository(db),
+		CommentThread: NewCommentThreadRepository(db),
+		ShareLink:     NewShareLinkRepository(db),
+		SchemaStar:    NewSchemaStarRepository(db),
+		Folder:        NewFolderRepository(db),
 	}
 }
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/database"
+)
+
+type schemaBranchRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSchemaBranchRepository(db *database.MongoDB) SchemaBranchRepository {
+	return &schemaBranchRepository{
+		collection: db.GetCollection("schema_branches"),
+	}
+}
+
+func (r *schemaBranchRepository) Create(ctx context.Context, branch *models.SchemaBranch) error {
+	branch.CreatedAt = time.Now()
+	branch.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, branch)
+	if err != nil {
+		return fmt.Errorf("failed to create schema branch: %v", err)
+	}
+
+	branch.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *schemaBranchRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.SchemaBranch, error) {
+	var branch models.SchemaBranch
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&branch)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("schema branch not found")
+		}
+		return nil, fmt.Errorf("failed to get schema branch: %v", err)
+	}
+
+	return &branch, nil
+}
+
+func (r *schemaBranchRepository) ListBySchema(ctx context.Context, schemaID primitive.ObjectID) ([]*models.SchemaBranch, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"schema_id": schemaID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schema branches: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var branches []*models.SchemaBranch
+	if err := cursor.All(ctx, &branches); err != nil {
+		return nil, fmt.Errorf("failed to decode schema branches: %v", err)
+	}
+
+	return branches, nil
+}
+
+func (r *schemaBranchRepository) SetResolutions(ctx context.Context, id primitive.ObjectID, resolutions map[string]string) error {
+	update := bson.M{"$set": bson.M{"resolutions": resolutions, "updated_at": time.Now()}}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update schema branch: %v", err)
+	}
+
+	return nil
+}
+
+// MarkMerged closes an open branch. It fails if the branch was merged in
+// the meantime, so a merge is applied at most once.
+func (r *schemaBranchRepository) MarkMerged(ctx context.Context, id primitive.ObjectID, version int) error {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{
+		"status":         models.BranchStatusMerged,
+		"merged_version": version,
+		"merged_at":      now,
+		"updated_at":     now,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "status": models.BranchStatusOpen}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update schema branch: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("schema branch is not open")
+	}
+
+	return nil
+}
+
+// DeleteBySchema removes the branches of a schema and the record of a
+// schema that is itself a branch.
+func (r *schemaBranchRepository) DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error {
+	filter := bson.M{"$or": []bson.M{{"schema_id": schemaID}, {"branch_schema_id": schemaID}}}
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete schema branches: %v", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -51,7 +52,15 @@ func (r *schemaRepository) GetByID(ctx context.Context, id primitive.ObjectID) (
 	return &schema, nil
 }
 
-func (r *schemaRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID, page, limit int) ([]*models.Schema, int64, error) {
+// SchemaListFilter narrows a user's schemas to those with a tag or in a
+// folder. Unfiled selects the schemas in no folder.
+type SchemaListFilter struct {
+	Tag      string
+	FolderID *primitive.ObjectID
+	Unfiled  bool
+}
+
+func (r *schemaRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID, page, limit int, listFilter SchemaListFilter) ([]*models.Schema, int64, error) {
 	skip := (page - 1) * limit
 
 	opts := options.Find().
@@ -60,6 +69,14 @@ func (r *schemaRepository) GetByUserID(ctx context.Context, userID primitive.Obj
 		SetLimit(int64(limit))
 
 	filter := bson.M{"user_id": userID}
+	if listFilter.Tag != "" {
+		filter["tags"] = listFilter.Tag
+	}
+	if listFilter.FolderID != nil {
+		filter["folder_id"] = *listFilter.FolderID
+	} else if listFilter.Unfiled {
+		filter["folder_id"] = nil
+	}
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -90,10 +107,25 @@ func (r *schemaRepository) Update(ctx context.Context, id primitive.ObjectID, up
 	if update.Description != "" {
 		updateDoc["description"] = update.Description
 	}
+	if update.DatabaseType != "" {
+		updateDoc["database_type"] = update.DatabaseType
+	}
+	if update.Namespaces != nil {
+		updateDoc["namespaces"] = update.Namespaces
+		updateOps["$inc"] = bson.M{"version": 1}
+	}
 	if update.Tables != nil {
 		updateDoc["tables"] = update.Tables
 		updateOps["$inc"] = bson.M{"version": 1}
 	}
+	if update.Enums != nil {
+		updateDoc["enums"] = update.Enums
+		updateOps["$inc"] = bson.M{"version": 1}
+	}
+	if update.Views != nil {
+		updateDoc["views"] = update.Views
+		updateOps["$inc"] = bson.M{"version": 1}
+	}
 	if update.IsPublic != nil {
 		updateDoc["is_public"] = *update.IsPublic
 	}
@@ -119,11 +151,21 @@ func (r *schemaRepository) Delete(ctx context.Context, id primitive.ObjectID) er
 	return nil
 }
 
-func (r *schemaRepository) GetPublicSchemas(ctx context.Context, page, limit int) ([]*models.Schema, int64, error) {
+// GetPublicSchemas pages through public schemas, most recently updated
+// first or, with sort "stars" or "forks", most starred or forked first.
+func (r *schemaRepository) GetPublicSchemas(ctx context.Context, page, limit int, sort string) ([]*models.Schema, int64, error) {
 	skip := (page - 1) * limit
 
+	order := bson.D{{Key: "updated_at", Value: -1}}
+	switch sort {
+	case "stars":
+		order = append(bson.D{{Key: "star_count", Value: -1}}, order...)
+	case "forks":
+		order = append(bson.D{{Key: "fork_count", Value: -1}}, order...)
+	}
+
 	opts := options.Find().
-		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetSort(order).
 		SetSkip(int64(skip)).
 		SetLimit(int64(limit))
 
@@ -179,3 +221,349 @@ func (r *schemaRepository) GetOtherUsersSchemas(ctx context.Context, excludeUser
 
 	return schemas, total, nil
 }
+
+func (r *schemaRepository) ForEach(ctx context.Context, fn func(schema *models.Schema) error) error {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to find schemas: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var schema models.Schema
+		if err := cursor.Decode(&schema); err != nil {
+			return fmt.Errorf("failed to decode schema: %v", err)
+		}
+		if err := fn(&schema); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate schemas: %v", err)
+	}
+
+	return nil
+}
+
+// ReplaceTables rewrites stored tables in place without bumping the version,
+// for data migrations that do not change what the schema describes.
+func (r *schemaRepository) ReplaceTables(ctx context.Context, id primitive.ObjectID, tables []models.Table) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"tables": tables}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to replace schema tables: %v", err)
+	}
+
+	return nil
+}
+
+// ListForks returns the direct forks of a schema the viewer can see.
+func (r *schemaRepository) ListForks(ctx context.Context, id primitive.ObjectID, viewerID primitive.ObjectID) ([]*models.Schema, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "star_count", Value: -1}, {Key: "updated_at", Value: -1}}).
+		SetProjection(bson.M{"tables": 0, "namespaces": 0, "enums": 0, "views": 0})
+
+	filter := bson.M{
+		"forked_from.schema_id": id,
+		"$or":                   bson.A{bson.M{"is_public": true}, bson.M{"user_id": viewerID}},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find forks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var schemas []*models.Schema
+	if err := cursor.All(ctx, &schemas); err != nil {
+		return nil, fmt.Errorf("failed to decode schemas: %v", err)
+	}
+
+	return schemas, nil
+}
+
+func (r *schemaRepository) SetForkSync(ctx context.Context, id primitive.ObjectID, syncedVersion int) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"forked_from.synced_version": syncedVersion}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update fork: %v", err)
+	}
+
+	return nil
+}
+
+// AdjustCount adds delta to a counter such as fork_count or star_count
+// without touching updated_at.
+func (r *schemaRepository) AdjustCount(ctx context.Context, id primitive.ObjectID, field string, delta int) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{field: delta}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %v", field, err)
+	}
+
+	return nil
+}
+
+// SchemaSearchQuery searches the schemas the viewer can see. Text is matched
+// against the search index; the other fields filter when set.
+type SchemaSearchQuery struct {
+	Text      string
+	ViewerID  primitive.ObjectID
+	OwnerID   *primitive.ObjectID
+	MinTables int
+	MaxTables int
+	HasTable  string
+}
+
+type SchemaSearchHit struct {
+	Schema *models.Schema
+	Score  float64
+}
+
+// EnsureSearchIndex creates the text index Search relies on. Names weigh
+// more than descriptions and comments.
+func (r *schemaRepository) EnsureSearchIndex(ctx context.Context) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "tables.name", Value: "text"},
+			{Key: "tables.description", Value: "text"},
+			{Key: "tables.fields.name", Value: "text"},
+			{Key: "tables.fields.comment", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("schema_search").
+			SetWeights(bson.D{
+				{Key: "name", Value: 10},
+				{Key: "tables.name", Value: 5},
+				{Key: "tables.fields.name", Value: 2},
+				{Key: "description", Value: 2},
+				{Key: "tables.description", Value: 1},
+				{Key: "tables.fields.comment", Value: 1},
+			}),
+	}
+
+	if _, err := r.collection.Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create schema search index: %v", err)
+	}
+
+	return nil
+}
+
+// Search returns matching schemas, most relevant first when searching by
+// text and most starred first otherwise.
+func (r *schemaRepository) Search(ctx context.Context, query SchemaSearchQuery, page, limit int) ([]*SchemaSearchHit, int64, error) {
+	skip := (page - 1) * limit
+
+	conditions := bson.A{
+		bson.M{"$or": bson.A{bson.M{"is_public": true}, bson.M{"user_id": query.ViewerID}}},
+	}
+	if query.OwnerID != nil {
+		conditions = append(conditions, bson.M{"user_id": *query.OwnerID})
+	}
+	if query.HasTable != "" {
+		conditions = append(conditions, bson.M{"tables.name": bson.M{
+			"$regex":   "^" + regexp.QuoteMeta(query.HasTable) + "$",
+			"$options": "i",
+		}})
+	}
+	tableCount := bson.M{"$size": bson.M{"$ifNull": bson.A{"$tables", bson.A{}}}}
+	if query.MinTables > 0 {
+		conditions = append(conditions, bson.M{"$expr": bson.M{"$gte": bson.A{tableCount, query.MinTables}}})
+	}
+	if query.MaxTables > 0 {
+		conditions = append(conditions, bson.M{"$expr": bson.M{"$lte": bson.A{tableCount, query.MaxTables}}})
+	}
+
+	filter := bson.M{"$and": conditions}
+	opts := options.Find().SetSkip(int64(skip)).SetLimit(int64(limit))
+	if query.Text != "" {
+		filter["$text"] = bson.M{"$search": query.Text}
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+			SetSort(bson.D{
+				{Key: "score", Value: bson.M{"$meta": "textScore"}},
+				{Key: "star_count", Value: -1},
+				{Key: "updated_at", Value: -1},
+			})
+	} else {
+		opts.SetSort(bson.D{{Key: "star_count", Value: -1}, {Key: "updated_at", Value: -1}})
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search schemas: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		models.Schema `bson:",inline"`
+		Score         float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode schemas: %v", err)
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count schemas: %v", err)
+	}
+
+	hits := make([]*SchemaSearchHit, 0, len(docs))
+	for i := range docs {
+		hits = append(hits, &SchemaSearchHit{Schema: &docs[i].Schema, Score: docs[i].Score})
+	}
+	return hits, total, nil
+}
+
+// Tags and folders organize schemas without changing them, so the updates
+// below leave updated_at and the version alone.
+
+func (r *schemaRepository) SetTags(ctx context.Context, id primitive.ObjectID, tags []string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"tags": tags}})
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %v", err)
+	}
+
+	return nil
+}
+
+func (r *schemaRepository) AddTags(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, tags []string) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "user_id": userID},
+		bson.M{"$addToSet": bson.M{"tags": bson.M{"$each": tags}}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add tags: %v", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *schemaRepository) RemoveTags(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, tags []string) (int64, error) {
+	filter := bson.M{"user_id": userID}
+	if ids != nil {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{"$pull": bson.M{"tags": bson.M{"$in": tags}}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove tags: %v", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// RenameTag renames a tag on all of a user's schemas, merging it into the
+// new name where a schema has both.
+func (r *schemaRepository) RenameTag(ctx context.Context, userID primitive.ObjectID, from, to string) (int64, error) {
+	filter := bson.M{"user_id": userID, "tags": from}
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{"$addToSet": bson.M{"tags": to}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to rename tag: %v", err)
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, filter, bson.M{"$pull": bson.M{"tags": from}}); err != nil {
+		return 0, fmt.Errorf("failed to rename tag: %v", err)
+	}
+
+	return result.MatchedCount, nil
+}
+
+func (r *schemaRepository) CountByTag(ctx context.Context, userID primitive.ObjectID) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+	}
+
+	var groups []struct {
+		Tag   string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := r.aggregate(ctx, pipeline, &groups); err != nil {
+		return nil, fmt.Errorf("failed to count tags: %v", err)
+	}
+
+	counts := make(map[string]int64, len(groups))
+	for _, group := range groups {
+		counts[group.Tag] = group.Count
+	}
+	return counts, nil
+}
+
+func (r *schemaRepository) MoveToFolder(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, folderID *primitive.ObjectID) (int64, error) {
+	update := bson.M{"$unset": bson.M{"folder_id": ""}}
+	if folderID != nil {
+		update = bson.M{"$set": bson.M{"folder_id": *folderID}}
+	}
+
+	result, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "user_id": userID}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move schemas: %v", err)
+	}
+
+	return result.MatchedCount, nil
+}
+
+// ReassignFolder moves all schemas in one folder to another, or to the root.
+func (r *schemaRepository) ReassignFolder(ctx context.Context, userID primitive.ObjectID, from primitive.ObjectID, to *primitive.ObjectID) error {
+	update := bson.M{"$unset": bson.M{"folder_id": ""}}
+	if to != nil {
+		update = bson.M{"$set": bson.M{"folder_id": *to}}
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, bson.M{"user_id": userID, "folder_id": from}, update); err != nil {
+		return fmt.Errorf("failed to move schemas: %v", err)
+	}
+
+	return nil
+}
+
+// CountByFolder counts a user's schemas per folder. Schemas in no folder are
+// counted under the zero ObjectID.
+func (r *schemaRepository) CountByFolder(ctx context.Context, userID primitive.ObjectID) (map[primitive.ObjectID]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$group", Value: bson.M{"_id": "$folder_id", "count": bson.M{"$sum": 1}}}},
+	}
+
+	var groups []struct {
+		FolderID *primitive.ObjectID `bson:"_id"`
+		Count    int64               `bson:"count"`
+	}
+	if err := r.aggregate(ctx, pipeline, &groups); err != nil {
+		return nil, fmt.Errorf("failed to count schemas per folder: %v", err)
+	}
+
+	counts := make(map[primitive.ObjectID]int64, len(groups))
+	for _, group := range groups {
+		var folderID primitive.ObjectID
+		if group.FolderID != nil {
+			folderID = *group.FolderID
+		}
+		counts[folderID] += group.Count
+	}
+	return counts, nil
+}
+
+func (r *schemaRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/database"
+)
+
+type schemaStarRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSchemaStarRepository(db *database.MongoDB) SchemaStarRepository {
+	return &schemaStarRepository{
+		collection: db.GetCollection("schema_stars"),
+	}
+}
+
+// EnsureIndex creates the unique index that keeps a user from starring a
+// schema twice.
+func (r *schemaStarRepository) EnsureIndex(ctx context.Context) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "schema_id", Value: 1},
+			{Key: "user_id", Value: 1},
+		},
+		Options: options.Index().SetName("schema_star_user").SetUnique(true),
+	}
+
+	if _, err := r.collection.Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create schema star index: %v", err)
+	}
+
+	return nil
+}
+
+// Add stars a schema for a user and reports whether it was not starred yet.
+// Of two concurrent upserts, the unique index fails one, which then counts
+// as already starred.
+func (r *schemaStarRepository) Add(ctx context.Context, schemaID, userID primitive.ObjectID) (bool, error) {
+	filter := bson.M{"schema_id": schemaID, "user_id": userID}
+	update := bson.M{"$setOnInsert": models.SchemaStar{
+		SchemaID:  schemaID,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to star schema: %v", err)
+	}
+
+	return result.UpsertedCount > 0, nil
+}
+
+// Remove unstars a schema and reports whether it was starred.
+func (r *schemaStarRepository) Remove(ctx context.Context, schemaID, userID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"schema_id": schemaID, "user_id": userID})
+	if err != nil {
+		return false, fmt.Errorf("failed to unstar schema: %v", err)
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+func (r *schemaStarRepository) IsStarred(ctx context.Context, schemaID, userID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"schema_id": schemaID, "user_id": userID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check star: %v", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *schemaStarRepository) DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"schema_id": schemaID}); err != nil {
+		return fmt.Errorf("failed to delete schema stars: %v", err)
+	}
+
+	return nil
+}
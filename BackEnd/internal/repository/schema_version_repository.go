@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/database"
+)
+
+type schemaVersionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSchemaVersionRepository(db *database.MongoDB) SchemaVersionRepository {
+	return &schemaVersionRepository{
+		collection: db.GetCollection("schema_versions"),
+	}
+}
+
+// Save stores the snapshot for its schema and version, replacing an earlier
+// snapshot of the same version.
+func (r *schemaVersionRepository) Save(ctx context.Context, version *models.SchemaVersion) error {
+	if version.CreatedAt.IsZero() {
+		version.CreatedAt = time.Now()
+	}
+
+	filter := bson.M{"schema_id": version.SchemaID, "version": version.Version}
+	snapshot := *version
+	snapshot.ID = primitive.NilObjectID
+	result, err := r.collection.ReplaceOne(ctx, filter, &snapshot, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save schema version: %v", err)
+	}
+
+	if id, ok := result.UpsertedID.(primitive.ObjectID); ok {
+		version.ID = id
+	}
+	return nil
+}
+
+func (r *schemaVersionRepository) Get(ctx context.Context, schemaID primitive.ObjectID, version int) (*models.SchemaVersion, error) {
+	var snapshot models.SchemaVersion
+	err := r.collection.FindOne(ctx, bson.M{"schema_id": schemaID, "version": version}).Decode(&snapshot)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("schema version not found")
+		}
+		return nil, fmt.Errorf("failed to get schema version: %v", err)
+	}
+
+	return &snapshot, nil
+}
+
+// List returns the versions of a schema, newest first, without their
+// definitions.
+func (r *schemaVersionRepository) List(ctx context.Context, schemaID primitive.ObjectID) ([]*models.SchemaVersion, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"namespaces": 0, "tables": 0, "enums": 0, "views": 0})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"schema_id": schemaID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schema versions: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var versions []*models.SchemaVersion
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode schema versions: %v", err)
+	}
+
+	return versions, nil
+}
+
+func (r *schemaVersionRepository) DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"schema_id": schemaID})
+	if err != nil {
+		return fmt.Errorf("failed to delete schema versions: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/database"
+)
+
+type shareLinkRepository struct {
+	collection *mongo.Collection
+}
+
+func NewShareLinkRepository(db *database.MongoDB) ShareLinkRepository {
+	return &shareLinkRepository{
+		collection: db.GetCollection("share_links"),
+	}
+}
+
+// EnsureTokenIndex creates the unique index GetByTokenHash looks links up
+// by.
+func (r *shareLinkRepository) EnsureTokenIndex(ctx context.Context) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "token_hash", Value: 1}},
+		Options: options.Index().SetName("share_link_token").SetUnique(true),
+	}
+
+	if _, err := r.collection.Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create share link token index: %v", err)
+	}
+
+	return nil
+}
+
+func (r *shareLinkRepository) Create(ctx context.Context, link *models.ShareLink) error {
+	link.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, link)
+	if err != nil {
+		return fmt.Errorf("failed to create share link: %v", err)
+	}
+
+	link.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *shareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&link)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("share link not found")
+		}
+		return nil, fmt.Errorf("failed to get share link: %v", err)
+	}
+
+	return &link, nil
+}
+
+func (r *shareLinkRepository) ListBySchema(ctx context.Context, schemaID primitive.ObjectID) ([]*models.ShareLink, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"schema_id": schemaID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find share links: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var links []*models.ShareLink
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, fmt.Errorf("failed to decode share links: %v", err)
+	}
+
+	return links, nil
+}
+
+func (r *shareLinkRepository) Revoke(ctx context.Context, id, schemaID primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "schema_id": schemaID}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	if err != nil {
+		return fmt.Errorf("failed to revoke share link: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("share link not found")
+	}
+
+	return nil
+}
+
+func (r *shareLinkRepository) RecordView(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$inc": bson.M{"views": 1},
+		"$set": bson.M{"last_viewed_at": time.Now()},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to record share link view: %v", err)
+	}
+
+	return nil
+}
+
+func (r *shareLinkRepository) DeleteBySchema(ctx context.Context, schemaID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"schema_id": schemaID}); err != nil {
+		return fmt.Errorf("failed to delete share links: %v", err)
+	}
+
+	return nil
+}
@@ -14,7 +14,12 @@ func SetupRoutes(
 	r *gin.Engine,
 	authHandler *handlers.AuthHandler,
 	schemaHandler *handlers.SchemaHandler,
+	exportHandler *handlers.ExportHandler,
+	importHandler *handlers.ImportHandler,
 	aiHandler *handlers.AIHandler,
+	commentHandler *handlers.CommentHandler,
+	shareHandler *handlers.ShareHandler,
+	organizeHandler *handlers.OrganizeHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	securityMiddleware *middleware.SecurityMiddleware,
 ) {
@@ -49,6 +54,7 @@ func SetupRoutes(
 		public.POST("/auth/check-user", authHandler.CheckUser)
 
 		public.GET("/schemas/public", schemaHandler.ListPublicSchemas)
+		public.GET("/shared/:token", shareHandler.GetSharedSchema)
 	}
 
 	protected := api.Group("/")
@@ -64,12 +70,78 @@ func SetupRoutes(
 		{
 			schemas.POST("", schemaHandler.CreateSchema)
 			schemas.GET("", schemaHandler.ListUserSchemas)
+			schemas.GET("/search", schemaHandler.SearchSchemas)
+			schemas.POST("/import/:format", importHandler.ImportSchema)
 			schemas.GET("/others", schemaHandler.ListOtherUsersSchemas)
+			schemas.POST("/bulk/move", organizeHandler.MoveSchemas)
+			schemas.POST("/bulk/tags", organizeHandler.TagSchemas)
 			schemas.GET("/:id", schemaHandler.GetSchema)
 			schemas.PUT("/:id", schemaHandler.UpdateSchema)
 			schemas.DELETE("/:id", schemaHandler.DeleteSchema)
 			schemas.POST("/:id/duplicate", schemaHandler.DuplicateSchema)
 			schemas.PATCH("/:id/visibility", schemaHandler.ToggleSchemaVisibility)
+			schemas.PUT("/:id/tags", organizeHandler.SetSchemaTags)
+			schemas.GET("/:id/namespaces", schemaHandler.ListNamespaces)
+			schemas.GET("/:id/versions", schemaHandler.ListSchemaVersions)
+			schemas.GET("/:id/versions/:version", schemaHandler.GetSchemaVersion)
+			schemas.GET("/:id/versions/:version/migration-safety", schemaHandler.AnalyzeMigrationSafety)
+			schemas.POST("/:id/branches", schemaHandler.CreateBranch)
+			schemas.GET("/:id/branches", schemaHandler.ListBranches)
+			schemas.GET("/:id/branches/:branch/merge", schemaHandler.PreviewMerge)
+			schemas.PUT("/:id/branches/:branch/merge/resolutions", schemaHandler.ResolveMerge)
+			schemas.POST("/:id/branches/:branch/merge", schemaHandler.CompleteMerge)
+			schemas.POST("/:id/change-requests", schemaHandler.CreateChangeRequest)
+			schemas.GET("/:id/change-requests", schemaHandler.ListChangeRequests)
+			schemas.GET("/:id/change-requests/:request", schemaHandler.GetChangeRequest)
+			schemas.PUT("/:id/change-requests/:request", schemaHandler.UpdateChangeRequest)
+			schemas.POST("/:id/change-requests/:request/reviews", schemaHandler.ReviewChangeRequest)
+			schemas.POST("/:id/change-requests/:request/merge", schemaHandler.MergeChangeRequest)
+			schemas.POST("/:id/change-requests/:request/close", schemaHandler.CloseChangeRequest)
+			schemas.GET("/:id/threads", commentHandler.ListThreads)
+			schemas.POST("/:id/threads", commentHandler.CreateThread)
+			schemas.GET("/:id/threads/:thread", commentHandler.GetThread)
+			schemas.POST("/:id/threads/:thread/comments", commentHandler.AddComment)
+			schemas.POST("/:id/threads/:thread/resolve", commentHandler.ResolveThread)
+			schemas.POST("/:id/threads/:thread/unresolve", commentHandler.UnresolveThread)
+			schemas.POST("/:id/fork", schemaHandler.ForkSchema)
+			schemas.GET("/:id/forks", schemaHandler.ListForks)
+			schemas.GET("/:id/upstream", schemaHandler.PreviewUpstream)
+			schemas.POST("/:id/upstream/pull", schemaHandler.PullUpstream)
+			schemas.GET("/:id/star", schemaHandler.GetStarStatus)
+			schemas.PUT("/:id/star", schemaHandler.StarSchema)
+			schemas.DELETE("/:id/star", schemaHandler.UnstarSchema)
+			schemas.POST("/:id/shares", shareHandler.CreateShareLink)
+			schemas.GET("/:id/shares", shareHandler.ListShareLinks)
+			schemas.DELETE("/:id/shares/:share", shareHandler.RevokeShareLink)
+			schemas.GET("/:id/normalization", schemaHandler.AnalyzeNormalization)
+			schemas.POST("/:id/normalization/apply", schemaHandler.ApplyNormalization)
+			schemas.GET("/:id/export", exportHandler.ExportSchema)
+			schemas.GET("/:id/docs", exportHandler.GenerateDocs)
+			schemas.GET("/:id/diagram.svg", exportHandler.RenderDiagram)
+			schemas.GET("/:id/diagram.png", exportHandler.RenderDiagram)
+			schemas.POST("/:id/seed", exportHandler.GenerateSeedData)
+			schemas.POST("/:id/verify", exportHandler.VerifyDDL)
+		}
+
+		folders := protected.Group("/folders")
+		{
+			folders.GET("", organizeHandler.ListFolders)
+			folders.POST("", organizeHandler.CreateFolder)
+			folders.PUT("/:folder", organizeHandler.UpdateFolder)
+			folders.DELETE("/:folder", organizeHandler.DeleteFolder)
+		}
+
+		tags := protected.Group("/tags")
+		{
+			tags.GET("", organizeHandler.ListTags)
+			tags.PUT("/:tag", organizeHandler.RenameTag)
+			tags.DELETE("/:tag", organizeHandler.DeleteTag)
+		}
+
+		shared := protected.Group("/shared")
+		{
+			shared.POST("/:token/threads", shareHandler.CreateSharedThread)
+			shared.POST("/:token/threads/:thread/comments", shareHandler.AddSharedComment)
 		}
 
 		ai := protected.Group("/ai")
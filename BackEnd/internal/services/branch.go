@@ -0,0 +1,617 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	MergeBothModified   = "both_modified"
+	MergeBothAdded      = "both_added"
+	MergeDeleteModified = "delete_modified"
+
+	MergeChoiceTarget = "target"
+	MergeChoiceBranch = "branch"
+)
+
+// MergePreview is the result of merging a branch into its schema. The
+// merged namespaces, tables, enums and views have resolved conflicts
+// applied and keep the target side of the rest.
+type MergePreview struct {
+	BranchID      primitive.ObjectID `json:"branch_id"`
+	BaseVersion   int                `json:"base_version"`
+	TargetVersion int                `json:"target_version"`
+	BranchVersion int                `json:"branch_version"`
+	Namespaces    []models.Namespace `json:"namespaces"`
+	Tables        []models.Table     `json:"tables"`
+	Enums         []models.Enum      `json:"enums"`
+	Views         []models.View      `json:"views"`
+	Conflicts     []MergeConflict    `json:"conflicts"`
+	Unresolved    int                `json:"unresolved"`
+	Issues        []string           `json:"issues,omitempty"`
+}
+
+// MergeConflict is a property, field, table, namespace, enum or view both
+// sides changed differently. Property is the JSON name of the conflicting
+// property and is empty when one side deleted what the other modified.
+type MergeConflict struct {
+	ID         string      `json:"id"`
+	Kind       string      `json:"kind"`
+	TableID    string      `json:"table_id,omitempty"`
+	FieldID    string      `json:"field_id,omitempty"`
+	Namespace  string      `json:"namespace,omitempty"`
+	EnumID     string      `json:"enum_id,omitempty"`
+	ViewID     string      `json:"view_id,omitempty"`
+	Property   string      `json:"property,omitempty"`
+	Base       interface{} `json:"base"`
+	Target     interface{} `json:"target"`
+	Branch     interface{} `json:"branch"`
+	Message    string      `json:"message"`
+	Resolution string      `json:"resolution,omitempty"`
+}
+
+func (s *SchemaService) CreateBranch(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.CreateBranchRequest) (*models.SchemaBranch, error) {
+	schema, err := s.schemaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("schema not found: %v", err)
+	}
+
+	if schema.UserID != userID {
+		return nil, fmt.Errorf("access denied: you can only branch your own schemas")
+	}
+
+	// The merge base has to be on record; schemas saved before versions
+	// were recorded may not have it yet.
+	if _, err := s.versionRepo.Get(ctx, id, schema.Version); err != nil {
+		s.recordVersion(ctx, schema, schema.UserID)
+	}
+
+	branchSchema, err := s.CreateSchema(ctx, userID, &models.CreateSchemaRequest{
+		Name:         fmt.Sprintf("%s (%s)", schema.Name, req.Name),
+		Description:  fmt.Sprintf("Branch %s of %s", req.Name, schema.Name),
+		DatabaseType: schema.DatabaseType,
+		Namespaces:   schema.Namespaces,
+		Tables:       schema.Tables,
+		Enums:        schema.Enums,
+		Views:        schema.Views,
+		IsPublic:     false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	branch := &models.SchemaBranch{
+		SchemaID:       id,
+		BranchSchemaID: branchSchema.ID,
+		Name:           req.Name,
+		BaseVersion:    schema.Version,
+		Status:         models.BranchStatusOpen,
+		CreatedBy:      userID,
+	}
+	if err := s.branchRepo.Create(ctx, branch); err != nil {
+		s.log.Errorf("Failed to create schema branch: %v", err)
+		return nil, fmt.Errorf("failed to create schema branch: %v", err)
+	}
+
+	s.log.Infof("Schema %s branched as %s at version %d", id.Hex(), branchSchema.ID.Hex(), schema.Version)
+	return branch, nil
+}
+
+func (s *SchemaService) ListBranches(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) ([]*models.SchemaBranch, error) {
+	if _, err := s.GetSchemaByID(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	branches, err := s.branchRepo.ListBySchema(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema branches: %v", err)
+	}
+
+	return branches, nil
+}
+
+func (s *SchemaService) PreviewMerge(ctx context.Context, id, branchID primitive.ObjectID, userID primitive.ObjectID) (*MergePreview, error) {
+	_, preview, err := s.prepareMerge(ctx, id, branchID, userID)
+	return preview, err
+}
+
+// ResolveMerge records a choice for each listed conflict. Choices for
+// conflicts that no longer occur are dropped.
+func (s *SchemaService) ResolveMerge(ctx context.Context, id, branchID primitive.ObjectID, userID primitive.ObjectID, req *models.ResolveMergeRequest) (*MergePreview, error) {
+	branch, preview, err := s.prepareMerge(ctx, id, branchID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if branch.Status != models.BranchStatusOpen {
+		return nil, fmt.Errorf("schema branch is not open")
+	}
+
+	resolutions := make(map[string]string)
+	known := make(map[string]bool, len(preview.Conflicts))
+	for _, conflict := range preview.Conflicts {
+		known[conflict.ID] = true
+		if choice, ok := branch.Resolutions[conflict.ID]; ok {
+			resolutions[conflict.ID] = choice
+		}
+	}
+	for _, resolution := range req.Resolutions {
+		if !known[resolution.ConflictID] {
+			return nil, fmt.Errorf("unknown merge conflict: %s", resolution.ConflictID)
+		}
+		resolutions[resolution.ConflictID] = resolution.Choice
+	}
+
+	if err := s.branchRepo.SetResolutions(ctx, branchID, resolutions); err != nil {
+		s.log.Errorf("Failed to save merge resolutions: %v", err)
+		return nil, fmt.Errorf("failed to save merge resolutions: %v", err)
+	}
+
+	return s.PreviewMerge(ctx, id, branchID, userID)
+}
+
+// CompleteMerge saves the merged definitions to the target schema and closes the
+// branch. Every conflict has to be resolved first.
+func (s *SchemaService) CompleteMerge(ctx context.Context, id, branchID primitive.ObjectID, userID primitive.ObjectID, req *models.CompleteMergeRequest) (*models.Schema, error) {
+	branch, preview, err := s.prepareMerge(ctx, id, branchID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if branch.Status != models.BranchStatusOpen {
+		return nil, fmt.Errorf("schema branch is not open")
+	}
+
+	if req.TargetVersion != 0 && req.TargetVersion != preview.TargetVersion {
+		return nil, fmt.Errorf("schema version mismatch: merge was previewed for version %d but the schema is at version %d", req.TargetVersion, preview.TargetVersion)
+	}
+	if req.BranchVersion != 0 && req.BranchVersion != preview.BranchVersion {
+		return nil, fmt.Errorf("schema version mismatch: merge was previewed for branch version %d but the branch is at version %d", req.BranchVersion, preview.BranchVersion)
+	}
+	if preview.Unresolved > 0 {
+		return nil, fmt.Errorf("unresolved merge conflicts: %d of %d conflicts have no resolution", preview.Unresolved, len(preview.Conflicts))
+	}
+
+	updated, err := s.UpdateSchema(ctx, id, userID, &models.UpdateSchemaRequest{
+		Namespaces: preview.Namespaces,
+		Tables:     preview.Tables,
+		Enums:      preview.Enums,
+		Views:      preview.Views,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.branchRepo.MarkMerged(ctx, branchID, updated.Version); err != nil {
+		s.log.Errorf("Failed to close schema branch %s: %v", branchID.Hex(), err)
+	}
+
+	s.log.Infof("Branch %s merged into schema %s at version %d", branchID.Hex(), id.Hex(), updated.Version)
+	return updated, nil
+}
+
+// prepareMerge loads the target, branch and base and merges them. Only the
+// owner of the target schema can merge into it.
+func (s *SchemaService) prepareMerge(ctx context.Context, id, branchID primitive.ObjectID, userID primitive.ObjectID) (*models.SchemaBranch, *MergePreview, error) {
+	target, err := s.schemaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("schema not found: %v", err)
+	}
+
+	if target.UserID != userID {
+		return nil, nil, fmt.Errorf("access denied: you can only merge into your own schemas")
+	}
+
+	branch, err := s.branchRepo.GetByID(ctx, branchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if branch.SchemaID != id {
+		return nil, nil, fmt.Errorf("schema branch not found")
+	}
+
+	branchSchema, err := s.schemaRepo.GetByID(ctx, branch.BranchSchemaID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("schema branch not found: %v", err)
+	}
+
+	base, err := s.GetSchemaVersion(ctx, id, userID, branch.BaseVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidate, conflicts := MergeSchemas(versionSchema(target, base), target, branchSchema, branch.Resolutions)
+	preview := &MergePreview{
+		BranchID:      branch.ID,
+		BaseVersion:   branch.BaseVersion,
+		TargetVersion: target.Version,
+		BranchVersion: branchSchema.Version,
+		Namespaces:    candidate.Namespaces,
+		Tables:        candidate.Tables,
+		Enums:         candidate.Enums,
+		Views:         candidate.Views,
+		Conflicts:     conflicts,
+	}
+	for _, conflict := range conflicts {
+		if conflict.Resolution == "" {
+			preview.Unresolved++
+		}
+	}
+
+	normalizeKeys(candidate.Tables)
+	normalizeReferences(candidate.Tables)
+	if err := ValidateSchemaDefinition(candidate); err != nil {
+		if validationErr, ok := err.(*SchemaValidationError); ok {
+			preview.Issues = validationErr.Issues
+		}
+	}
+
+	return branch, preview, nil
+}
+
+// MergeTables merges the changes made on a branch since the base into the
+// target, matching tables by ID and fields by ID within them. Properties
+// changed on one side only take that side's value; properties changed
+// differently on both sides are conflicts, settled by the resolutions or
+// left at the target's value. Table positions never conflict: a table moved
+// on both sides keeps the target's position.
+func MergeTables(base, target, branch []models.Table, resolutions map[string]string) ([]models.Table, []MergeConflict) {
+	m := &tableMerger{resolutions: resolutions}
+	return m.mergeTables(base, target, branch), m.conflicts
+}
+
+// MergeSchemas merges the tables of two schemas as MergeTables does, and
+// their namespaces, enums and views the same way, matching namespaces by
+// name and enums and views by ID. The result is a copy of target.
+func MergeSchemas(base, target, branch *models.Schema, resolutions map[string]string) (*models.Schema, []MergeConflict) {
+	m := &tableMerger{resolutions: resolutions}
+	merged := *target
+	merged.Namespaces = m.mergeObjects("namespace", base.Namespaces, target.Namespaces, branch.Namespaces, "Name").([]models.Namespace)
+	merged.Tables = m.mergeTables(base.Tables, target.Tables, branch.Tables)
+	merged.Enums = m.mergeObjects("enum", base.Enums, target.Enums, branch.Enums, "ID").([]models.Enum)
+	merged.Views = m.mergeObjects("view", base.Views, target.Views, branch.Views, "ID").([]models.View)
+	return &merged, m.conflicts
+}
+
+type tableMerger struct {
+	resolutions map[string]string
+	conflicts   []MergeConflict
+}
+
+func (m *tableMerger) mergeTables(base, target, branch []models.Table) []models.Table {
+	baseByID := tablesByID(base)
+	branchByID := tablesByID(branch)
+	targetByID := tablesByID(target)
+
+	var merged []models.Table
+	for i := range target {
+		table := &target[i]
+		baseTable, inBase := baseByID[table.ID]
+		branchTable, inBranch := branchByID[table.ID]
+		switch {
+		case inBase && inBranch:
+			merged = append(merged, m.mergeTable(baseTable, table, branchTable, MergeBothModified))
+		case inBranch:
+			merged = append(merged, m.mergeTable(&models.Table{ID: table.ID}, table, branchTable, MergeBothAdded))
+		case inBase:
+			// Deleted on the branch.
+			if sameValue(baseTable, table) {
+				continue
+			}
+			if m.deleteConflict(table.ID, "", baseTable, table, nil, "table %s was deleted on the branch but changed on the target", table.Name) != MergeChoiceBranch {
+				merged = append(merged, *table)
+			}
+		default:
+			merged = append(merged, *table)
+		}
+	}
+
+	for i := range branch {
+		table := &branch[i]
+		if _, ok := targetByID[table.ID]; ok {
+			continue
+		}
+		baseTable, inBase := baseByID[table.ID]
+		switch {
+		case !inBase:
+			merged = append(merged, *table)
+		case !sameValue(baseTable, table):
+			// Deleted on the target but changed on the branch.
+			if m.deleteConflict(table.ID, "", baseTable, nil, table, "table %s was deleted on the target but changed on the branch", table.Name) == MergeChoiceBranch {
+				merged = append(merged, *table)
+			}
+		}
+	}
+
+	if merged == nil {
+		merged = []models.Table{}
+	}
+	return merged
+}
+
+// conflict records a conflict and returns its resolution, if any.
+func (m *tableMerger) conflict(conflict MergeConflict) string {
+	conflict.Resolution = m.resolutions[conflict.ID]
+	m.conflicts = append(m.conflicts, conflict)
+	return conflict.Resolution
+}
+
+func (m *tableMerger) deleteConflict(tableID, fieldID string, base, target, branch interface{}, format string, args ...interface{}) string {
+	id := "table:" + tableID
+	if fieldID != "" {
+		id = "field:" + tableID + ":" + fieldID
+	}
+	return m.conflict(MergeConflict{
+		ID:      id,
+		Kind:    MergeDeleteModified,
+		TableID: tableID,
+		FieldID: fieldID,
+		Base:    base,
+		Target:  target,
+		Branch:  branch,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (m *tableMerger) mergeTable(base, target, branch *models.Table, kind string) models.Table {
+	merged := *target
+	m.mergeProperties(&merged, base, target, branch, func(property string) MergeConflict {
+		return MergeConflict{
+			ID:       "table:" + target.ID + ":" + property,
+			Kind:     kind,
+			TableID:  target.ID,
+			Property: property,
+			Message:  fmt.Sprintf("%s of table %s was changed on both sides", property, target.Name),
+		}
+	}, "id", "fields", "position")
+	if sameValue(target.Position, base.Position) {
+		merged.Position = branch.Position
+	}
+
+	baseFields := fieldsByID(base.Fields)
+	branchFields := fieldsByID(branch.Fields)
+	targetFields := fieldsByID(target.Fields)
+
+	var fields []models.Field
+	for i := range target.Fields {
+		field := &target.Fields[i]
+		baseField, inBase := baseFields[field.ID]
+		branchField, inBranch := branchFields[field.ID]
+		switch {
+		case inBranch:
+			fieldKind := MergeBothModified
+			if !inBase {
+				baseField = &models.Field{ID: field.ID}
+				fieldKind = MergeBothAdded
+			}
+			mergedField := *field
+			m.mergeProperties(&mergedField, baseField, field, branchField, func(property string) MergeConflict {
+				return MergeConflict{
+					ID:       "field:" + target.ID + ":" + field.ID + ":" + property,
+					Kind:     fieldKind,
+					TableID:  target.ID,
+					FieldID:  field.ID,
+					Property: property,
+					Message:  fmt.Sprintf("%s of %s.%s was changed on both sides", property, target.Name, field.Name),
+				}
+			}, "id")
+			fields = append(fields, mergedField)
+		case inBase:
+			// Deleted on the branch.
+			if sameValue(baseField, field) {
+				continue
+			}
+			if m.deleteConflict(target.ID, field.ID, baseField, field, nil, "%s.%s was deleted on the branch but changed on the target", target.Name, field.Name) != MergeChoiceBranch {
+				fields = append(fields, *field)
+			}
+		default:
+			fields = append(fields, *field)
+		}
+	}
+
+	// Fields only on the branch are placed after the field preceding them
+	// there.
+	for i := range branch.Fields {
+		field := &branch.Fields[i]
+		if _, ok := targetFields[field.ID]; ok {
+			continue
+		}
+		if baseField, inBase := baseFields[field.ID]; inBase {
+			if sameValue(baseField, field) ||
+				m.deleteConflict(target.ID, field.ID, baseField, nil, field, "%s.%s was deleted on the target but changed on the branch", target.Name, field.Name) != MergeChoiceBranch {
+				continue
+			}
+		}
+
+		position := 0
+		for j := i - 1; j >= 0 && position == 0; j-- {
+			for k := range fields {
+				if fields[k].ID == branch.Fields[j].ID {
+					position = k + 1
+					break
+				}
+			}
+		}
+		fields = append(fields[:position], append([]models.Field{*field}, fields[position:]...)...)
+	}
+
+	if fields == nil {
+		fields = []models.Field{}
+	}
+	merged.Fields = fields
+	return merged
+}
+
+// mergeObjects three-way merges slices of namespaces, enums or views,
+// matching elements by the struct field key. Properties merge as in
+// mergeTable; elements only on the branch are appended.
+func (m *tableMerger) mergeObjects(kind string, base, target, branch interface{}, key string) interface{} {
+	baseValue := reflect.ValueOf(base)
+	targetValue := reflect.ValueOf(target)
+	branchValue := reflect.ValueOf(branch)
+	keyField, _ := targetValue.Type().Elem().FieldByName(key)
+	keyProperty := strings.Split(keyField.Tag.Get("json"), ",")[0]
+
+	byKey := func(list reflect.Value) map[string]reflect.Value {
+		elements := make(map[string]reflect.Value, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			elements[list.Index(i).FieldByName(key).String()] = list.Index(i)
+		}
+		return elements
+	}
+	baseByKey := byKey(baseValue)
+	branchByKey := byKey(branchValue)
+	targetByKey := byKey(targetValue)
+
+	merged := reflect.MakeSlice(targetValue.Type(), 0, targetValue.Len())
+	for i := 0; i < targetValue.Len(); i++ {
+		element := targetValue.Index(i)
+		id := element.FieldByName(key).String()
+		name := element.FieldByName("Name").String()
+		baseElement, inBase := baseByKey[id]
+		branchElement, inBranch := branchByKey[id]
+		switch {
+		case inBranch:
+			conflictKind := MergeBothModified
+			if !inBase {
+				baseElement = reflect.New(element.Type()).Elem()
+				baseElement.FieldByName(key).SetString(id)
+				conflictKind = MergeBothAdded
+			}
+			mergedElement := reflect.New(element.Type())
+			mergedElement.Elem().Set(element)
+			m.mergeProperties(mergedElement.Interface(), baseElement.Addr().Interface(), element.Addr().Interface(), branchElement.Addr().Interface(), func(property string) MergeConflict {
+				return objectConflict(kind, id, property, conflictKind, "%s of %s %s was changed on both sides", property, kind, name)
+			}, keyProperty)
+			merged = reflect.Append(merged, mergedElement.Elem())
+		case inBase:
+			// Deleted on the branch.
+			if sameValue(baseElement.Interface(), element.Interface()) {
+				continue
+			}
+			conflict := objectConflict(kind, id, "", MergeDeleteModified, "%s %s was deleted on the branch but changed on the target", kind, name)
+			conflict.Base, conflict.Target = baseElement.Interface(), element.Interface()
+			if m.conflict(conflict) != MergeChoiceBranch {
+				merged = reflect.Append(merged, element)
+			}
+		default:
+			merged = reflect.Append(merged, element)
+		}
+	}
+
+	for i := 0; i < branchValue.Len(); i++ {
+		element := branchValue.Index(i)
+		id := element.FieldByName(key).String()
+		if _, ok := targetByKey[id]; ok {
+			continue
+		}
+		baseElement, inBase := baseByKey[id]
+		switch {
+		case !inBase:
+			merged = reflect.Append(merged, element)
+		case !sameValue(baseElement.Interface(), element.Interface()):
+			// Deleted on the target but changed on the branch.
+			conflict := objectConflict(kind, id, "", MergeDeleteModified, "%s %s was deleted on the target but changed on the branch", kind, element.FieldByName("Name").String())
+			conflict.Base, conflict.Branch = baseElement.Interface(), element.Interface()
+			if m.conflict(conflict) == MergeChoiceBranch {
+				merged = reflect.Append(merged, element)
+			}
+		}
+	}
+
+	return merged.Interface()
+}
+
+// objectConflict starts a conflict on a namespace, enum or view. Property
+// is empty when one side deleted what the other modified.
+func objectConflict(kind, id, property, conflictKind, format string, args ...interface{}) MergeConflict {
+	conflict := MergeConflict{
+		ID:       kind + ":" + id,
+		Kind:     conflictKind,
+		Property: property,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if property != "" {
+		conflict.ID += ":" + property
+	}
+	switch kind {
+	case "namespace":
+		conflict.Namespace = id
+	case "enum":
+		conflict.EnumID = id
+	case "view":
+		conflict.ViewID = id
+	}
+	return conflict
+}
+
+// mergeProperties three-way merges the exported fields of two structs of
+// the same type into merged, which starts out as a copy of target.
+func (m *tableMerger) mergeProperties(merged, base, target, branch interface{}, newConflict func(property string) MergeConflict, skip ...string) {
+	mergedValue := reflect.ValueOf(merged).Elem()
+	baseValue := reflect.ValueOf(base).Elem()
+	targetValue := reflect.ValueOf(target).Elem()
+	branchValue := reflect.ValueOf(branch).Elem()
+
+	for i := 0; i < mergedValue.NumField(); i++ {
+		property := strings.Split(mergedValue.Type().Field(i).Tag.Get("json"), ",")[0]
+		if contains(skip, property) {
+			continue
+		}
+
+		baseProperty := baseValue.Field(i).Interface()
+		targetProperty := targetValue.Field(i).Interface()
+		branchProperty := branchValue.Field(i).Interface()
+		switch {
+		case sameValue(targetProperty, branchProperty), sameValue(branchProperty, baseProperty):
+		case sameValue(targetProperty, baseProperty):
+			mergedValue.Field(i).Set(branchValue.Field(i))
+		default:
+			conflict := newConflict(property)
+			conflict.Base = baseProperty
+			conflict.Target = targetProperty
+			conflict.Branch = branchProperty
+			if m.conflict(conflict) == MergeChoiceBranch {
+				mergedValue.Field(i).Set(branchValue.Field(i))
+			}
+		}
+	}
+}
+
+// sameValue compares by JSON encoding, so nil and empty collections are
+// equal wherever the model omits empty values.
+func sameValue(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func tablesByID(tables []models.Table) map[string]*models.Table {
+	byID := make(map[string]*models.Table, len(tables))
+	for i := range tables {
+		byID[tables[i].ID] = &tables[i]
+	}
+	return byID
+}
+
+func fieldsByID(fields []models.Field) map[string]*models.Field {
+	byID := make(map[string]*models.Field, len(fields))
+	for i := range fields {
+		byID[fields[i].ID] = &fields[i]
+	}
+	return byID
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	ReviewDecisionApproved         = "approved"
+	ReviewDecisionChangesRequested = "changes_requested"
+	ReviewDecisionRequired         = "review_required"
+)
+
+// ChangeRequestDetails is a change request with what merging it would do:
+// the proposal merged into the current schema, the migration from the
+// current version to that, and any conflicts with changes made to the schema
+// since the base version.
+type ChangeRequestDetails struct {
+	*models.ChangeRequest
+	CurrentVersion int                    `json:"current_version"`
+	ReviewDecision string                 `json:"review_decision,omitempty"`
+	Migration      *MigrationSafetyReport `json:"migration"`
+	Conflicts      []MergeConflict        `json:"conflicts"`
+	Issues         []string               `json:"issues,omitempty"`
+	Mergeable      bool                   `json:"mergeable"`
+}
+
+// CreateChangeRequest proposes tables against a version of a schema. Anyone
+// who can view the schema may propose changes.
+func (s *SchemaService) CreateChangeRequest(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.CreateChangeRequestRequest) (*ChangeRequestDetails, error) {
+	schema, err := s.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseVersion := req.BaseVersion
+	if baseVersion == 0 {
+		baseVersion = schema.Version
+		// Schemas saved before versions were recorded may not have their
+		// current version on record yet.
+		if _, err := s.versionRepo.Get(ctx, id, baseVersion); err != nil {
+			s.recordVersion(ctx, schema, schema.UserID)
+		}
+	} else if _, err := s.schemaVersion(ctx, schema, baseVersion); err != nil {
+		return nil, err
+	}
+
+	if err := validateProposal(schema, req.Tables); err != nil {
+		return nil, err
+	}
+
+	reviewers, err := s.changeRequestReviewers(ctx, userID, req.Reviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	changeRequest := &models.ChangeRequest{
+		SchemaID:    id,
+		AuthorID:    userID,
+		Title:       req.Title,
+		Description: req.Description,
+		BaseVersion: baseVersion,
+		Revision:    1,
+		Tables:      req.Tables,
+		Reviewers:   reviewers,
+		Reviews:     []models.ChangeRequestReview{},
+		Status:      models.ChangeRequestOpen,
+	}
+	if err := s.changeRepo.Create(ctx, changeRequest); err != nil {
+		s.log.Errorf("Failed to create change request: %v", err)
+		return nil, fmt.Errorf("failed to create change request: %v", err)
+	}
+
+	s.log.Infof("Change request %s opened against schema %s at version %d", changeRequest.ID.Hex(), id.Hex(), baseVersion)
+	return s.changeRequestDetails(ctx, schema, changeRequest)
+}
+
+// ListChangeRequests lists the change requests of a schema, optionally only
+// those with the given status.
+func (s *SchemaService) ListChangeRequests(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, status string) ([]*models.ChangeRequest, error) {
+	if _, err := s.GetSchemaByID(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	changeRequests, err := s.changeRepo.ListBySchema(ctx, id, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list change requests: %v", err)
+	}
+
+	return changeRequests, nil
+}
+
+func (s *SchemaService) GetChangeRequest(ctx context.Context, id, requestID primitive.ObjectID, userID primitive.ObjectID) (*ChangeRequestDetails, error) {
+	schema, changeRequest, err := s.loadChangeRequest(ctx, id, requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.changeRequestDetails(ctx, schema, changeRequest)
+}
+
+// UpdateChangeRequest edits an open change request. Only its author can
+// edit it; changing the tables starts a new revision, so earlier approvals
+// no longer count.
+func (s *SchemaService) UpdateChangeRequest(ctx context.Context, id, requestID primitive.ObjectID, userID primitive.ObjectID, req *models.UpdateChangeRequestRequest) (*ChangeRequestDetails, error) {
+	schema, changeRequest, err := s.loadChangeRequest(ctx, id, requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeRequest.AuthorID != userID {
+		return nil, fmt.Errorf("access denied: only the author can update a change request")
+	}
+	if changeRequest.Status != models.ChangeRequestOpen {
+		return nil, fmt.Errorf("change request is not open")
+	}
+
+	if req.BaseVersion != 0 && req.Tables == nil {
+		return nil, fmt.Errorf("invalid change request: base_version can only be changed together with tables")
+	}
+
+	if req.Title != "" {
+		changeRequest.Title = req.Title
+	}
+	if req.Description != "" {
+		changeRequest.Description = req.Description
+	}
+	if req.Tables != nil {
+		if req.BaseVersion != 0 {
+			if _, err := s.schemaVersion(ctx, schema, req.BaseVersion); err != nil {
+				return nil, err
+			}
+			changeRequest.BaseVersion = req.BaseVersion
+		}
+		if err := validateProposal(schema, req.Tables); err != nil {
+			return nil, err
+		}
+		changeRequest.Tables = req.Tables
+		changeRequest.Revision++
+	}
+	if req.Reviewers != nil {
+		reviewers, err := s.changeRequestReviewers(ctx, userID, req.Reviewers)
+		if err != nil {
+			return nil, err
+		}
+		changeRequest.Reviewers = reviewers
+	}
+
+	if err := s.changeRepo.Update(ctx, changeRequest); err != nil {
+		s.log.Errorf("Failed to update change request %s: %v", requestID.Hex(), err)
+		return nil, err
+	}
+
+	return s.changeRequestDetails(ctx, schema, changeRequest)
+}
+
+// ReviewChangeRequest adds a review to the current revision. Anyone who can
+// see the change request can comment; only its reviewers can approve or
+// request changes.
+func (s *SchemaService) ReviewChangeRequest(ctx context.Context, id, requestID primitive.ObjectID, userID primitive.ObjectID, req *models.ReviewChangeRequestRequest) (*ChangeRequestDetails, error) {
+	schema, changeRequest, err := s.loadChangeRequest(ctx, id, requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeRequest.Status != models.ChangeRequestOpen {
+		return nil, fmt.Errorf("change request is not open")
+	}
+	if req.State != models.ReviewCommented && !isReviewer(changeRequest, userID) {
+		return nil, fmt.Errorf("access denied: only reviewers can approve or request changes")
+	}
+	if req.State == models.ReviewCommented && strings.TrimSpace(req.Body) == "" {
+		return nil, fmt.Errorf("invalid review: a comment needs a body")
+	}
+
+	review := &models.ChangeRequestReview{
+		ReviewerID: userID,
+		State:      req.State,
+		Body:       req.Body,
+		Revision:   changeRequest.Revision,
+	}
+	if err := s.changeRepo.AddReview(ctx, requestID, review); err != nil {
+		s.log.Errorf("Failed to review change request %s: %v", requestID.Hex(), err)
+		return nil, err
+	}
+	changeRequest.Reviews = append(changeRequest.Reviews, *review)
+
+	return s.changeRequestDetails(ctx, schema, changeRequest)
+}
+
+// MergeChangeRequest applies an approved change request to the schema,
+// creating a new version. Only the owner of the schema can merge.
+func (s *SchemaService) MergeChangeRequest(ctx context.Context, id, requestID primitive.ObjectID, userID primitive.ObjectID) (*models.Schema, error) {
+	schema, changeRequest, err := s.loadChangeRequest(ctx, id, requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.UserID != userID {
+		return nil, fmt.Errorf("access denied: only the schema owner can merge change requests")
+	}
+	if changeRequest.Status != models.ChangeRequestOpen {
+		return nil, fmt.Errorf("change request is not open")
+	}
+
+	switch reviewDecision(changeRequest) {
+	case ReviewDecisionChangesRequested:
+		return nil, fmt.Errorf("change request not approved: changes were requested")
+	case ReviewDecisionRequired:
+		if len(changeRequest.Reviewers) == 0 {
+			return nil, fmt.Errorf("change request not approved: it has no reviewers")
+		}
+		return nil, fmt.Errorf("change request not approved: waiting for reviewers to approve revision %d", changeRequest.Revision)
+	}
+
+	tables, conflicts, err := s.mergeChangeRequest(ctx, schema, changeRequest)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("merge conflicts: the schema changed since version %d in %d places the change request also changes", changeRequest.BaseVersion, len(conflicts))
+	}
+
+	updated, err := s.UpdateSchema(ctx, id, userID, &models.UpdateSchemaRequest{Tables: tables})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.changeRepo.SetStatus(ctx, requestID, models.ChangeRequestMerged, updated.Version); err != nil {
+		s.log.Errorf("Failed to mark change request %s merged: %v", requestID.Hex(), err)
+	}
+
+	s.log.Infof("Change request %s merged into schema %s at version %d", requestID.Hex(), id.Hex(), updated.Version)
+	return updated, nil
+}
+
+// CloseChangeRequest closes an open change request without merging it. The
+// author and the schema owner can close it.
+func (s *SchemaService) CloseChangeRequest(ctx context.Context, id, requestID primitive.ObjectID, userID primitive.ObjectID) (*models.ChangeRequest, error) {
+	schema, changeRequest, err := s.loadChangeRequest(ctx, id, requestID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeRequest.AuthorID != userID && schema.UserID != userID {
+		return nil, fmt.Errorf("access denied: only the author or the schema owner can close a change request")
+	}
+
+	if err := s.changeRepo.SetStatus(ctx, requestID, models.ChangeRequestClosed, 0); err != nil {
+		return nil, err
+	}
+
+	changeRequest.Status = models.ChangeRequestClosed
+	return changeRequest, nil
+}
+
+// loadChangeRequest loads a change request and its schema. Reviewers and
+// the author can see a change request even if the schema is private.
+func (s *SchemaService) loadChangeRequest(ctx context.Context, id, requestID primitive.ObjectID, userID primitive.ObjectID) (*models.Schema, *models.ChangeRequest, error) {
+	schema, err := s.schemaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("schema not found: %v", err)
+	}
+
+	changeRequest, err := s.changeRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if changeRequest.SchemaID != id {
+		return nil, nil, fmt.Errorf("change request not found")
+	}
+
+	if schema.UserID != userID && !schema.IsPublic && changeRequest.AuthorID != userID && !isReviewer(changeRequest, userID) {
+		return nil, nil, fmt.Errorf("access denied: schema is private")
+	}
+
+	return schema, changeRequest, nil
+}
+
+func (s *SchemaService) changeRequestDetails(ctx context.Context, schema *models.Schema, changeRequest *models.ChangeRequest) (*ChangeRequestDetails, error) {
+	details := &ChangeRequestDetails{
+		ChangeRequest:  changeRequest,
+		CurrentVersion: schema.Version,
+		ReviewDecision: reviewDecision(changeRequest),
+	}
+
+	tables, conflicts, err := s.mergeChangeRequest(ctx, schema, changeRequest)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts == nil {
+		conflicts = []MergeConflict{}
+	}
+	details.Conflicts = conflicts
+
+	proposed := *schema
+	proposed.Version = schema.Version + 1
+	proposed.Tables = tables
+	normalizeKeys(proposed.Tables)
+	normalizeReferences(proposed.Tables)
+	if err := ValidateSchemaDefinition(&proposed); err != nil {
+		if validationErr, ok := err.(*SchemaValidationError); ok {
+			details.Issues = validationErr.Issues
+		}
+	}
+
+	dialects := []SQLDialect{DialectPostgreSQL, DialectMySQL, DialectSQLite}
+	if dialect, ok := ParseSQLDialect(schema.DatabaseType); ok {
+		dialects = []SQLDialect{dialect}
+	}
+	details.Migration = AnalyzeMigrationSafety(schema, &proposed, dialects)
+	details.Migration.SchemaID = schema.ID
+
+	details.Mergeable = changeRequest.Status == models.ChangeRequestOpen &&
+		len(conflicts) == 0 && len(details.Issues) == 0 &&
+		details.ReviewDecision != ReviewDecisionChangesRequested &&
+		details.ReviewDecision != ReviewDecisionRequired
+	return details, nil
+}
+
+// mergeChangeRequest merges the proposal into the current tables, with the
+// base version as the common ancestor.
+func (s *SchemaService) mergeChangeRequest(ctx context.Context, schema *models.Schema, changeRequest *models.ChangeRequest) ([]models.Table, []MergeConflict, error) {
+	base, err := s.schemaVersion(ctx, schema, changeRequest.BaseVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tables, conflicts := MergeTables(base.Tables, schema.Tables, changeRequest.Tables, nil)
+	return tables, conflicts, nil
+}
+
+// changeRequestReviewers resolves reviewer emails to user IDs.
+func (s *SchemaService) changeRequestReviewers(ctx context.Context, authorID primitive.ObjectID, emails []string) ([]primitive.ObjectID, error) {
+	reviewers := []primitive.ObjectID{}
+	seen := make(map[primitive.ObjectID]bool)
+	for _, email := range emails {
+		user, err := s.userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("invalid change request: unknown reviewer %s", email)
+		}
+		if user.ID == authorID {
+			return nil, fmt.Errorf("invalid change request: the author cannot review their own change request")
+		}
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			reviewers = append(reviewers, user.ID)
+		}
+	}
+	return reviewers, nil
+}
+
+func validateProposal(schema *models.Schema, tables []models.Table) error {
+	candidate := *schema
+	candidate.Tables = tables
+	normalizeKeys(candidate.Tables)
+	normalizeReferences(candidate.Tables)
+	if err := ValidateSchemaDefinition(&candidate); err != nil {
+		return err
+	}
+	return nil
+}
+
+func isReviewer(changeRequest *models.ChangeRequest, userID primitive.ObjectID) bool {
+	for _, reviewer := range changeRequest.Reviewers {
+		if reviewer == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewDecision sums up the reviews. A reviewer's latest approval or
+// request for changes is their verdict; approvals only count for the
+// current revision, while requested changes stand until the reviewer
+// reviews again. Change requests without reviewers always need review, so
+// nothing merges without an approval.
+func reviewDecision(changeRequest *models.ChangeRequest) string {
+	if len(changeRequest.Reviewers) == 0 {
+		return ReviewDecisionRequired
+	}
+
+	verdicts := make(map[primitive.ObjectID]models.ChangeRequestReview)
+	for _, review := range changeRequest.Reviews {
+		if review.State != models.ReviewCommented {
+			verdicts[review.ReviewerID] = review
+		}
+	}
+
+	decision := ReviewDecisionApproved
+	for _, reviewer := range changeRequest.Reviewers {
+		verdict, ok := verdicts[reviewer]
+		switch {
+		case ok && verdict.State == models.ReviewChangesRequested:
+			return ReviewDecisionChangesRequested
+		case !ok || verdict.Revision != changeRequest.Revision:
+			decision = ReviewDecisionRequired
+		}
+	}
+	return decision
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/repository"
+	"schema-builder-backend/pkg/logger"
+)
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_.-]+)`)
+
+type CommentService struct {
+	schemaService *SchemaService
+	threadRepo    repository.CommentThreadRepository
+	userRepo      repository.UserRepository
+	emailService  *EmailService
+	log           *logrus.Logger
+}
+
+// CommentThreadDetails is a thread with the current names of what it is
+// anchored on. Detached threads are anchored on a table or field that has
+// since been removed from the schema.
+type CommentThreadDetails struct {
+	*models.CommentThread
+	TableName string `json:"table_name,omitempty"`
+	FieldName string `json:"field_name,omitempty"`
+	Detached  bool   `json:"detached"`
+}
+
+func NewCommentService(schemaService *SchemaService, threadRepo repository.CommentThreadRepository, userRepo repository.UserRepository, emailService *EmailService) *CommentService {
+	return &CommentService{
+		schemaService: schemaService,
+		threadRepo:    threadRepo,
+		userRepo:      userRepo,
+		emailService:  emailService,
+		log:           logger.GetLogger(),
+	}
+}
+
+// CreateThread starts a thread on the schema, a table or a field. Anyone who
+// can view the schema can comment on it.
+func (s *CommentService) CreateThread(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.CreateCommentThreadRequest) (*CommentThreadDetails, error) {
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createThread(ctx, schema, userID, req)
+}
+
+func (s *CommentService) createThread(ctx context.Context, schema *models.Schema, userID primitive.ObjectID, req *models.CreateCommentThreadRequest) (*CommentThreadDetails, error) {
+	thread := &models.CommentThread{
+		SchemaID:   schema.ID,
+		AnchorType: models.AnchorSchema,
+		TableID:    req.TableID,
+		FieldID:    req.FieldID,
+		Comments:   []models.Comment{},
+		CreatedBy:  userID,
+	}
+	switch {
+	case req.FieldID != "" && req.TableID == "":
+		return nil, fmt.Errorf("invalid anchor: field_id requires table_id")
+	case req.FieldID != "":
+		thread.AnchorType = models.AnchorField
+	case req.TableID != "":
+		thread.AnchorType = models.AnchorTable
+	}
+
+	details := threadDetails(schema, thread)
+	if details.Detached {
+		if req.FieldID != "" {
+			return nil, fmt.Errorf("invalid anchor: field %s not found in table %s", req.FieldID, req.TableID)
+		}
+		return nil, fmt.Errorf("invalid anchor: table %s not found", req.TableID)
+	}
+
+	comment, mentioned := s.newComment(ctx, schema, userID, req.Body)
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+	thread.Comments = append(thread.Comments, *comment)
+	if err := s.threadRepo.Create(ctx, thread); err != nil {
+		s.log.Errorf("Failed to create comment thread: %v", err)
+		return nil, fmt.Errorf("failed to create comment thread: %v", err)
+	}
+
+	s.notifyMentions(ctx, schema, details, comment, mentioned)
+	return details, nil
+}
+
+// ListThreads lists the threads of a schema, optionally only those that are
+// (un)resolved or anchored on a table or field.
+func (s *CommentService) ListThreads(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, filter repository.CommentThreadFilter) ([]*CommentThreadDetails, error) {
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.SchemaID = id
+	return s.listThreads(ctx, schema, filter)
+}
+
+func (s *CommentService) listThreads(ctx context.Context, schema *models.Schema, filter repository.CommentThreadFilter) ([]*CommentThreadDetails, error) {
+	threads, err := s.threadRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comment threads: %v", err)
+	}
+
+	details := make([]*CommentThreadDetails, 0, len(threads))
+	for _, thread := range threads {
+		details = append(details, threadDetails(schema, thread))
+	}
+	return details, nil
+}
+
+func (s *CommentService) GetThread(ctx context.Context, id, threadID primitive.ObjectID, userID primitive.ObjectID) (*CommentThreadDetails, error) {
+	schema, thread, err := s.loadThread(ctx, id, threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return threadDetails(schema, thread), nil
+}
+
+func (s *CommentService) AddComment(ctx context.Context, id, threadID primitive.ObjectID, userID primitive.ObjectID, req *models.AddCommentRequest) (*CommentThreadDetails, error) {
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.addComment(ctx, schema, threadID, userID, req)
+}
+
+func (s *CommentService) addComment(ctx context.Context, schema *models.Schema, threadID primitive.ObjectID, userID primitive.ObjectID, req *models.AddCommentRequest) (*CommentThreadDetails, error) {
+	thread, err := s.thread(ctx, schema, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := threadDetails(schema, thread)
+	comment, mentioned := s.newComment(ctx, schema, userID, req.Body)
+	if err := s.threadRepo.AddComment(ctx, threadID, comment); err != nil {
+		s.log.Errorf("Failed to add comment to thread %s: %v", threadID.Hex(), err)
+		return nil, err
+	}
+	thread.Comments = append(thread.Comments, *comment)
+
+	s.notifyMentions(ctx, schema, details, comment, mentioned)
+	return details, nil
+}
+
+// SetResolved resolves or reopens a thread. The thread's author and the
+// schema owner can do either.
+func (s *CommentService) SetResolved(ctx context.Context, id, threadID primitive.ObjectID, userID primitive.ObjectID, resolved bool) (*CommentThreadDetails, error) {
+	schema, thread, err := s.loadThread(ctx, id, threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if thread.CreatedBy != userID && schema.UserID != userID {
+		return nil, fmt.Errorf("access denied: only the thread author or the schema owner can resolve a thread")
+	}
+
+	if err := s.threadRepo.SetResolved(ctx, threadID, resolved, userID); err != nil {
+		return nil, err
+	}
+
+	thread.Resolved = resolved
+	thread.ResolvedBy, thread.ResolvedAt = nil, nil
+	if resolved {
+		now := time.Now()
+		thread.ResolvedBy, thread.ResolvedAt = &userID, &now
+	}
+	return threadDetails(schema, thread), nil
+}
+
+func (s *CommentService) loadThread(ctx context.Context, id, threadID primitive.ObjectID, userID primitive.ObjectID) (*models.Schema, *models.CommentThread, error) {
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thread, err := s.thread(ctx, schema, threadID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return schema, thread, nil
+}
+
+func (s *CommentService) thread(ctx context.Context, schema *models.Schema, threadID primitive.ObjectID) (*models.CommentThread, error) {
+	thread, err := s.threadRepo.GetByID(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if thread.SchemaID != schema.ID {
+		return nil, fmt.Errorf("comment thread not found")
+	}
+
+	return thread, nil
+}
+
+// newComment builds a comment, resolving the users it mentions. Mentions
+// of users who cannot view the schema are ignored.
+func (s *CommentService) newComment(ctx context.Context, schema *models.Schema, userID primitive.ObjectID, body string) (*models.Comment, []*models.User) {
+	var mentioned []*models.User
+	comment := &models.Comment{AuthorID: userID, Body: body}
+	for _, username := range parseMentions(body) {
+		user, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil || user.ID == userID || (user.ID != schema.UserID && !schema.IsPublic) {
+			continue
+		}
+		comment.Mentions = append(comment.Mentions, user.ID)
+		mentioned = append(mentioned, user)
+	}
+	return comment, mentioned
+}
+
+// notifyMentions emails the mentioned users. Failures are logged only: the
+// comment has already been saved.
+func (s *CommentService) notifyMentions(ctx context.Context, schema *models.Schema, thread *CommentThreadDetails, comment *models.Comment, mentioned []*models.User) {
+	if len(mentioned) == 0 {
+		return
+	}
+
+	authorName := "Someone"
+	if author, err := s.userRepo.GetByID(ctx, comment.AuthorID); err == nil {
+		authorName = strings.TrimSpace(author.FirstName + " " + author.LastName)
+		if authorName == "" {
+			authorName = author.Username
+		}
+	}
+
+	for _, user := range mentioned {
+		if err := s.emailService.SendMentionEmail(user.Email, user.FirstName, authorName, schema.Name, thread.anchorName(), comment.Body); err != nil {
+			s.log.Warnf("Failed to notify %s of mention in thread %s: %v", user.Email, thread.ID.Hex(), err)
+		}
+	}
+}
+
+func (d *CommentThreadDetails) anchorName() string {
+	switch d.AnchorType {
+	case models.AnchorField:
+		return fmt.Sprintf("field %s.%s", d.TableName, d.FieldName)
+	case models.AnchorTable:
+		return fmt.Sprintf("table %s", d.TableName)
+	}
+	return "the schema"
+}
+
+func threadDetails(schema *models.Schema, thread *models.CommentThread) *CommentThreadDetails {
+	details := &CommentThreadDetails{CommentThread: thread}
+	if thread.AnchorType == models.AnchorSchema {
+		return details
+	}
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		if table.ID != thread.TableID {
+			continue
+		}
+		details.TableName = table.Name
+		if thread.AnchorType == models.AnchorTable {
+			return details
+		}
+		for _, field := range table.Fields {
+			if field.ID == thread.FieldID {
+				details.FieldName = field.Name
+				return details
+			}
+		}
+	}
+
+	details.Detached = true
+	return details
+}
+
+// parseMentions returns the distinct usernames mentioned as @username.
+func parseMentions(body string) []string {
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username != "" && !contains(usernames, username) {
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
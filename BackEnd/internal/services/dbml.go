@@ -0,0 +1,278 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+var dbmlDatabaseTypes = map[string]string{
+	"postgresql": "PostgreSQL",
+	"mysql":      "MySQL",
+	"sqlite":     "SQLite",
+}
+
+func dbmlName(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+}
+
+func dbmlQualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return dbmlName(name)
+	}
+	return dbmlName(namespace) + "." + dbmlName(name)
+}
+
+func dbmlString(value string) string {
+	if strings.Contains(value, "\n") {
+		return "'''" + strings.ReplaceAll(value, "'''", `\'''`) + "'''"
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+// dbmlDefault maps a stored default to DBML: numbers, booleans and null stay
+// bare, function calls and SQL keywords become backtick expressions and
+// everything else is a string.
+func dbmlDefault(value string) string {
+	trimmed := strings.TrimSpace(value)
+	upper := strings.ToUpper(trimmed)
+	switch {
+	case upper == "NULL" || upper == "TRUE" || upper == "FALSE":
+		return strings.ToLower(trimmed)
+	case numericLiteralPattern.MatchString(trimmed):
+		return trimmed
+	case sqlKeywordDefaults[upper] || functionLiteralPattern.MatchString(trimmed):
+		return "`" + trimmed + "`"
+	case len(trimmed) >= 2 && strings.HasPrefix(trimmed, "'") && strings.HasSuffix(trimmed, "'"):
+		return dbmlString(strings.ReplaceAll(trimmed[1:len(trimmed)-1], "''", "'"))
+	}
+	return dbmlString(value)
+}
+
+func dbmlColumnType(schema *models.Schema, table *models.Table, field *models.Field) string {
+	if enum := resolveEnum(schema, table.Namespace, field.Type); enum != nil {
+		return dbmlQualifiedName(enum.Namespace, enum.Name)
+	}
+	columnType := strings.ReplaceAll(renderColumnType("", field), ", ", ",")
+	if strings.ContainsAny(columnType, " \"") {
+		return dbmlName(columnType)
+	}
+	return columnType
+}
+
+func dbmlRefEndpoint(table *models.Table, fields []*models.Field) string {
+	prefix := dbmlQualifiedName(table.Namespace, table.Name) + "."
+	if len(fields) == 1 {
+		return prefix + dbmlName(fields[0].Name)
+	}
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = dbmlName(field.Name)
+	}
+	return prefix + "(" + strings.Join(names, ", ") + ")"
+}
+
+func GenerateDBML(schema *models.Schema) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Project %s {\n", dbmlName(schema.Name))
+	if databaseType, ok := dbmlDatabaseTypes[schema.DatabaseType]; ok {
+		fmt.Fprintf(&b, "  database_type: %s\n", dbmlString(databaseType))
+	}
+	if schema.Description != "" {
+		fmt.Fprintf(&b, "  Note: %s\n", dbmlString(schema.Description))
+	}
+	b.WriteString("}\n")
+
+	for _, enum := range schema.Enums {
+		fmt.Fprintf(&b, "\nEnum %s {\n", dbmlQualifiedName(enum.Namespace, enum.Name))
+		for _, value := range enum.Values {
+			fmt.Fprintf(&b, "  %s\n", dbmlName(value))
+		}
+		b.WriteString("}\n")
+	}
+
+	for i := range schema.Tables {
+		b.WriteString("\n")
+		b.WriteString(dbmlTable(schema, &schema.Tables[i]))
+	}
+
+	relations := collectRelations(schema)
+	if len(relations) > 0 {
+		b.WriteString("\n")
+	}
+	for i := range relations {
+		relation := &relations[i]
+		op := ">"
+		if relation.IsOneToOne() {
+			op = "-"
+		}
+
+		name := ""
+		var settings []string
+		if relation.Constraint != nil {
+			if relation.Constraint.Name != "" {
+				name = " " + dbmlName(relation.Constraint.Name)
+			}
+			if relation.OnDelete != "" {
+				settings = append(settings, "delete: "+strings.ToLower(relation.OnDelete))
+			}
+			if relation.OnUpdate != "" {
+				settings = append(settings, "update: "+strings.ToLower(relation.OnUpdate))
+			}
+		}
+
+		line := fmt.Sprintf("Ref%s: %s %s %s", name, dbmlRefEndpoint(relation.From, relation.FromFields), op, dbmlRefEndpoint(relation.To, relation.ToFields))
+		if len(settings) > 0 {
+			line += " [" + strings.Join(settings, ", ") + "]"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return b.String()
+}
+
+func dbmlTable(schema *models.Schema, table *models.Table) string {
+	var b strings.Builder
+	primaryKey := primaryKeyFields(table)
+	inPrimaryKey := make(map[*models.Field]bool, len(primaryKey))
+	for _, field := range primaryKey {
+		inPrimaryKey[field] = true
+	}
+
+	fmt.Fprintf(&b, "Table %s {\n", dbmlQualifiedName(table.Namespace, table.Name))
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		var settings []string
+		if len(primaryKey) == 1 && primaryKey[0] == field {
+			settings = append(settings, "pk")
+		} else if field.IsNotNull && !inPrimaryKey[field] {
+			settings = append(settings, "not null")
+		}
+		if field.IsUnique {
+			settings = append(settings, "unique")
+		}
+		if field.DefaultValue != "" {
+			settings = append(settings, "default: "+dbmlDefault(field.DefaultValue))
+		}
+		if field.Comment != "" {
+			settings = append(settings, "note: "+dbmlString(field.Comment))
+		}
+
+		line := fmt.Sprintf("  %s %s", dbmlName(field.Name), dbmlColumnType(schema, table, field))
+		if len(settings) > 0 {
+			line += " [" + strings.Join(settings, ", ") + "]"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if table.Description != "" {
+		fmt.Fprintf(&b, "\n  Note: %s\n", dbmlString(table.Description))
+	}
+
+	var indexes []string
+	if len(primaryKey) > 1 {
+		indexes = append(indexes, dbmlIndexColumns(fieldNames(primaryKey), nil)+" [pk]")
+	}
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		if constraintKind(constraint) != ConstraintUnique {
+			continue
+		}
+		fields := constraintFields(table, constraint)
+		if len(fields) == 0 {
+			continue
+		}
+		settings := []string{"unique"}
+		if constraint.Name != "" {
+			settings = append(settings, "name: "+dbmlString(constraint.Name))
+		}
+		indexes = append(indexes, dbmlIndexColumns(fieldNames(fields), nil)+" ["+strings.Join(settings, ", ")+"]")
+	}
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+		var names, expressions []string
+		for _, column := range indexColumns(index) {
+			if field := findField(table, column.FieldID); field != nil {
+				names = append(names, field.Name)
+				expressions = append(expressions, "")
+			} else if column.Expression != "" {
+				names = append(names, "")
+				expressions = append(expressions, column.Expression)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		var settings []string
+		if index.IsUnique {
+			settings = append(settings, "unique")
+		}
+		if index.Name != "" {
+			settings = append(settings, "name: "+dbmlString(index.Name))
+		}
+		if method := indexMethod(index); method != "" {
+			settings = append(settings, "type: "+method)
+		}
+		line := dbmlIndexColumns(names, expressions)
+		if len(settings) > 0 {
+			line += " [" + strings.Join(settings, ", ") + "]"
+		}
+		if strings.TrimSpace(index.Where) != "" {
+			line += " // partial index condition omitted: " + strings.Join(strings.Fields(index.Where), " ")
+		}
+		indexes = append(indexes, line)
+	}
+	if len(indexes) > 0 {
+		b.WriteString("\n  indexes {\n")
+		for _, line := range indexes {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+		b.WriteString("  }\n")
+	}
+
+	var checks []string
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		if constraintKind(constraint) != ConstraintCheck || strings.TrimSpace(constraint.CheckCondition) == "" {
+			continue
+		}
+		line := "`" + constraint.CheckCondition + "`"
+		if constraint.Name != "" {
+			line += " [name: " + dbmlString(constraint.Name) + "]"
+		}
+		checks = append(checks, line)
+	}
+	if len(checks) > 0 {
+		b.WriteString("\n  checks {\n")
+		for _, line := range checks {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dbmlIndexColumns renders the key of an index; expressions[i], when set,
+// replaces names[i] with a backtick expression.
+func dbmlIndexColumns(names, expressions []string) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		if expressions != nil && expressions[i] != "" {
+			parts[i] = "`" + expressions[i] + "`"
+		} else {
+			parts[i] = dbmlName(name)
+		}
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,1146 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"schema-builder-backend/internal/models"
+)
+
+type dbmlTokenKind int
+
+const (
+	dbmlTokenEOF dbmlTokenKind = iota
+	dbmlTokenNewline
+	dbmlTokenIdent
+	dbmlTokenQuoted
+	dbmlTokenString
+	dbmlTokenExpression
+	dbmlTokenPunct
+)
+
+type dbmlToken struct {
+	Kind dbmlTokenKind
+	Text string
+	Line int
+}
+
+func (t dbmlToken) is(text string) bool {
+	return t.Kind == dbmlTokenPunct && t.Text == text
+}
+
+func (t dbmlToken) isKeyword(keyword string) bool {
+	return t.Kind == dbmlTokenIdent && strings.EqualFold(t.Text, keyword)
+}
+
+func (t dbmlToken) isName() bool {
+	return t.Kind == dbmlTokenIdent || t.Kind == dbmlTokenQuoted
+}
+
+func tokenizeDBML(content string) ([]dbmlToken, error) {
+	var tokens []dbmlToken
+	runes := []rune(content)
+	line := 1
+
+	readUntil := func(start int, quote rune) (string, int, error) {
+		var b strings.Builder
+		startLine := line
+		for i := start; i < len(runes); i++ {
+			switch r := runes[i]; {
+			case r == '\\' && i+1 < len(runes):
+				i++
+				switch runes[i] {
+				case 'n':
+					b.WriteRune('\n')
+				case 't':
+					b.WriteRune('\t')
+				case '\n':
+					line++
+				default:
+					b.WriteRune(runes[i])
+				}
+			case r == quote:
+				return b.String(), i + 1, nil
+			default:
+				if r == '\n' {
+					line++
+				}
+				b.WriteRune(r)
+			}
+		}
+		return "", 0, fmt.Errorf("line %d: unterminated %c", startLine, quote)
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			tokens = append(tokens, dbmlToken{Kind: dbmlTokenNewline, Line: line})
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := strings.Index(string(runes[i+2:]), "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			comment := []rune(string(runes[i+2:])[:end])
+			line += strings.Count(string(comment), "\n")
+			i += 2 + len(comment) + 2
+		case r == '\'' && i+2 < len(runes) && runes[i+1] == '\'' && runes[i+2] == '\'':
+			rest := string(runes[i+3:])
+			end := strings.Index(rest, "'''")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated multi-line string", line)
+			}
+			text := rest[:end]
+			tokens = append(tokens, dbmlToken{Kind: dbmlTokenString, Text: dedentDBML(text), Line: line})
+			line += strings.Count(text, "\n")
+			i += 3 + len([]rune(text)) + 3
+		case r == '\'' || r == '"' || r == '`':
+			startLine := line
+			text, next, err := readUntil(i+1, r)
+			if err != nil {
+				return nil, err
+			}
+			kind := dbmlTokenString
+			if r == '"' {
+				kind = dbmlTokenQuoted
+			} else if r == '`' {
+				kind = dbmlTokenExpression
+			}
+			tokens = append(tokens, dbmlToken{Kind: kind, Text: text, Line: startLine})
+			i = next
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			if unicode.IsDigit(r) && i+1 < len(runes) && runes[i] == '.' && unicode.IsDigit(runes[i+1]) {
+				i++
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, dbmlToken{Kind: dbmlTokenIdent, Text: string(runes[start:i]), Line: line})
+		default:
+			tokens = append(tokens, dbmlToken{Kind: dbmlTokenPunct, Text: string(r), Line: line})
+			i++
+		}
+	}
+
+	return append(tokens, dbmlToken{Kind: dbmlTokenEOF, Line: line}), nil
+}
+
+// dedentDBML strips the common indentation of a multi-line string, as
+// dbdiagram.io does.
+func dedentDBML(text string) string {
+	lines := strings.Split(strings.Trim(text, "\n"), "\n")
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		width := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || width < indent {
+			indent = width
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+type dbmlSetting struct {
+	Key   string
+	Value []dbmlToken
+	Ref   *dbmlRef
+}
+
+func (s dbmlSetting) text() string {
+	parts := make([]string, len(s.Value))
+	for i, token := range s.Value {
+		parts[i] = token.Text
+	}
+	return strings.Join(parts, " ")
+}
+
+type dbmlEndpoint struct {
+	Table   []string
+	Columns []string
+}
+
+type dbmlRef struct {
+	Name     string
+	Left     dbmlEndpoint
+	Op       string
+	Right    dbmlEndpoint
+	OnDelete string
+	OnUpdate string
+	Line     int
+}
+
+type dbmlParser struct {
+	tokens  []dbmlToken
+	pos     int
+	request *models.CreateSchemaRequest
+	aliases map[string]int
+	refs    []dbmlRef
+}
+
+func ParseDBML(content string) (*models.CreateSchemaRequest, error) {
+	tokens, err := tokenizeDBML(content)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dbmlParser{
+		tokens:  tokens,
+		request: &models.CreateSchemaRequest{},
+		aliases: make(map[string]int),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	if err := p.resolve(); err != nil {
+		return nil, err
+	}
+	return p.request, nil
+}
+
+func (p *dbmlParser) peek() dbmlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *dbmlParser) peekAt(offset int) dbmlToken {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *dbmlParser) next() dbmlToken {
+	token := p.tokens[p.pos]
+	if token.Kind != dbmlTokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *dbmlParser) skipNewlines() {
+	for p.peek().Kind == dbmlTokenNewline {
+		p.pos++
+	}
+}
+
+func (p *dbmlParser) errorf(token dbmlToken, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", token.Line, fmt.Sprintf(format, args...))
+}
+
+func (p *dbmlParser) expect(text string) error {
+	token := p.next()
+	if !token.is(text) {
+		return p.errorf(token, "expected %q, found %q", text, token.Text)
+	}
+	return nil
+}
+
+func (p *dbmlParser) expectName() (string, error) {
+	token := p.next()
+	if !token.isName() {
+		return "", p.errorf(token, "expected a name, found %q", token.Text)
+	}
+	return token.Text, nil
+}
+
+// endOfLine accepts a newline, or a closing brace left for the caller.
+func (p *dbmlParser) endOfLine() error {
+	token := p.peek()
+	switch {
+	case token.Kind == dbmlTokenNewline:
+		p.pos++
+		return nil
+	case token.Kind == dbmlTokenEOF || token.is("}"):
+		return nil
+	}
+	return p.errorf(token, "unexpected %q", token.Text)
+}
+
+func (p *dbmlParser) parse() error {
+	for {
+		p.skipNewlines()
+		token := p.peek()
+		switch {
+		case token.Kind == dbmlTokenEOF:
+			return nil
+		case token.isKeyword("Project"):
+			if err := p.parseProject(); err != nil {
+				return err
+			}
+		case token.isKeyword("Table"):
+			if err := p.parseTable(); err != nil {
+				return err
+			}
+		case token.isKeyword("Enum"):
+			if err := p.parseEnum(); err != nil {
+				return err
+			}
+		case token.isKeyword("Ref"):
+			if err := p.parseRefs(); err != nil {
+				return err
+			}
+		case token.isKeyword("TableGroup"), token.isKeyword("Note"), token.isKeyword("Records"), token.isKeyword("TablePartial"):
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+		default:
+			return p.errorf(token, "unexpected %q", token.Text)
+		}
+	}
+}
+
+func (p *dbmlParser) skipBlock() error {
+	for !p.peek().is("{") {
+		if token := p.next(); token.Kind == dbmlTokenEOF {
+			return p.errorf(token, "expected a block")
+		}
+	}
+	depth := 0
+	for {
+		token := p.next()
+		switch {
+		case token.Kind == dbmlTokenEOF:
+			return p.errorf(token, "unterminated block")
+		case token.is("{"):
+			depth++
+		case token.is("}"):
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// parseQualifiedName reads "name" or "schema.name"; the public schema maps
+// to the default namespace.
+func (p *dbmlParser) parseQualifiedName() (string, string, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return "", "", err
+	}
+	namespace := ""
+	if p.peek().is(".") {
+		p.next()
+		namespace = name
+		if name, err = p.expectName(); err != nil {
+			return "", "", err
+		}
+	}
+	if strings.EqualFold(namespace, "public") {
+		namespace = ""
+	}
+	return namespace, name, nil
+}
+
+func (p *dbmlParser) parseSettings() ([]dbmlSetting, error) {
+	if !p.peek().is("[") {
+		return nil, nil
+	}
+	p.next()
+
+	var settings []dbmlSetting
+	for {
+		p.skipNewlines()
+		if p.peek().is("]") {
+			p.next()
+			return settings, nil
+		}
+
+		var words []string
+		for p.peek().Kind == dbmlTokenIdent {
+			words = append(words, strings.ToLower(p.next().Text))
+		}
+		if len(words) == 0 {
+			return nil, p.errorf(p.peek(), "expected a setting, found %q", p.peek().Text)
+		}
+		setting := dbmlSetting{Key: strings.Join(words, " ")}
+
+		if p.peek().is(":") {
+			p.next()
+			if setting.Key == "ref" {
+				ref, err := p.parseInlineRef()
+				if err != nil {
+					return nil, err
+				}
+				setting.Ref = ref
+			} else {
+				depth := 0
+				for {
+					token := p.peek()
+					if token.Kind == dbmlTokenEOF || token.Kind == dbmlTokenNewline || (depth == 0 && (token.is(",") || token.is("]"))) {
+						break
+					}
+					if token.is("(") {
+						depth++
+					} else if token.is(")") {
+						depth--
+					}
+					setting.Value = append(setting.Value, p.next())
+				}
+			}
+		}
+		settings = append(settings, setting)
+
+		p.skipNewlines()
+		if p.peek().is(",") {
+			p.next()
+		} else if !p.peek().is("]") {
+			return nil, p.errorf(p.peek(), "expected \",\" or \"]\", found %q", p.peek().Text)
+		}
+	}
+}
+
+func (p *dbmlParser) parseNote() (string, error) {
+	p.next()
+	if p.peek().is(":") {
+		p.next()
+		token := p.next()
+		if token.Kind != dbmlTokenString {
+			return "", p.errorf(token, "expected a note string, found %q", token.Text)
+		}
+		return token.Text, p.endOfLine()
+	}
+
+	if err := p.expect("{"); err != nil {
+		return "", err
+	}
+	p.skipNewlines()
+	token := p.next()
+	if token.Kind != dbmlTokenString {
+		return "", p.errorf(token, "expected a note string, found %q", token.Text)
+	}
+	p.skipNewlines()
+	if err := p.expect("}"); err != nil {
+		return "", err
+	}
+	return token.Text, p.endOfLine()
+}
+
+func (p *dbmlParser) isNoteLine() bool {
+	return p.peek().isKeyword("Note") && (p.peekAt(1).is(":") || p.peekAt(1).is("{"))
+}
+
+func (p *dbmlParser) parseProject() error {
+	p.next()
+	if p.peek().isName() {
+		p.request.Name = p.next().Text
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	for {
+		p.skipNewlines()
+		token := p.peek()
+		switch {
+		case token.is("}"):
+			p.next()
+			return nil
+		case token.Kind == dbmlTokenEOF:
+			return p.errorf(token, "unterminated Project block")
+		case p.isNoteLine():
+			note, err := p.parseNote()
+			if err != nil {
+				return err
+			}
+			p.request.Description = note
+		case token.Kind == dbmlTokenIdent && p.peekAt(1).is(":"):
+			key := strings.ToLower(p.next().Text)
+			p.next()
+			value := p.next()
+			if key == "database_type" {
+				for databaseType, label := range dbmlDatabaseTypes {
+					if strings.EqualFold(label, value.Text) || strings.EqualFold(databaseType, value.Text) {
+						p.request.DatabaseType = databaseType
+					}
+				}
+			}
+			if err := p.endOfLine(); err != nil {
+				return err
+			}
+		default:
+			return p.errorf(token, "unexpected %q in Project", token.Text)
+		}
+	}
+}
+
+func (p *dbmlParser) parseEnum() error {
+	p.next()
+	namespace, name, err := p.parseQualifiedName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	enum := models.Enum{ID: newElementID("enum"), Namespace: namespace, Name: name}
+	for {
+		p.skipNewlines()
+		token := p.peek()
+		if token.is("}") {
+			p.next()
+			break
+		}
+		value, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		if _, err := p.parseSettings(); err != nil {
+			return err
+		}
+		enum.Values = append(enum.Values, value)
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+
+	p.request.Enums = append(p.request.Enums, enum)
+	return nil
+}
+
+func (p *dbmlParser) parseTable() error {
+	p.next()
+	namespace, name, err := p.parseQualifiedName()
+	if err != nil {
+		return err
+	}
+
+	table := models.Table{ID: newElementID("table"), Namespace: namespace, Name: name}
+	index := len(p.request.Tables)
+	if p.peek().isKeyword("as") {
+		p.next()
+		alias, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		p.aliases[strings.ToLower(alias)] = index
+	}
+
+	settings, err := p.parseSettings()
+	if err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		if setting.Key == "note" {
+			table.Description = setting.text()
+		}
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	var inlineRefs []dbmlRef
+	for {
+		p.skipNewlines()
+		token := p.peek()
+		switch {
+		case token.is("}"):
+			p.next()
+			p.request.Tables = append(p.request.Tables, table)
+			p.refs = append(p.refs, inlineRefs...)
+			return nil
+		case token.Kind == dbmlTokenEOF:
+			return p.errorf(token, "unterminated Table %q", name)
+		case p.isNoteLine():
+			note, err := p.parseNote()
+			if err != nil {
+				return err
+			}
+			table.Description = note
+		case token.isKeyword("indexes") && p.peekAt(1).is("{"):
+			if err := p.parseIndexes(&table); err != nil {
+				return err
+			}
+		case token.isKeyword("checks") && p.peekAt(1).is("{"):
+			if err := p.parseChecks(&table); err != nil {
+				return err
+			}
+		default:
+			field, refs, err := p.parseColumn(&table)
+			if err != nil {
+				return err
+			}
+			table.Fields = append(table.Fields, *field)
+			inlineRefs = append(inlineRefs, refs...)
+		}
+	}
+}
+
+func (p *dbmlParser) parseColumn(table *models.Table) (*models.Field, []dbmlRef, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, nil, err
+	}
+	field := &models.Field{ID: newElementID("f"), Name: name}
+	if err := p.parseColumnType(field); err != nil {
+		return nil, nil, err
+	}
+
+	settings, err := p.parseSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var refs []dbmlRef
+	increment := false
+	for _, setting := range settings {
+		switch setting.Key {
+		case "pk", "primary key":
+			field.IsPrimaryKey = true
+			field.IsNotNull = true
+		case "not null":
+			field.IsNotNull = true
+		case "unique":
+			field.IsUnique = true
+		case "increment":
+			increment = true
+		case "default":
+			field.DefaultValue = dbmlDefaultValue(setting.Value)
+		case "note":
+			field.Comment = setting.text()
+		case "ref":
+			ref := *setting.Ref
+			ref.Left = dbmlEndpoint{Table: []string{table.Namespace, table.Name}, Columns: []string{name}}
+			refs = append(refs, ref)
+		}
+	}
+
+	if increment {
+		switch strings.ToUpper(field.Type) {
+		case "INT", "INTEGER", "INT4":
+			field.Type = "SERIAL"
+		case "BIGINT", "INT8":
+			field.Type = "BIGSERIAL"
+		case "SMALLINT", "INT2":
+			field.Type = "SMALLSERIAL"
+		}
+	}
+
+	return field, refs, p.endOfLine()
+}
+
+// parseColumnType reads a type such as varchar(255), decimal(10,2), int[],
+// "timestamp with time zone" or schema.enum_name.
+func (p *dbmlParser) parseColumnType(field *models.Field) error {
+	token := p.next()
+	if !token.isName() {
+		return p.errorf(token, "expected a type for column %q, found %q", field.Name, token.Text)
+	}
+	base := token.Text
+	if p.peek().is(".") {
+		p.next()
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		base += "." + name
+	}
+
+	var args []string
+	if p.peek().is("(") {
+		p.next()
+		var current strings.Builder
+		for {
+			token := p.next()
+			if token.Kind == dbmlTokenEOF || token.Kind == dbmlTokenNewline {
+				return p.errorf(token, "unterminated type arguments for column %q", field.Name)
+			}
+			if token.is(")") {
+				break
+			}
+			if token.is(",") {
+				args = append(args, strings.TrimSpace(current.String()))
+				current.Reset()
+				continue
+			}
+			current.WriteString(token.Text)
+		}
+		args = append(args, strings.TrimSpace(current.String()))
+	}
+
+	suffix := ""
+	for p.peek().is("[") && p.peekAt(1).is("]") {
+		p.pos += 2
+		suffix += "[]"
+	}
+
+	field.Type = base
+	numbers := make([]int, 0, len(args))
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	upper := strings.ToUpper(base)
+	switch {
+	case suffix == "" && lengthTypes[upper] && len(args) == 1 && len(numbers) == 1:
+		field.Length = numbers[0]
+	case suffix == "" && decimalTypes[upper] && len(args) >= 1 && len(args) <= 2 && len(numbers) == len(args):
+		field.Precision = numbers[0]
+		if len(numbers) == 2 {
+			field.Scale = numbers[1]
+		}
+	case len(args) > 0:
+		field.Type += "(" + strings.Join(args, ",") + ")"
+	}
+	field.Type += suffix
+	return nil
+}
+
+func dbmlDefaultValue(tokens []dbmlToken) string {
+	if len(tokens) == 1 {
+		switch tokens[0].Kind {
+		case dbmlTokenString:
+			if numericLiteralPattern.MatchString(tokens[0].Text) || sqlKeywordDefaults[strings.ToUpper(tokens[0].Text)] {
+				return sqlString(tokens[0].Text)
+			}
+			return tokens[0].Text
+		case dbmlTokenExpression:
+			return tokens[0].Text
+		case dbmlTokenIdent:
+			return strings.ToUpper(tokens[0].Text)
+		}
+	}
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString(token.Text)
+	}
+	return b.String()
+}
+
+func (p *dbmlParser) parseIndexes(table *models.Table) error {
+	p.pos += 2
+	for {
+		p.skipNewlines()
+		token := p.peek()
+		if token.is("}") {
+			p.next()
+			return p.endOfLine()
+		}
+
+		var columns []models.IndexColumn
+		readColumn := func() error {
+			token := p.next()
+			switch {
+			case token.Kind == dbmlTokenExpression:
+				columns = append(columns, models.IndexColumn{Expression: token.Text})
+			case token.isName():
+				columns = append(columns, models.IndexColumn{FieldID: token.Text})
+			default:
+				return p.errorf(token, "expected an index column, found %q", token.Text)
+			}
+			return nil
+		}
+
+		if token.is("(") {
+			p.next()
+			for {
+				if err := readColumn(); err != nil {
+					return err
+				}
+				if p.peek().is(",") {
+					p.next()
+					continue
+				}
+				if err := p.expect(")"); err != nil {
+					return err
+				}
+				break
+			}
+		} else if err := readColumn(); err != nil {
+			return err
+		}
+
+		settings, err := p.parseSettings()
+		if err != nil {
+			return err
+		}
+
+		index := models.Index{}
+		primary := false
+		for _, setting := range settings {
+			switch setting.Key {
+			case "pk", "primary key":
+				primary = true
+			case "unique":
+				index.IsUnique = true
+			case "name":
+				index.Name = setting.text()
+			case "type":
+				index.Type = strings.ToLower(setting.text())
+			}
+		}
+
+		hasExpression := false
+		for _, column := range columns {
+			if column.Expression != "" {
+				hasExpression = true
+			}
+		}
+		switch {
+		case primary:
+			if hasExpression {
+				return p.errorf(token, "primary key of %q cannot contain expressions", table.Name)
+			}
+			table.PrimaryKey = nil
+			for _, column := range columns {
+				table.PrimaryKey = append(table.PrimaryKey, column.FieldID)
+			}
+		case hasExpression:
+			index.Columns = columns
+			table.Indexes = append(table.Indexes, index)
+		default:
+			for _, column := range columns {
+				index.Fields = append(index.Fields, column.FieldID)
+			}
+			table.Indexes = append(table.Indexes, index)
+		}
+
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dbmlParser) parseChecks(table *models.Table) error {
+	p.pos += 2
+	for {
+		p.skipNewlines()
+		token := p.next()
+		if token.is("}") {
+			return p.endOfLine()
+		}
+		if token.Kind != dbmlTokenExpression {
+			return p.errorf(token, "expected a check expression, found %q", token.Text)
+		}
+		constraint := models.Constraint{Type: ConstraintCheck, CheckCondition: token.Text}
+		settings, err := p.parseSettings()
+		if err != nil {
+			return err
+		}
+		for _, setting := range settings {
+			if setting.Key == "name" {
+				constraint.Name = setting.text()
+			}
+		}
+		table.Constraints = append(table.Constraints, constraint)
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dbmlParser) parseRefs() error {
+	p.next()
+	name := ""
+	if p.peek().isName() {
+		name = p.next().Text
+	}
+
+	if p.peek().is(":") {
+		p.next()
+		ref, err := p.parseRef(name)
+		if err != nil {
+			return err
+		}
+		p.refs = append(p.refs, *ref)
+		return p.endOfLine()
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.peek().is("}") {
+			p.next()
+			return p.endOfLine()
+		}
+		ref, err := p.parseRef(name)
+		if err != nil {
+			return err
+		}
+		p.refs = append(p.refs, *ref)
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dbmlParser) parseRef(name string) (*dbmlRef, error) {
+	line := p.peek().Line
+	left, err := p.parseEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	op, err := p.parseRefOp()
+	if err != nil {
+		return nil, err
+	}
+	right, err := p.parseEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &dbmlRef{Name: name, Left: *left, Op: op, Right: *right, Line: line}
+	settings, err := p.parseSettings()
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		switch setting.Key {
+		case "delete":
+			ref.OnDelete = strings.ToUpper(setting.text())
+		case "update":
+			ref.OnUpdate = strings.ToUpper(setting.text())
+		}
+	}
+	return ref, nil
+}
+
+func (p *dbmlParser) parseInlineRef() (*dbmlRef, error) {
+	line := p.peek().Line
+	op, err := p.parseRefOp()
+	if err != nil {
+		return nil, err
+	}
+	right, err := p.parseEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	return &dbmlRef{Op: op, Right: *right, Line: line}, nil
+}
+
+func (p *dbmlParser) parseRefOp() (string, error) {
+	token := p.next()
+	switch {
+	case token.is(">"), token.is("-"):
+		return token.Text, nil
+	case token.is("<"):
+		if p.peek().is(">") {
+			p.next()
+			return "<>", nil
+		}
+		return "<", nil
+	}
+	return "", p.errorf(token, "expected a relationship operator, found %q", token.Text)
+}
+
+// parseEndpoint reads table.column, schema.table.column or the composite
+// forms table.(a, b) and schema.table.(a, b).
+func (p *dbmlParser) parseEndpoint() (*dbmlEndpoint, error) {
+	endpoint := &dbmlEndpoint{}
+	var parts []string
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, name)
+		if !p.peek().is(".") {
+			break
+		}
+		p.next()
+		if p.peek().is("(") {
+			p.next()
+			for {
+				column, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				endpoint.Columns = append(endpoint.Columns, column)
+				if p.peek().is(",") {
+					p.next()
+					continue
+				}
+				if err := p.expect(")"); err != nil {
+					return nil, err
+				}
+				break
+			}
+			break
+		}
+	}
+
+	if len(endpoint.Columns) == 0 {
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("reference endpoint %q has no column", strings.Join(parts, "."))
+		}
+		endpoint.Columns = []string{parts[len(parts)-1]}
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("reference endpoint %q has too many parts", strings.Join(parts, "."))
+	}
+	endpoint.Table = parts
+	return endpoint, nil
+}
+
+func (p *dbmlParser) lookupTable(path []string) *models.Table {
+	tables := p.request.Tables
+	if len(path) == 1 {
+		if index, ok := p.aliases[strings.ToLower(path[0])]; ok {
+			return &tables[index]
+		}
+		var match *models.Table
+		for i := range tables {
+			if strings.EqualFold(tables[i].Name, path[0]) {
+				if tables[i].Namespace == "" {
+					return &tables[i]
+				}
+				if match == nil {
+					match = &tables[i]
+				}
+			}
+		}
+		return match
+	}
+
+	namespace := path[0]
+	if strings.EqualFold(namespace, "public") {
+		namespace = ""
+	}
+	for i := range tables {
+		if strings.EqualFold(tables[i].Namespace, namespace) && strings.EqualFold(tables[i].Name, path[1]) {
+			return &tables[i]
+		}
+	}
+	return nil
+}
+
+func (p *dbmlParser) resolveEndpoint(ref *dbmlRef, endpoint dbmlEndpoint) (*models.Table, []*models.Field, error) {
+	table := p.lookupTable(endpoint.Table)
+	if table == nil {
+		return nil, nil, fmt.Errorf("line %d: reference to unknown table %q", ref.Line, strings.Join(endpoint.Table, "."))
+	}
+	fields := make([]*models.Field, len(endpoint.Columns))
+	for i, column := range endpoint.Columns {
+		if fields[i] = findField(table, column); fields[i] == nil {
+			return nil, nil, fmt.Errorf("line %d: reference to unknown column %q of %q", ref.Line, column, table.Name)
+		}
+	}
+	return table, fields, nil
+}
+
+func (p *dbmlParser) resolve() error {
+	request := p.request
+
+	for i := range request.Tables {
+		table := &request.Tables[i]
+		for j := range table.Fields {
+			field := &table.Fields[j]
+			if resolveEnum(&models.Schema{Enums: request.Enums}, table.Namespace, field.Type) == nil {
+				field.Type = strings.ToUpper(field.Type)
+			}
+		}
+
+		if len(table.PrimaryKey) > 0 {
+			ids := resolveFieldIDs(table, table.PrimaryKey)
+			if len(ids) != len(table.PrimaryKey) {
+				return fmt.Errorf("primary key of %q references an unknown column", table.Name)
+			}
+			table.PrimaryKey = ids
+			for j := range table.Fields {
+				table.Fields[j].IsPrimaryKey = false
+			}
+			for _, id := range ids {
+				if field := findField(table, id); field != nil {
+					field.IsPrimaryKey = true
+					field.IsNotNull = true
+				}
+			}
+		}
+
+		for j := range table.Indexes {
+			index := &table.Indexes[j]
+			for k, ref := range index.Fields {
+				field := findField(table, ref)
+				if field == nil {
+					return fmt.Errorf("index on %q references unknown column %q", table.Name, ref)
+				}
+				index.Fields[k] = field.ID
+			}
+			for k, column := range index.Columns {
+				if column.FieldID == "" {
+					continue
+				}
+				field := findField(table, column.FieldID)
+				if field == nil {
+					return fmt.Errorf("index on %q references unknown column %q", table.Name, column.FieldID)
+				}
+				index.Columns[k].FieldID = field.ID
+			}
+		}
+	}
+
+	for i := range p.refs {
+		ref := &p.refs[i]
+		left, leftFields, err := p.resolveEndpoint(ref, ref.Left)
+		if err != nil {
+			return err
+		}
+		right, rightFields, err := p.resolveEndpoint(ref, ref.Right)
+		if err != nil {
+			return err
+		}
+		if len(leftFields) != len(rightFields) {
+			return fmt.Errorf("line %d: reference columns do not match in number", ref.Line)
+		}
+
+		from, fromFields, to, toFields := left, leftFields, right, rightFields
+		switch ref.Op {
+		case "<":
+			from, fromFields, to, toFields = right, rightFields, left, leftFields
+		case "<>":
+			return fmt.Errorf("line %d: many-to-many references are not supported; model them with a join table", ref.Line)
+		}
+
+		if len(fromFields) == 1 && ref.Name == "" && ref.OnDelete == "" && ref.OnUpdate == "" && fromFields[0].References == nil {
+			fromFields[0].IsForeignKey = true
+			fromFields[0].References = &models.Reference{Namespace: to.Namespace, TableID: to.ID, FieldID: toFields[0].ID}
+			continue
+		}
+
+		constraint := models.Constraint{
+			Name:               ref.Name,
+			Type:               ConstraintForeignKey,
+			ReferenceNamespace: to.Namespace,
+			ReferenceTable:     to.ID,
+			OnDelete:           ref.OnDelete,
+			OnUpdate:           ref.OnUpdate,
+		}
+		for k := range fromFields {
+			fromFields[k].IsForeignKey = true
+			constraint.Fields = append(constraint.Fields, fromFields[k].ID)
+			constraint.ReferenceFields = append(constraint.ReferenceFields, toFields[k].ID)
+		}
+		from.Constraints = append(from.Constraints, constraint)
+	}
+
+	request.Namespaces = collectNamespaces(request.Tables, request.Enums)
+	arrangeTables(request.Tables)
+	return nil
+}
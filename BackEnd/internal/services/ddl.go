@@ -0,0 +1,862 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+type SQLDialect string
+
+const (
+	DialectPostgreSQL SQLDialect = "postgresql"
+	DialectMySQL      SQLDialect = "mysql"
+	DialectSQLite     SQLDialect = "sqlite"
+)
+
+func ParseSQLDialect(value string) (SQLDialect, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "postgresql", "postgres", "pg":
+		return DialectPostgreSQL, true
+	case "mysql", "mariadb":
+		return DialectMySQL, true
+	case "sqlite", "sqlite3":
+		return DialectSQLite, true
+	}
+	return "", false
+}
+
+var (
+	numericLiteralPattern  = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	functionLiteralPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\(.*\)$`)
+)
+
+var sqlKeywordDefaults = map[string]bool{
+	"NULL":              true,
+	"TRUE":              true,
+	"FALSE":             true,
+	"CURRENT_TIMESTAMP": true,
+	"CURRENT_DATE":      true,
+	"CURRENT_TIME":      true,
+	"LOCALTIMESTAMP":    true,
+}
+
+// defaultFunctions maps common default functions, lower-cased and without
+// spaces, to their equivalent in each dialect. Dialects left out keep the
+// function as written.
+var defaultFunctions = map[string]map[SQLDialect]string{
+	"now()": {
+		DialectMySQL:  "CURRENT_TIMESTAMP",
+		DialectSQLite: "CURRENT_TIMESTAMP",
+	},
+	"current_timestamp()": {
+		DialectPostgreSQL: "CURRENT_TIMESTAMP",
+		DialectMySQL:      "CURRENT_TIMESTAMP",
+		DialectSQLite:     "CURRENT_TIMESTAMP",
+	},
+	"localtimestamp()": {
+		DialectPostgreSQL: "LOCALTIMESTAMP",
+		DialectMySQL:      "LOCALTIMESTAMP",
+		DialectSQLite:     "CURRENT_TIMESTAMP",
+	},
+	"datetime('now')": {
+		DialectPostgreSQL: "CURRENT_TIMESTAMP",
+		DialectMySQL:      "CURRENT_TIMESTAMP",
+	},
+	"curdate()": {
+		DialectPostgreSQL: "CURRENT_DATE",
+		DialectSQLite:     "CURRENT_DATE",
+	},
+	"current_date()": {
+		DialectPostgreSQL: "CURRENT_DATE",
+		DialectSQLite:     "CURRENT_DATE",
+	},
+	"curtime()": {
+		DialectPostgreSQL: "CURRENT_TIME",
+		DialectSQLite:     "CURRENT_TIME",
+	},
+	"current_time()": {
+		DialectPostgreSQL: "CURRENT_TIME",
+		DialectSQLite:     "CURRENT_TIME",
+	},
+	"gen_random_uuid()": {
+		DialectMySQL:  "UUID()",
+		DialectSQLite: "lower(hex(randomblob(16)))",
+	},
+	"uuid_generate_v4()": {
+		DialectMySQL:  "UUID()",
+		DialectSQLite: "lower(hex(randomblob(16)))",
+	},
+	"uuid()": {
+		DialectPostgreSQL: "gen_random_uuid()",
+		DialectSQLite:     "lower(hex(randomblob(16)))",
+	},
+}
+
+var dialectTypeAliases = map[SQLDialect]map[string]string{
+	DialectPostgreSQL: {
+		"DATETIME":   "TIMESTAMP",
+		"TINYINT":    "SMALLINT",
+		"MEDIUMINT":  "INTEGER",
+		"DOUBLE":     "DOUBLE PRECISION",
+		"BLOB":       "BYTEA",
+		"LONGTEXT":   "TEXT",
+		"MEDIUMTEXT": "TEXT",
+		"TINYTEXT":   "TEXT",
+	},
+	DialectMySQL: {
+		"UUID":             "CHAR(36)",
+		"JSONB":            "JSON",
+		"BYTEA":            "BLOB",
+		"TIMESTAMPTZ":      "TIMESTAMP",
+		"DOUBLE PRECISION": "DOUBLE",
+		"BIGSERIAL":        "BIGINT AUTO_INCREMENT",
+		"SMALLSERIAL":      "SMALLINT AUTO_INCREMENT",
+	},
+	DialectSQLite: {
+		"SERIAL":      "INTEGER",
+		"BIGSERIAL":   "INTEGER",
+		"SMALLSERIAL": "INTEGER",
+		"UUID":        "TEXT",
+		"JSONB":       "TEXT",
+		"JSON":        "TEXT",
+		"BYTEA":       "BLOB",
+		"TIMESTAMPTZ": "TIMESTAMP",
+	},
+}
+
+var lengthTypes = map[string]bool{
+	"VARCHAR":   true,
+	"CHAR":      true,
+	"CHARACTER": true,
+	"NVARCHAR":  true,
+	"NCHAR":     true,
+	"VARBINARY": true,
+	"BINARY":    true,
+	"BIT":       true,
+}
+
+var decimalTypes = map[string]bool{
+	"DECIMAL": true,
+	"NUMERIC": true,
+}
+
+type ddlGenerator struct {
+	schema     *models.Schema
+	dialect    SQLDialect
+	tablesByID map[string]*models.Table
+}
+
+func GenerateDDL(schema *models.Schema, dialect SQLDialect) string {
+	return newDDLGenerator(schema, dialect).generate()
+}
+
+func newDDLGenerator(schema *models.Schema, dialect SQLDialect) *ddlGenerator {
+	g := &ddlGenerator{
+		schema:     schema,
+		dialect:    dialect,
+		tablesByID: make(map[string]*models.Table, len(schema.Tables)),
+	}
+	for i := range schema.Tables {
+		g.tablesByID[schema.Tables[i].ID] = &schema.Tables[i]
+	}
+	return g
+}
+
+// ddlStatement is one generated statement together with the schema element
+// it came from, so failures can be traced back to the designer.
+type ddlStatement struct {
+	SQL     string
+	Element string
+	ID      string
+	Table   *models.Table
+}
+
+const (
+	DDLElementNamespace  = "namespace"
+	DDLElementEnum       = "enum"
+	DDLElementTable      = "table"
+	DDLElementPartition  = "partition"
+	DDLElementComment    = "comment"
+	DDLElementIndex      = "index"
+	DDLElementForeignKey = "foreign_key"
+	DDLElementView       = "view"
+)
+
+func (g *ddlGenerator) generate() string {
+	statements := g.statements()
+	sql := make([]string, len(statements))
+	for i, statement := range statements {
+		sql[i] = statement.SQL
+	}
+
+	header := fmt.Sprintf("-- %s\n-- Generated by Schema Builder for %s", g.schema.Name, g.dialect)
+	return header + "\n\n" + strings.Join(sql, "\n\n") + "\n"
+}
+
+func (g *ddlGenerator) statements() []ddlStatement {
+	var statements []ddlStatement
+	add := func(element, id string, table *models.Table, sql ...string) {
+		for _, statement := range sql {
+			statements = append(statements, ddlStatement{SQL: statement, Element: element, ID: id, Table: table})
+		}
+	}
+
+	for _, statement := range g.namespaceStatements() {
+		add(DDLElementNamespace, "", nil, statement)
+	}
+	// Enum statements are generated one per enum, in schema order.
+	for i, statement := range g.enumStatements() {
+		add(DDLElementEnum, g.schema.Enums[i].ID, nil, statement)
+	}
+
+	for i := range g.schema.Tables {
+		table := &g.schema.Tables[i]
+		add(DDLElementTable, table.ID, table, g.createTable(table))
+		add(DDLElementPartition, table.ID, table, g.partitionStatements(table)...)
+		add(DDLElementComment, table.ID, table, g.commentStatements(table)...)
+	}
+	for i := range g.schema.Tables {
+		table := &g.schema.Tables[i]
+		add(DDLElementIndex, table.ID, table, g.indexStatements(table)...)
+	}
+	if g.dialect != DialectSQLite {
+		for i := range g.schema.Tables {
+			table := &g.schema.Tables[i]
+			add(DDLElementForeignKey, table.ID, table, g.foreignKeyStatements(table)...)
+		}
+	}
+	for _, view := range g.schema.Views {
+		add(DDLElementView, view.ID, nil, fmt.Sprintf("CREATE VIEW %s AS\n%s;",
+			g.objectName(view.Namespace, view.Name),
+			strings.TrimSuffix(strings.TrimSpace(view.Definition), ";")))
+	}
+
+	return statements
+}
+
+func (g *ddlGenerator) quote(name string) string {
+	if g.dialect == DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// SQLite has no schemas, so namespaced objects are flattened into a prefixed
+// name instead of being qualified.
+func (g *ddlGenerator) objectName(namespace, name string) string {
+	if namespace == "" {
+		return g.quote(name)
+	}
+	if g.dialect == DialectSQLite {
+		return g.quote(namespace + "_" + name)
+	}
+	return g.quote(namespace) + "." + g.quote(name)
+}
+
+func (g *ddlGenerator) tableName(table *models.Table) string {
+	return g.objectName(table.Namespace, table.Name)
+}
+
+func (g *ddlGenerator) namespaceStatements() []string {
+	if g.dialect == DialectSQLite {
+		return nil
+	}
+
+	var statements []string
+	comments := make(map[string]string)
+	var order []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			order = append(order, name)
+		}
+	}
+
+	for _, namespace := range g.schema.Namespaces {
+		add(namespace.Name)
+		comments[namespace.Name] = namespace.Comment
+	}
+	for _, table := range g.schema.Tables {
+		add(table.Namespace)
+	}
+	for _, enum := range g.schema.Enums {
+		add(enum.Namespace)
+	}
+	for _, view := range g.schema.Views {
+		add(view.Namespace)
+	}
+
+	for _, name := range order {
+		statements = append(statements, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", g.quote(name)))
+		if comment := comments[name]; comment != "" && g.dialect == DialectPostgreSQL {
+			statements = append(statements, fmt.Sprintf("COMMENT ON SCHEMA %s IS %s;", g.quote(name), sqlString(comment)))
+		}
+	}
+
+	return statements
+}
+
+func (g *ddlGenerator) enumStatements() []string {
+	if g.dialect != DialectPostgreSQL {
+		return nil
+	}
+
+	var statements []string
+	for _, enum := range g.schema.Enums {
+		statements = append(statements, fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);",
+			g.objectName(enum.Namespace, enum.Name), sqlStringList(enum.Values)))
+	}
+	return statements
+}
+
+func (g *ddlGenerator) createTable(table *models.Table) string {
+	var lines []string
+
+	for _, field := range table.Fields {
+		lines = append(lines, "  "+g.columnDefinition(table, &field))
+	}
+
+	if primaryKeys := primaryKeyFields(table); len(primaryKeys) > 0 {
+		lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", g.columnList(primaryKeys)))
+	}
+
+	for _, constraint := range table.Constraints {
+		if definition := g.inlineConstraint(table, &constraint); definition != "" {
+			lines = append(lines, "  "+definition)
+		}
+	}
+
+	if g.dialect == DialectSQLite {
+		for _, field := range table.Fields {
+			if clause := g.referenceClause(&field); clause != "" {
+				lines = append(lines, fmt.Sprintf("  FOREIGN KEY (%s) %s", g.quote(field.Name), clause))
+			}
+		}
+	}
+
+	var notes []string
+	for _, issue := range unsupportedTableOptions(g.dialect, table) {
+		notes = append(notes, fmt.Sprintf("-- table %s: %s; omitted", table.Name, issue))
+	}
+
+	statement := fmt.Sprintf("CREATE TABLE %s (\n%s\n)%s;", g.tableName(table), strings.Join(lines, ",\n"), g.tableSuffix(table))
+	return strings.Join(append(notes, statement), "\n")
+}
+
+func (g *ddlGenerator) tableSuffix(table *models.Table) string {
+	var parts []string
+	options := table.Options
+	if options == nil {
+		options = &models.TableOptions{}
+	}
+
+	switch g.dialect {
+	case DialectPostgreSQL:
+		if partitioning := options.Partitioning; partitioning != nil {
+			var keys []*models.Field
+			for _, ref := range partitioning.Fields {
+				if field := findField(table, ref); field != nil {
+					keys = append(keys, field)
+				}
+			}
+			if len(keys) > 0 {
+				parts = append(parts, fmt.Sprintf("PARTITION BY %s (%s)", strings.ToUpper(partitionStrategy(partitioning)), g.columnList(keys)))
+			}
+		}
+		if options.Tablespace != "" {
+			parts = append(parts, "TABLESPACE "+g.quote(options.Tablespace))
+		}
+	case DialectMySQL:
+		if options.Engine != "" {
+			parts = append(parts, "ENGINE="+options.Engine)
+		}
+		if options.Charset != "" {
+			parts = append(parts, "DEFAULT CHARSET="+options.Charset)
+		}
+		if options.Collation != "" {
+			parts = append(parts, "COLLATE="+options.Collation)
+		}
+		if table.Description != "" {
+			parts = append(parts, "COMMENT="+sqlString(table.Description))
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " ")
+}
+
+func (g *ddlGenerator) partitionStatements(table *models.Table) []string {
+	if g.dialect != DialectPostgreSQL || table.Options == nil || table.Options.Partitioning == nil {
+		return nil
+	}
+
+	partitioning := table.Options.Partitioning
+	var statements []string
+	for _, partition := range partitioning.Partitions {
+		var bound string
+		switch {
+		case partition.IsDefault:
+			bound = "DEFAULT"
+		case partitionStrategy(partitioning) == PartitionRange:
+			bound = fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", strings.Join(partition.From, ", "), strings.Join(partition.To, ", "))
+		case partitionStrategy(partitioning) == PartitionList:
+			bound = fmt.Sprintf("FOR VALUES IN (%s)", strings.Join(partition.In, ", "))
+		case partitionStrategy(partitioning) == PartitionHash:
+			bound = fmt.Sprintf("FOR VALUES WITH (MODULUS %d, REMAINDER %d)", partition.Modulus, partition.Remainder)
+		default:
+			continue
+		}
+
+		statement := fmt.Sprintf("CREATE TABLE %s PARTITION OF %s %s",
+			g.objectName(table.Namespace, partition.Name), g.tableName(table), bound)
+		if partition.Tablespace != "" {
+			statement += " TABLESPACE " + g.quote(partition.Tablespace)
+		}
+		statements = append(statements, statement+";")
+	}
+
+	return statements
+}
+
+func (g *ddlGenerator) columnDefinition(table *models.Table, field *models.Field) string {
+	definition := g.quote(field.Name) + " " + g.columnType(table, field)
+
+	if field.Charset != "" && g.dialect == DialectMySQL {
+		definition += " CHARACTER SET " + field.Charset
+	}
+	if field.Collation != "" && g.supportsCollation(field.Collation) {
+		if g.dialect == DialectPostgreSQL {
+			definition += " COLLATE " + g.quote(field.Collation)
+		} else {
+			definition += " COLLATE " + field.Collation
+		}
+	}
+
+	if field.IsNotNull {
+		definition += " NOT NULL"
+	}
+	if field.IsUnique && !field.IsPrimaryKey {
+		definition += " UNIQUE"
+	}
+	if field.DefaultValue != "" {
+		definition += " DEFAULT " + sqlDefault(field.DefaultValue, g.dialect)
+	}
+	if enum := resolveEnum(g.schema, table.Namespace, field.Type); enum != nil && g.dialect == DialectSQLite {
+		definition += fmt.Sprintf(" CHECK (%s IN (%s))", g.quote(field.Name), sqlStringList(enum.Values))
+	}
+	if field.Comment != "" && g.dialect == DialectMySQL {
+		definition += " COMMENT " + sqlString(field.Comment)
+	}
+
+	return definition
+}
+
+// Collation names are dialect specific, so they are only carried over to the
+// schema's own database type. SQLite knows just its three built-in ones.
+func (g *ddlGenerator) supportsCollation(collation string) bool {
+	if g.dialect == DialectSQLite {
+		switch strings.ToUpper(collation) {
+		case "BINARY", "NOCASE", "RTRIM":
+			return true
+		}
+		return false
+	}
+	target, ok := ParseSQLDialect(g.schema.DatabaseType)
+	return !ok || target == g.dialect
+}
+
+func (g *ddlGenerator) columnType(table *models.Table, field *models.Field) string {
+	if enum := resolveEnum(g.schema, table.Namespace, field.Type); enum != nil {
+		switch g.dialect {
+		case DialectPostgreSQL:
+			return g.objectName(enum.Namespace, enum.Name)
+		case DialectMySQL:
+			return fmt.Sprintf("ENUM(%s)", sqlStringList(enum.Values))
+		default:
+			return "TEXT"
+		}
+	}
+
+	return renderColumnType(g.dialect, field)
+}
+
+func isSerialField(field *models.Field) bool {
+	base, _ := splitColumnType(field)
+	switch base {
+	case "SERIAL", "BIGSERIAL", "SMALLSERIAL":
+		return true
+	}
+	return false
+}
+
+func renderColumnType(dialect SQLDialect, field *models.Field) string {
+	raw := strings.TrimSpace(field.Type)
+	if raw == "" {
+		raw = "TEXT"
+	}
+
+	base, args := raw, ""
+	if idx := strings.Index(raw, "("); idx >= 0 {
+		base, args = strings.TrimSpace(raw[:idx]), raw[idx:]
+	}
+	base = strings.ToUpper(base)
+
+	if alias, ok := dialectTypeAliases[dialect][base]; ok {
+		if strings.Contains(alias, "(") {
+			return alias
+		}
+		base = alias
+	}
+
+	if args == "" {
+		switch {
+		case lengthTypes[base] && field.Length > 0:
+			args = fmt.Sprintf("(%d)", field.Length)
+		case decimalTypes[base] && field.Precision > 0 && field.Scale > 0:
+			args = fmt.Sprintf("(%d, %d)", field.Precision, field.Scale)
+		case decimalTypes[base] && field.Precision > 0:
+			args = fmt.Sprintf("(%d)", field.Precision)
+		case dialect == DialectMySQL && (base == "VARCHAR" || base == "VARBINARY"):
+			args = "(255)"
+		}
+	}
+
+	return base + args
+}
+
+func (g *ddlGenerator) inlineConstraint(table *models.Table, constraint *models.Constraint) string {
+	name := ""
+	if constraint.Name != "" {
+		name = "CONSTRAINT " + g.quote(constraint.Name) + " "
+	}
+
+	switch constraintKind(constraint) {
+	case ConstraintUnique:
+		fields := constraintFields(table, constraint)
+		if len(fields) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%sUNIQUE (%s)", name, g.columnList(fields))
+	case ConstraintCheck:
+		if constraint.CheckCondition == "" {
+			return ""
+		}
+		return fmt.Sprintf("%sCHECK (%s)", name, constraint.CheckCondition)
+	case ConstraintForeignKey:
+		if g.dialect != DialectSQLite {
+			return ""
+		}
+		fields := constraintFields(table, constraint)
+		clause := g.constraintReferenceClause(constraint, len(fields))
+		if len(fields) == 0 || clause == "" {
+			return ""
+		}
+		return fmt.Sprintf("%sFOREIGN KEY (%s) %s", name, g.columnList(fields), clause)
+	}
+
+	return ""
+}
+
+func (g *ddlGenerator) columnList(fields []*models.Field) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = g.quote(field.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (g *ddlGenerator) referenceClause(field *models.Field) string {
+	if field.References == nil {
+		return ""
+	}
+	target, ok := g.tablesByID[field.References.TableID]
+	if !ok {
+		return ""
+	}
+	targetField := findField(target, field.References.FieldID)
+	if targetField == nil {
+		return ""
+	}
+	return fmt.Sprintf("REFERENCES %s (%s)", g.tableName(target), g.quote(targetField.Name))
+}
+
+func (g *ddlGenerator) constraintReferenceClause(constraint *models.Constraint, arity int) string {
+	target := findConstraintTarget(g.schema, constraint)
+	if target == nil {
+		return ""
+	}
+	targetFields := constraintReferenceFields(target, constraint)
+	if len(targetFields) == 0 || len(targetFields) != arity {
+		return ""
+	}
+
+	clause := fmt.Sprintf("REFERENCES %s (%s)", g.tableName(target), g.columnList(targetFields))
+	if constraint.OnDelete != "" {
+		clause += " ON DELETE " + strings.ToUpper(constraint.OnDelete)
+	}
+	if constraint.OnUpdate != "" {
+		clause += " ON UPDATE " + strings.ToUpper(constraint.OnUpdate)
+	}
+	return clause
+}
+
+func (g *ddlGenerator) foreignKeyStatements(table *models.Table) []string {
+	var statements []string
+
+	for _, field := range table.Fields {
+		clause := g.referenceClause(&field)
+		if clause == "" {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) %s;",
+			g.tableName(table),
+			g.quote(fmt.Sprintf("fk_%s_%s", table.Name, field.Name)),
+			g.quote(field.Name),
+			clause))
+	}
+
+	for _, constraint := range table.Constraints {
+		if constraintKind(&constraint) != ConstraintForeignKey {
+			continue
+		}
+		fields := constraintFields(table, &constraint)
+		clause := g.constraintReferenceClause(&constraint, len(fields))
+		if len(fields) == 0 || clause == "" {
+			continue
+		}
+		name := constraint.Name
+		if name == "" {
+			names := make([]string, len(fields))
+			for i, field := range fields {
+				names[i] = field.Name
+			}
+			name = fmt.Sprintf("fk_%s_%s", table.Name, strings.Join(names, "_"))
+		}
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) %s;",
+			g.tableName(table), g.quote(name), g.columnList(fields), clause))
+	}
+
+	return statements
+}
+
+func (g *ddlGenerator) indexStatements(table *models.Table) []string {
+	var statements []string
+
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+
+		var parts []string
+		for _, column := range indexColumns(index) {
+			if part := g.indexColumn(table, column); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+
+		name := indexDisplayName(table, index)
+		if g.dialect == DialectSQLite && table.Namespace != "" {
+			name = table.Namespace + "_" + name
+		}
+
+		var notes []string
+		for _, issue := range unsupportedIndexFeatures(g.dialect, index) {
+			notes = append(notes, fmt.Sprintf("-- index %s: %s; omitted", name, issue))
+		}
+
+		method := indexMethod(index)
+		supported := method != "" && indexMethodsByDialect[g.dialect][method]
+
+		kind := "INDEX"
+		switch {
+		case g.dialect == DialectMySQL && supported && method == IndexMethodFullText:
+			kind = "FULLTEXT INDEX"
+		case g.dialect == DialectMySQL && supported && method == IndexMethodSpatial:
+			kind = "SPATIAL INDEX"
+		case index.IsUnique:
+			kind = "UNIQUE INDEX"
+		}
+
+		statement := fmt.Sprintf("CREATE %s %s ON %s", kind, g.quote(name), g.tableName(table))
+		if g.dialect == DialectPostgreSQL && supported {
+			statement += " USING " + method
+		}
+		statement += fmt.Sprintf(" (%s)", strings.Join(parts, ", "))
+
+		if g.dialect == DialectMySQL && supported && (method == IndexMethodBTree || method == IndexMethodHash) {
+			statement += " USING " + strings.ToUpper(method)
+		}
+		if g.dialect == DialectPostgreSQL && len(index.Include) > 0 {
+			var included []*models.Field
+			for _, ref := range index.Include {
+				if field := findField(table, ref); field != nil {
+					included = append(included, field)
+				}
+			}
+			if len(included) > 0 {
+				statement += fmt.Sprintf(" INCLUDE (%s)", g.columnList(included))
+			}
+		}
+		if where := strings.TrimSpace(index.Where); where != "" && g.dialect != DialectMySQL {
+			statement += " WHERE " + where
+		}
+
+		statements = append(statements, strings.Join(append(notes, statement+";"), "\n"))
+	}
+
+	return statements
+}
+
+func (g *ddlGenerator) indexColumn(table *models.Table, column models.IndexColumn) string {
+	var part string
+	switch {
+	case column.FieldID != "":
+		field := findField(table, column.FieldID)
+		if field == nil {
+			return ""
+		}
+		part = g.quote(field.Name)
+	case strings.TrimSpace(column.Expression) != "":
+		part = "(" + strings.TrimSpace(column.Expression) + ")"
+	default:
+		return ""
+	}
+
+	if column.Collation != "" && g.dialect != DialectMySQL && g.supportsCollation(column.Collation) {
+		if g.dialect == DialectPostgreSQL {
+			part += " COLLATE " + g.quote(column.Collation)
+		} else {
+			part += " COLLATE " + column.Collation
+		}
+	}
+	if column.OpClass != "" && g.dialect == DialectPostgreSQL {
+		part += " " + column.OpClass
+	}
+	if order := strings.ToUpper(column.Order); order == "ASC" || order == "DESC" {
+		part += " " + order
+	}
+	if nulls := strings.ToUpper(column.Nulls); (nulls == "FIRST" || nulls == "LAST") && g.dialect == DialectPostgreSQL {
+		part += " NULLS " + nulls
+	}
+
+	return part
+}
+
+func (g *ddlGenerator) commentStatements(table *models.Table) []string {
+	if g.dialect != DialectPostgreSQL {
+		return nil
+	}
+
+	var statements []string
+	if table.Description != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", g.tableName(table), sqlString(table.Description)))
+	}
+	for _, field := range table.Fields {
+		if field.Comment == "" {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;",
+			g.tableName(table), g.quote(field.Name), sqlString(field.Comment)))
+	}
+	return statements
+}
+
+func findTable(schema *models.Schema, ref string) *models.Table {
+	for i := range schema.Tables {
+		if schema.Tables[i].ID == ref {
+			return &schema.Tables[i]
+		}
+	}
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		if strings.EqualFold(table.Name, ref) || strings.EqualFold(QualifiedName(table.Namespace, table.Name), ref) {
+			return table
+		}
+	}
+	return nil
+}
+
+func findConstraintTarget(schema *models.Schema, constraint *models.Constraint) *models.Table {
+	if constraint.ReferenceNamespace != "" {
+		if table := findTable(schema, QualifiedName(constraint.ReferenceNamespace, constraint.ReferenceTable)); table != nil {
+			return table
+		}
+	}
+	return findTable(schema, constraint.ReferenceTable)
+}
+
+func findField(table *models.Table, ref string) *models.Field {
+	for i := range table.Fields {
+		if table.Fields[i].ID == ref {
+			return &table.Fields[i]
+		}
+	}
+	for i := range table.Fields {
+		if strings.EqualFold(table.Fields[i].Name, ref) {
+			return &table.Fields[i]
+		}
+	}
+	return nil
+}
+
+// A field whose type names an enum resolves to the enum in its own namespace
+// first, then in the default namespace; qualified type names match directly.
+func resolveEnum(schema *models.Schema, namespace, typeName string) *models.Enum {
+	typeName = strings.TrimSpace(typeName)
+	if typeName == "" || len(schema.Enums) == 0 {
+		return nil
+	}
+
+	for _, candidate := range []string{QualifiedName(namespace, typeName), typeName} {
+		for i := range schema.Enums {
+			enum := &schema.Enums[i]
+			if strings.EqualFold(QualifiedName(enum.Namespace, enum.Name), candidate) {
+				return enum
+			}
+		}
+	}
+	return nil
+}
+
+func sqlString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func sqlStringList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = sqlString(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sqlDefault renders a default value. SQLite and MySQL only take function
+// calls as defaults when they are wrapped in parentheses.
+func sqlDefault(value string, dialect SQLDialect) string {
+	trimmed := strings.TrimSpace(value)
+	switch {
+	case sqlKeywordDefaults[strings.ToUpper(trimmed)]:
+		return strings.ToUpper(trimmed)
+	case numericLiteralPattern.MatchString(trimmed):
+		return trimmed
+	case functionLiteralPattern.MatchString(trimmed):
+		key := strings.ToLower(strings.Join(strings.Fields(trimmed), ""))
+		if mapped, ok := defaultFunctions[key][dialect]; ok {
+			trimmed = mapped
+		}
+		if dialect != DialectPostgreSQL && functionLiteralPattern.MatchString(trimmed) {
+			return "(" + trimmed + ")"
+		}
+		return trimmed
+	case len(trimmed) >= 2 && strings.HasPrefix(trimmed, "'") && strings.HasSuffix(trimmed, "'"):
+		return trimmed
+	}
+	return sqlString(value)
+}
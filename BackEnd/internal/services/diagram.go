@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	diagramMargin        = 40
+	diagramCharWidth     = 7
+	diagramHeaderHeight  = 36
+	diagramRowHeight     = 24
+	diagramMinTableWidth = 240
+	diagramPadding       = 12
+	diagramMarkerWidth   = 30
+	diagramMaxLabel      = 48
+)
+
+type DiagramOptions struct {
+	Format      string
+	Theme       string
+	Namespace   string
+	Tables      []string
+	HideColumns bool
+}
+
+type diagramTheme struct {
+	Background color.RGBA
+	Border     color.RGBA
+	HeaderFill color.RGBA
+	HeaderText color.RGBA
+	BodyFill   color.RGBA
+	Text       color.RGBA
+	MutedText  color.RGBA
+	Divider    color.RGBA
+	Edge       color.RGBA
+	PrimaryKey color.RGBA
+	ForeignKey color.RGBA
+	Unique     color.RGBA
+}
+
+// The light theme follows the colours of the designer canvas.
+var diagramThemes = map[string]diagramTheme{
+	"light": {
+		Background: color.RGBA{0xf9, 0xfa, 0xfb, 0xff},
+		Border:     color.RGBA{0xe5, 0xe7, 0xeb, 0xff},
+		HeaderFill: color.RGBA{0x00, 0x00, 0x00, 0xff},
+		HeaderText: color.RGBA{0xff, 0xff, 0xff, 0xff},
+		BodyFill:   color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Text:       color.RGBA{0x37, 0x41, 0x51, 0xff},
+		MutedText:  color.RGBA{0x6b, 0x72, 0x80, 0xff},
+		Divider:    color.RGBA{0xf3, 0xf4, 0xf6, 0xff},
+		Edge:       color.RGBA{0x63, 0x66, 0xf1, 0xff},
+		PrimaryKey: color.RGBA{0xea, 0xb3, 0x08, 0xff},
+		ForeignKey: color.RGBA{0x3b, 0x82, 0xf6, 0xff},
+		Unique:     color.RGBA{0x22, 0xc5, 0x5e, 0xff},
+	},
+	"dark": {
+		Background: color.RGBA{0x11, 0x18, 0x27, 0xff},
+		Border:     color.RGBA{0x37, 0x41, 0x51, 0xff},
+		HeaderFill: color.RGBA{0x4f, 0x46, 0xe5, 0xff},
+		HeaderText: color.RGBA{0xff, 0xff, 0xff, 0xff},
+		BodyFill:   color.RGBA{0x1f, 0x29, 0x37, 0xff},
+		Text:       color.RGBA{0xe5, 0xe7, 0xeb, 0xff},
+		MutedText:  color.RGBA{0x9c, 0xa3, 0xaf, 0xff},
+		Divider:    color.RGBA{0x37, 0x41, 0x51, 0xff},
+		Edge:       color.RGBA{0xa5, 0xb4, 0xfc, 0xff},
+		PrimaryKey: color.RGBA{0xfa, 0xcc, 0x15, 0xff},
+		ForeignKey: color.RGBA{0x60, 0xa5, 0xfa, 0xff},
+		Unique:     color.RGBA{0x4a, 0xde, 0x80, 0xff},
+	},
+}
+
+type diagramShapeKind int
+
+const (
+	shapeRect diagramShapeKind = iota
+	shapeLine
+	shapeCircle
+	shapeText
+)
+
+// diagramShape is a drawing primitive in layout units. The SVG and PNG
+// renderers draw the same list of shapes so both outputs look alike.
+type diagramShape struct {
+	Kind   diagramShapeKind
+	Rect   image.Rectangle
+	Points []image.Point
+	Radius int
+	Fill   color.RGBA
+	Stroke color.RGBA
+	Text   string
+	Bold   bool
+	// AlignEnd anchors text at its right edge instead of its left edge.
+	AlignEnd bool
+}
+
+type diagram struct {
+	Width      int
+	Height     int
+	Background color.RGBA
+	Shapes     []diagramShape
+}
+
+type diagramBox struct {
+	Table  *models.Table
+	Bounds image.Rectangle
+	Rows   map[*models.Field]int
+}
+
+func (s *ExportService) RenderDiagram(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, opts DiagramOptions) (*ExportResult, error) {
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Namespace != "" {
+		schema = FilterSchemaByNamespace(schema, opts.Namespace)
+	}
+
+	result, err := RenderDiagram(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infof("Diagram of schema %s rendered as %s", id.Hex(), opts.Format)
+	return result, nil
+}
+
+func RenderDiagram(schema *models.Schema, opts DiagramOptions) (*ExportResult, error) {
+	themeName := strings.ToLower(opts.Theme)
+	if themeName == "" {
+		themeName = "light"
+	}
+	theme, ok := diagramThemes[themeName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported diagram theme: %s", opts.Theme)
+	}
+
+	format := strings.ToLower(opts.Format)
+	if format != "svg" && format != "png" {
+		return nil, fmt.Errorf("unsupported diagram format: %s", opts.Format)
+	}
+
+	tables, err := selectDiagramTables(schema, opts.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := layoutDiagram(schema, tables, theme, opts.HideColumns)
+
+	if format == "svg" {
+		return &ExportResult{
+			Content:     renderDiagramSVG(layout),
+			ContentType: "image/svg+xml",
+			FileName:    exportFileName(schema.Name, "svg"),
+		}, nil
+	}
+
+	content, err := renderDiagramPNG(layout)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportResult{
+		Content:     content,
+		ContentType: "image/png",
+		FileName:    exportFileName(schema.Name, "png"),
+	}, nil
+}
+
+// selectDiagramTables resolves table IDs, names or qualified names; an empty
+// selection keeps every table.
+func selectDiagramTables(schema *models.Schema, refs []string) (map[*models.Table]bool, error) {
+	selected := make(map[*models.Table]bool)
+	if len(refs) == 0 {
+		for i := range schema.Tables {
+			selected[&schema.Tables[i]] = true
+		}
+		return selected, nil
+	}
+
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		found := false
+		for i := range schema.Tables {
+			table := &schema.Tables[i]
+			if table.ID == ref || strings.EqualFold(table.Name, ref) || strings.EqualFold(QualifiedName(table.Namespace, table.Name), ref) {
+				selected[table] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown diagram table: %s", ref)
+		}
+	}
+	return selected, nil
+}
+
+func truncateLabel(label string) string {
+	runes := []rune(label)
+	if len(runes) <= diagramMaxLabel {
+		return label
+	}
+	return string(runes[:diagramMaxLabel-3]) + "..."
+}
+
+func textWidth(text string) int {
+	return len([]rune(text)) * diagramCharWidth
+}
+
+func layoutDiagram(schema *models.Schema, tables map[*models.Table]bool, theme diagramTheme, hideColumns bool) *diagram {
+	dialect, _ := ParseSQLDialect(schema.DatabaseType)
+	relations := collectRelations(schema)
+
+	var boxes []*diagramBox
+	boxesByTable := make(map[*models.Table]*diagramBox)
+	minX, minY := math.MaxInt32, math.MaxInt32
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		if !tables[table] {
+			continue
+		}
+
+		width := diagramPadding*2 + textWidth(truncateLabel(QualifiedName(table.Namespace, table.Name)))
+		height := diagramHeaderHeight
+		rows := make(map[*models.Field]int)
+		if !hideColumns {
+			for j := range table.Fields {
+				field := &table.Fields[j]
+				rows[field] = j
+				rowWidth := diagramMarkerWidth + textWidth(truncateLabel(field.Name)) + diagramPadding*3 +
+					textWidth(truncateLabel(displayFieldType(schema, dialect, table, field)))
+				if rowWidth > width {
+					width = rowWidth
+				}
+			}
+			height += len(table.Fields) * diagramRowHeight
+		}
+		if width < diagramMinTableWidth {
+			width = diagramMinTableWidth
+		}
+
+		x, y := int(math.Round(table.Position.X)), int(math.Round(table.Position.Y))
+		box := &diagramBox{Table: table, Bounds: image.Rect(x, y, x+width, y+height), Rows: rows}
+		boxes = append(boxes, box)
+		boxesByTable[table] = box
+		if x < minX {
+			minX = x
+		}
+		if y < minY {
+			minY = y
+		}
+	}
+
+	layout := &diagram{Width: diagramMargin * 2, Height: diagramMargin * 2, Background: theme.Background}
+	offset := image.Pt(diagramMargin-minX, diagramMargin-minY)
+	for _, box := range boxes {
+		box.Bounds = box.Bounds.Add(offset)
+		if box.Bounds.Max.X+diagramMargin > layout.Width {
+			layout.Width = box.Bounds.Max.X + diagramMargin
+		}
+		if box.Bounds.Max.Y+diagramMargin > layout.Height {
+			layout.Height = box.Bounds.Max.Y + diagramMargin
+		}
+	}
+
+	for _, box := range boxes {
+		layout.Shapes = append(layout.Shapes, tableShapes(schema, dialect, box, relations, theme, hideColumns)...)
+	}
+
+	for i := range relations {
+		relation := &relations[i]
+		source, target := boxesByTable[relation.From], boxesByTable[relation.To]
+		if source == nil || target == nil {
+			continue
+		}
+		shapes, right := relationShapes(relation, source, target, theme, hideColumns)
+		layout.Shapes = append(layout.Shapes, shapes...)
+		if right+diagramMargin > layout.Width {
+			layout.Width = right + diagramMargin
+		}
+	}
+
+	return layout
+}
+
+func tableShapes(schema *models.Schema, dialect SQLDialect, box *diagramBox, relations []schemaRelation, theme diagramTheme, hideColumns bool) []diagramShape {
+	bounds := box.Bounds
+	table := box.Table
+	shapes := []diagramShape{
+		{Kind: shapeRect, Rect: bounds, Fill: theme.BodyFill, Stroke: theme.Border},
+		{Kind: shapeRect, Rect: image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Min.Y+diagramHeaderHeight), Fill: theme.HeaderFill},
+		{
+			Kind:   shapeText,
+			Points: []image.Point{{bounds.Min.X + diagramPadding, bounds.Min.Y + diagramHeaderHeight/2}},
+			Text:   truncateLabel(QualifiedName(table.Namespace, table.Name)),
+			Fill:   theme.HeaderText,
+			Bold:   true,
+		},
+	}
+	if hideColumns {
+		return shapes
+	}
+
+	markers := fieldKeyMarkers(table, relations)
+	markerColors := map[string]color.RGBA{"PK": theme.PrimaryKey, "FK": theme.ForeignKey, "UQ": theme.Unique}
+	for j := range table.Fields {
+		field := &table.Fields[j]
+		top := bounds.Min.Y + diagramHeaderHeight + j*diagramRowHeight
+		center := top + diagramRowHeight/2
+
+		if j > 0 {
+			shapes = append(shapes, diagramShape{
+				Kind:   shapeLine,
+				Points: []image.Point{{bounds.Min.X + 1, top}, {bounds.Max.X - 1, top}},
+				Stroke: theme.Divider,
+			})
+		}
+		for k, marker := range markers[field.ID] {
+			shapes = append(shapes, diagramShape{
+				Kind:   shapeCircle,
+				Points: []image.Point{{bounds.Min.X + diagramPadding + k*7, center}},
+				Radius: 3,
+				Fill:   markerColors[marker],
+			})
+		}
+
+		nameColor := theme.Text
+		if len(markers[field.ID]) > 0 {
+			nameColor = markerColors[markers[field.ID][0]]
+		}
+		shapes = append(shapes,
+			diagramShape{
+				Kind:   shapeText,
+				Points: []image.Point{{bounds.Min.X + diagramMarkerWidth, center}},
+				Text:   truncateLabel(field.Name),
+				Fill:   nameColor,
+			},
+			diagramShape{
+				Kind:     shapeText,
+				Points:   []image.Point{{bounds.Max.X - diagramPadding, center}},
+				Text:     truncateLabel(displayFieldType(schema, dialect, table, field)),
+				Fill:     theme.MutedText,
+				AlignEnd: true,
+			},
+		)
+	}
+	return shapes
+}
+
+// relationShapes draws an orthogonal connector from the referencing column to
+// the referenced column with crow's foot cardinality markers at both ends. It
+// also returns the rightmost x coordinate used.
+func relationShapes(relation *schemaRelation, source, target *diagramBox, theme diagramTheme, hideColumns bool) ([]diagramShape, int) {
+	anchorY := func(box *diagramBox, field *models.Field) int {
+		if row, ok := box.Rows[field]; ok && !hideColumns {
+			return box.Bounds.Min.Y + diagramHeaderHeight + row*diagramRowHeight + diagramRowHeight/2
+		}
+		return box.Bounds.Min.Y + box.Bounds.Dy()/2
+	}
+	sy, ty := anchorY(source, relation.FromFields[0]), anchorY(target, relation.ToFields[0])
+
+	var points []image.Point
+	var sourceDir, targetDir int
+	right := 0
+	switch {
+	case source.Bounds.Max.X+24 <= target.Bounds.Min.X:
+		sx, tx := source.Bounds.Max.X, target.Bounds.Min.X
+		mid := (sx + tx) / 2
+		points = []image.Point{{sx, sy}, {mid, sy}, {mid, ty}, {tx, ty}}
+		sourceDir, targetDir = 1, -1
+	case target.Bounds.Max.X+24 <= source.Bounds.Min.X:
+		sx, tx := source.Bounds.Min.X, target.Bounds.Max.X
+		mid := (sx + tx) / 2
+		points = []image.Point{{sx, sy}, {mid, sy}, {mid, ty}, {tx, ty}}
+		sourceDir, targetDir = -1, 1
+	default:
+		sx, tx := source.Bounds.Max.X, target.Bounds.Max.X
+		out := sx
+		if tx > out {
+			out = tx
+		}
+		out += 32
+		points = []image.Point{{sx, sy}, {out, sy}, {out, ty}, {tx, ty}}
+		sourceDir, targetDir = 1, 1
+		right = out
+	}
+
+	shapes := []diagramShape{{Kind: shapeLine, Points: points, Stroke: theme.Edge}}
+
+	// Target end: exactly one (two bars) or zero-or-one (bar and circle).
+	shapes = append(shapes, barShape(points[3], targetDir, 8, theme.Edge))
+	if relation.IsOptional() {
+		shapes = append(shapes, circleShape(points[3], targetDir, 18, theme))
+	} else {
+		shapes = append(shapes, barShape(points[3], targetDir, 13, theme.Edge))
+	}
+
+	// Source end: zero-or-many (crow's foot and circle) or zero-or-one.
+	if relation.IsOneToOne() {
+		shapes = append(shapes, barShape(points[0], sourceDir, 8, theme.Edge))
+	} else {
+		tip := image.Pt(points[0].X+sourceDir*12, points[0].Y)
+		shapes = append(shapes,
+			diagramShape{Kind: shapeLine, Points: []image.Point{tip, {points[0].X, points[0].Y - 6}}, Stroke: theme.Edge},
+			diagramShape{Kind: shapeLine, Points: []image.Point{tip, {points[0].X, points[0].Y + 6}}, Stroke: theme.Edge},
+		)
+	}
+	shapes = append(shapes, circleShape(points[0], sourceDir, 18, theme))
+
+	return shapes, right
+}
+
+func barShape(end image.Point, dir, distance int, stroke color.RGBA) diagramShape {
+	x := end.X + dir*distance
+	return diagramShape{Kind: shapeLine, Points: []image.Point{{x, end.Y - 6}, {x, end.Y + 6}}, Stroke: stroke}
+}
+
+func circleShape(end image.Point, dir, distance int, theme diagramTheme) diagramShape {
+	return diagramShape{
+		Kind:   shapeCircle,
+		Points: []image.Point{{end.X + dir*distance, end.Y}},
+		Radius: 4,
+		Fill:   theme.Background,
+		Stroke: theme.Edge,
+	}
+}
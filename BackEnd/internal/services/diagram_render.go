@@ -0,0 +1,214 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+const (
+	diagramPNGScale     = 2
+	diagramPNGMaxPixels = 8192
+)
+
+func svgColor(c color.RGBA) string {
+	if c.A == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func renderDiagramSVG(layout *diagram) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		layout.Width, layout.Height, layout.Width, layout.Height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(layout.Background))
+	b.WriteString(`<g font-family="ui-monospace, SFMono-Regular, Menlo, Consolas, monospace" font-size="12">` + "\n")
+
+	for _, shape := range layout.Shapes {
+		switch shape.Kind {
+		case shapeRect:
+			r := shape.Rect
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="%s"/>`+"\n",
+				r.Min.X, r.Min.Y, r.Dx(), r.Dy(), svgColor(shape.Fill), svgColor(shape.Stroke))
+		case shapeLine:
+			points := make([]string, len(shape.Points))
+			for i, p := range shape.Points {
+				points[i] = fmt.Sprintf("%d,%d", p.X, p.Y)
+			}
+			fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`+"\n",
+				strings.Join(points, " "), svgColor(shape.Stroke))
+		case shapeCircle:
+			p := shape.Points[0]
+			fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="%d" fill="%s" stroke="%s" stroke-width="1.5"/>`+"\n",
+				p.X, p.Y, shape.Radius, svgColor(shape.Fill), svgColor(shape.Stroke))
+		case shapeText:
+			p := shape.Points[0]
+			attrs := ""
+			if shape.Bold {
+				attrs += ` font-weight="bold"`
+			}
+			if shape.AlignEnd {
+				attrs += ` text-anchor="end"`
+			}
+			fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="central" fill="%s"%s>%s</text>`+"\n",
+				p.X, p.Y, svgColor(shape.Fill), attrs, html.EscapeString(shape.Text))
+		}
+	}
+
+	b.WriteString("</g>\n</svg>\n")
+	return []byte(b.String())
+}
+
+// pngCanvas draws shapes in layout units onto an RGBA image scaled by
+// diagramPNGScale.
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func renderDiagramPNG(layout *diagram) ([]byte, error) {
+	width, height := layout.Width*diagramPNGScale, layout.Height*diagramPNGScale
+	if width > diagramPNGMaxPixels || height > diagramPNGMaxPixels {
+		return nil, fmt.Errorf("diagram is too large to render as PNG: %dx%d", layout.Width, layout.Height)
+	}
+
+	canvas := &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+	canvas.fillRect(image.Rect(0, 0, layout.Width, layout.Height), layout.Background)
+
+	for _, shape := range layout.Shapes {
+		switch shape.Kind {
+		case shapeRect:
+			if shape.Fill.A != 0 {
+				canvas.fillRect(shape.Rect, shape.Fill)
+			}
+			if shape.Stroke.A != 0 {
+				r := shape.Rect
+				canvas.line(image.Pt(r.Min.X, r.Min.Y), image.Pt(r.Max.X, r.Min.Y), shape.Stroke)
+				canvas.line(image.Pt(r.Max.X, r.Min.Y), image.Pt(r.Max.X, r.Max.Y), shape.Stroke)
+				canvas.line(image.Pt(r.Max.X, r.Max.Y), image.Pt(r.Min.X, r.Max.Y), shape.Stroke)
+				canvas.line(image.Pt(r.Min.X, r.Max.Y), image.Pt(r.Min.X, r.Min.Y), shape.Stroke)
+			}
+		case shapeLine:
+			for i := 1; i < len(shape.Points); i++ {
+				canvas.line(shape.Points[i-1], shape.Points[i], shape.Stroke)
+			}
+		case shapeCircle:
+			canvas.circle(shape.Points[0], shape.Radius, shape.Fill, shape.Stroke)
+		case shapeText:
+			x := shape.Points[0].X
+			if shape.AlignEnd {
+				x -= textWidth(shape.Text)
+			}
+			canvas.text(x, shape.Points[0].Y, shape.Text, shape.Fill, shape.Bold)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas.img); err != nil {
+		return nil, fmt.Errorf("failed to encode diagram: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *pngCanvas) fillRect(r image.Rectangle, fill color.RGBA) {
+	scaled := image.Rect(r.Min.X*diagramPNGScale, r.Min.Y*diagramPNGScale, r.Max.X*diagramPNGScale, r.Max.Y*diagramPNGScale)
+	scaled = scaled.Intersect(c.img.Bounds())
+	for y := scaled.Min.Y; y < scaled.Max.Y; y++ {
+		for x := scaled.Min.X; x < scaled.Max.X; x++ {
+			c.img.SetRGBA(x, y, fill)
+		}
+	}
+}
+
+// line draws a Bresenham line with a square brush one scale unit wide.
+func (c *pngCanvas) line(from, to image.Point, stroke color.RGBA) {
+	x0, y0 := from.X*diagramPNGScale, from.Y*diagramPNGScale
+	x1, y1 := to.X*diagramPNGScale, to.Y*diagramPNGScale
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		for by := 0; by < diagramPNGScale; by++ {
+			for bx := 0; bx < diagramPNGScale; bx++ {
+				c.img.SetRGBA(x0+bx-diagramPNGScale/2, y0+by-diagramPNGScale/2, stroke)
+			}
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			x0 += sx
+		} else {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func (c *pngCanvas) circle(center image.Point, radius int, fill, stroke color.RGBA) {
+	cx, cy, r := center.X*diagramPNGScale, center.Y*diagramPNGScale, radius*diagramPNGScale
+	inner := (r - diagramPNGScale) * (r - diagramPNGScale)
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			d := x*x + y*y
+			switch {
+			case d > r*r:
+			case d >= inner && stroke.A != 0:
+				c.img.SetRGBA(cx+x, cy+y, stroke)
+			case fill.A != 0:
+				c.img.SetRGBA(cx+x, cy+y, fill)
+			}
+		}
+	}
+}
+
+// text draws a string with the built-in 5x7 font, vertically centred on y.
+// Each character advances diagramCharWidth layout units.
+func (c *pngCanvas) text(x, y int, text string, fill color.RGBA, bold bool) {
+	dot := diagramPNGScale
+	left := x * diagramPNGScale
+	top := y*diagramPNGScale - 7*dot/2
+
+	for _, r := range text {
+		glyph, ok := diagramFont[r]
+		if !ok {
+			glyph = diagramFont['?']
+		}
+		for col, bits := range glyph {
+			for row := 0; row < 7; row++ {
+				if bits&(1<<row) == 0 {
+					continue
+				}
+				for by := 0; by < dot; by++ {
+					for bx := 0; bx < dot; bx++ {
+						px, py := left+col*dot+bx, top+row*dot+by
+						c.img.SetRGBA(px, py, fill)
+						if bold {
+							c.img.SetRGBA(px+1, py, fill)
+						}
+					}
+				}
+			}
+		}
+		left += diagramCharWidth * diagramPNGScale
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+var (
+	diagramIdentifierPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	mermaidTypePattern       = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]+`)
+)
+
+// diagramIdentifier turns a qualified table name into an identifier every
+// diagram language accepts unquoted.
+func diagramIdentifier(name string) string {
+	id := strings.Trim(diagramIdentifierPattern.ReplaceAllString(name, "_"), "_")
+	if id == "" || (id[0] >= '0' && id[0] <= '9') {
+		id = "t_" + id
+	}
+	return id
+}
+
+// crowsFoot returns the markers for the referenced (target) and referencing
+// (source) ends of a relation in the notation shared by Mermaid and PlantUML.
+func crowsFoot(relation *schemaRelation) (string, string) {
+	target, source := "||", "o{"
+	if relation.IsOptional() {
+		target = "|o"
+	}
+	if relation.IsOneToOne() {
+		source = "o|"
+	}
+	return target, source
+}
+
+func GenerateMermaid(schema *models.Schema) string {
+	dialect, _ := ParseSQLDialect(schema.DatabaseType)
+	relations := collectRelations(schema)
+
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		markers := fieldKeyMarkers(table, relations)
+
+		fmt.Fprintf(&b, "    %s {\n", diagramIdentifier(QualifiedName(table.Namespace, table.Name)))
+		for j := range table.Fields {
+			field := &table.Fields[j]
+			fieldType := strings.Trim(mermaidTypePattern.ReplaceAllString(displayFieldType(schema, dialect, table, field), "_"), "_")
+			line := fmt.Sprintf("        %s %s", fieldType, diagramIdentifier(field.Name))
+			if keys := markers[field.ID]; len(keys) > 0 {
+				line += " " + strings.ReplaceAll(strings.Join(keys, ", "), "UQ", "UK")
+			}
+			if field.Comment != "" {
+				line += fmt.Sprintf(" %q", strings.ReplaceAll(field.Comment, `"`, "'"))
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for i := range relations {
+		relation := &relations[i]
+		target, source := crowsFoot(relation)
+		fmt.Fprintf(&b, "    %s %s--%s %s : %q\n",
+			diagramIdentifier(QualifiedName(relation.To.Namespace, relation.To.Name)), target, source,
+			diagramIdentifier(QualifiedName(relation.From.Namespace, relation.From.Name)), relation.Name)
+	}
+
+	return b.String()
+}
+
+func GeneratePlantUML(schema *models.Schema) string {
+	dialect, _ := ParseSQLDialect(schema.DatabaseType)
+	relations := collectRelations(schema)
+
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	if schema.Name != "" {
+		fmt.Fprintf(&b, "title %s\n", schema.Name)
+	}
+	b.WriteString("hide circle\nskinparam linetype ortho\n\n")
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		markers := fieldKeyMarkers(table, relations)
+		name := QualifiedName(table.Namespace, table.Name)
+
+		var keyLines, otherLines []string
+		for j := range table.Fields {
+			field := &table.Fields[j]
+			keys := markers[field.ID]
+			line := fmt.Sprintf("%s : %s", field.Name, displayFieldType(schema, dialect, table, field))
+			if len(keys) > 0 {
+				line += " <<" + strings.Join(keys, ", ") + ">>"
+			}
+			if field.IsNotNull || field.IsPrimaryKey {
+				line = "* " + line
+			}
+			if len(keys) > 0 && keys[0] == "PK" {
+				keyLines = append(keyLines, "  "+line)
+			} else {
+				otherLines = append(otherLines, "  "+line)
+			}
+		}
+
+		fmt.Fprintf(&b, "entity %q as %s {\n", name, diagramIdentifier(name))
+		for _, line := range keyLines {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("  --\n")
+		for _, line := range otherLines {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	for i := range relations {
+		relation := &relations[i]
+		target, source := crowsFoot(relation)
+		fmt.Fprintf(&b, "%s %s--%s %s : %s\n",
+			diagramIdentifier(QualifiedName(relation.To.Namespace, relation.To.Name)), target, source,
+			diagramIdentifier(QualifiedName(relation.From.Namespace, relation.From.Name)), relation.Name)
+	}
+
+	b.WriteString("@enduml\n")
+	return b.String()
+}
+
+func GenerateDOT(schema *models.Schema) string {
+	dialect, _ := ParseSQLDialect(schema.DatabaseType)
+	relations := collectRelations(schema)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", schema.Name)
+	b.WriteString("  graph [rankdir=LR, splines=true, nodesep=0.6];\n")
+	b.WriteString("  node [shape=plain, fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=9, dir=both];\n\n")
+
+	ports := make(map[*models.Field]string)
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		markers := fieldKeyMarkers(table, relations)
+
+		var rows []string
+		rows = append(rows, fmt.Sprintf(`<tr><td bgcolor="#e4e7eb" colspan="3"><b>%s</b></td></tr>`,
+			html.EscapeString(QualifiedName(table.Namespace, table.Name))))
+		for j := range table.Fields {
+			field := &table.Fields[j]
+			port := fmt.Sprintf("f%d", j)
+			ports[field] = port
+			rows = append(rows, fmt.Sprintf(`<tr><td port="%s" align="left">%s</td><td align="left">%s</td><td align="left">%s</td></tr>`,
+				port, html.EscapeString(field.Name), html.EscapeString(displayFieldType(schema, dialect, table, field)),
+				strings.Join(markers[field.ID], " ")))
+		}
+
+		fmt.Fprintf(&b, "  %s [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">%s</table>>];\n",
+			diagramIdentifier(QualifiedName(table.Namespace, table.Name)), strings.Join(rows, ""))
+	}
+
+	if len(relations) > 0 {
+		b.WriteString("\n")
+	}
+	for i := range relations {
+		relation := &relations[i]
+		head, tail := "teetee", "crowodot"
+		if relation.IsOptional() {
+			head = "teeodot"
+		}
+		if relation.IsOneToOne() {
+			tail = "teeodot"
+		}
+		fmt.Fprintf(&b, "  %s:%s -> %s:%s [arrowhead=%s, arrowtail=%s, label=%q];\n",
+			diagramIdentifier(QualifiedName(relation.From.Namespace, relation.From.Name)), ports[relation.FromFields[0]],
+			diagramIdentifier(QualifiedName(relation.To.Namespace, relation.To.Name)), ports[relation.ToFields[0]],
+			head, tail, relation.Name)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
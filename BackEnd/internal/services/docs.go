@@ -0,0 +1,355 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+type dataDictionary struct {
+	Name        string
+	Description string
+	Tables      []dictionaryTable
+	Enums       []dictionaryEnum
+}
+
+type dictionaryTable struct {
+	Anchor      string
+	Name        string
+	Description string
+	Columns     []dictionaryColumn
+	PrimaryKey  string
+	UniqueKeys  []string
+	Indexes     []dictionaryIndex
+	Outgoing    []string
+	Incoming    []string
+}
+
+type dictionaryColumn struct {
+	Name       string
+	Type       string
+	Nullable   string
+	Default    string
+	Keys       string
+	References string
+	Comment    string
+}
+
+type dictionaryIndex struct {
+	Name    string
+	Columns string
+	Unique  bool
+	Method  string
+	Where   string
+}
+
+type dictionaryEnum struct {
+	Name   string
+	Values string
+}
+
+func GenerateDataDictionary(schema *models.Schema, format string) (*ExportResult, error) {
+	dictionary := buildDataDictionary(schema)
+
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return &ExportResult{
+			Content:     []byte(renderDictionaryMarkdown(dictionary)),
+			ContentType: "text/markdown; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "md"),
+		}, nil
+	case "html":
+		content, err := renderDictionaryHTML(dictionary)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportResult{
+			Content:     content,
+			ContentType: "text/html; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "html"),
+		}, nil
+	case "csv":
+		content, err := renderDictionaryCSV(dictionary)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportResult{
+			Content:     content,
+			ContentType: "text/csv; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "csv"),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported docs format: %s", format)
+}
+
+func buildDataDictionary(schema *models.Schema) *dataDictionary {
+	dialect, _ := ParseSQLDialect(schema.DatabaseType)
+	relations := collectRelations(schema)
+
+	dictionary := &dataDictionary{
+		Name:        schema.Name,
+		Description: schema.Description,
+	}
+
+	for _, enum := range schema.Enums {
+		dictionary.Enums = append(dictionary.Enums, dictionaryEnum{
+			Name:   QualifiedName(enum.Namespace, enum.Name),
+			Values: strings.Join(enum.Values, ", "),
+		})
+	}
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		name := QualifiedName(table.Namespace, table.Name)
+		entry := dictionaryTable{
+			Anchor:      dictionaryAnchor(name),
+			Name:        name,
+			Description: table.Description,
+			PrimaryKey:  strings.Join(fieldNames(primaryKeyFields(table)), ", "),
+		}
+
+		keys := fieldKeyMarkers(table, relations)
+		references := make(map[string][]string)
+		for j := range table.Constraints {
+			if constraintKind(&table.Constraints[j]) == ConstraintUnique {
+				if fields := constraintFields(table, &table.Constraints[j]); len(fields) > 0 {
+					entry.UniqueKeys = append(entry.UniqueKeys, strings.Join(fieldNames(fields), ", "))
+				}
+			}
+		}
+
+		for _, relation := range relations {
+			if relation.From == table {
+				target := QualifiedName(relation.To.Namespace, relation.To.Name)
+				entry.Outgoing = append(entry.Outgoing, fmt.Sprintf("(%s) → %s (%s)",
+					strings.Join(fieldNames(relation.FromFields), ", "), target, strings.Join(fieldNames(relation.ToFields), ", ")))
+				for k, field := range relation.FromFields {
+					references[field.ID] = append(references[field.ID], target+"."+relation.ToFields[k].Name)
+				}
+			}
+			if relation.To == table {
+				source := QualifiedName(relation.From.Namespace, relation.From.Name)
+				entry.Incoming = append(entry.Incoming, fmt.Sprintf("%s (%s) → (%s)",
+					source, strings.Join(fieldNames(relation.FromFields), ", "), strings.Join(fieldNames(relation.ToFields), ", ")))
+			}
+		}
+
+		for j := range table.Fields {
+			field := &table.Fields[j]
+			nullable := "YES"
+			if field.IsNotNull || field.IsPrimaryKey {
+				nullable = "NO"
+			}
+			entry.Columns = append(entry.Columns, dictionaryColumn{
+				Name:       field.Name,
+				Type:       displayFieldType(schema, dialect, table, field),
+				Nullable:   nullable,
+				Default:    field.DefaultValue,
+				Keys:       strings.Join(keys[field.ID], ", "),
+				References: strings.Join(references[field.ID], ", "),
+				Comment:    field.Comment,
+			})
+		}
+
+		for j := range table.Indexes {
+			index := &table.Indexes[j]
+			var columns []string
+			for _, column := range indexColumns(index) {
+				if field := findField(table, column.FieldID); field != nil {
+					columns = append(columns, strings.TrimSpace(field.Name+" "+strings.ToUpper(column.Order)))
+				} else if column.Expression != "" {
+					columns = append(columns, column.Expression)
+				}
+			}
+			entry.Indexes = append(entry.Indexes, dictionaryIndex{
+				Name:    indexDisplayName(table, index),
+				Columns: strings.Join(columns, ", "),
+				Unique:  index.IsUnique,
+				Method:  indexMethod(index),
+				Where:   index.Where,
+			})
+		}
+
+		dictionary.Tables = append(dictionary.Tables, entry)
+	}
+
+	return dictionary
+}
+
+func displayFieldType(schema *models.Schema, dialect SQLDialect, table *models.Table, field *models.Field) string {
+	if enum := resolveEnum(schema, table.Namespace, field.Type); enum != nil {
+		return QualifiedName(enum.Namespace, enum.Name)
+	}
+	return renderColumnType(dialect, field)
+}
+
+func dictionaryAnchor(name string) string {
+	return "table-" + strings.Trim(fileNameUnsafePattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	value = strings.ReplaceAll(value, "\r\n", "<br>")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+func renderDictionaryMarkdown(dictionary *dataDictionary) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", dictionary.Name)
+	if dictionary.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", dictionary.Description)
+	}
+
+	b.WriteString("## Tables\n\n")
+	for _, table := range dictionary.Tables {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", table.Name, table.Anchor)
+	}
+	b.WriteString("\n")
+
+	for _, table := range dictionary.Tables {
+		fmt.Fprintf(&b, "<a id=\"%s\"></a>\n\n## %s\n\n", table.Anchor, table.Name)
+		if table.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", table.Description)
+		}
+
+		b.WriteString("| Column | Type | Nullable | Default | Keys | References | Comment |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, column := range table.Columns {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				markdownCell(column.Name), markdownCell(column.Type), column.Nullable, markdownCell(column.Default),
+				column.Keys, markdownCell(column.References), markdownCell(column.Comment))
+		}
+		b.WriteString("\n")
+
+		if table.PrimaryKey != "" {
+			fmt.Fprintf(&b, "**Primary key:** %s\n\n", table.PrimaryKey)
+		}
+		for _, key := range table.UniqueKeys {
+			fmt.Fprintf(&b, "**Unique:** %s\n\n", key)
+		}
+
+		if len(table.Indexes) > 0 {
+			b.WriteString("**Indexes**\n\n| Name | Columns | Unique | Method | Where |\n| --- | --- | --- | --- | --- |\n")
+			for _, index := range table.Indexes {
+				unique := "NO"
+				if index.Unique {
+					unique = "YES"
+				}
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+					markdownCell(index.Name), markdownCell(index.Columns), unique, index.Method, markdownCell(index.Where))
+			}
+			b.WriteString("\n")
+		}
+
+		if len(table.Outgoing) > 0 {
+			b.WriteString("**References**\n\n")
+			for _, reference := range table.Outgoing {
+				fmt.Fprintf(&b, "- %s\n", reference)
+			}
+			b.WriteString("\n")
+		}
+		if len(table.Incoming) > 0 {
+			b.WriteString("**Referenced by**\n\n")
+			for _, reference := range table.Incoming {
+				fmt.Fprintf(&b, "- %s\n", reference)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(dictionary.Enums) > 0 {
+		b.WriteString("## Enums\n\n| Name | Values |\n| --- | --- |\n")
+		for _, enum := range dictionary.Enums {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(enum.Name), markdownCell(enum.Values))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+var dictionaryHTMLTemplate = template.Must(template.New("dictionary").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} – Data Dictionary</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Arial, sans-serif; color: #1f2933; margin: 2rem auto; max-width: 1100px; padding: 0 1rem; }
+h1 { border-bottom: 2px solid #e4e7eb; padding-bottom: .5rem; }
+h2 { margin-top: 2.5rem; }
+table { border-collapse: collapse; width: 100%; margin: 1rem 0; font-size: .9rem; }
+th, td { border: 1px solid #d9e2ec; padding: .4rem .6rem; text-align: left; vertical-align: top; }
+th { background: #f0f4f8; }
+code { background: #f0f4f8; padding: 0 .25rem; border-radius: 3px; }
+.muted { color: #7b8794; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<h2>Tables</h2>
+<ul>
+{{range .Tables}}<li><a href="#{{.Anchor}}">{{.Name}}</a></li>
+{{end}}</ul>
+{{range .Tables}}
+<h2 id="{{.Anchor}}">{{.Name}}</h2>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<table>
+<tr><th>Column</th><th>Type</th><th>Nullable</th><th>Default</th><th>Keys</th><th>References</th><th>Comment</th></tr>
+{{range .Columns}}<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{.Nullable}}</td><td>{{.Default}}</td><td>{{.Keys}}</td><td>{{.References}}</td><td>{{.Comment}}</td></tr>
+{{end}}</table>
+{{if .PrimaryKey}}<p><strong>Primary key:</strong> {{.PrimaryKey}}</p>{{end}}
+{{range .UniqueKeys}}<p><strong>Unique:</strong> {{.}}</p>{{end}}
+{{if .Indexes}}<h3>Indexes</h3>
+<table>
+<tr><th>Name</th><th>Columns</th><th>Unique</th><th>Method</th><th>Where</th></tr>
+{{range .Indexes}}<tr><td>{{.Name}}</td><td>{{.Columns}}</td><td>{{if .Unique}}YES{{else}}NO{{end}}</td><td>{{.Method}}</td><td>{{.Where}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Outgoing}}<h3>References</h3>
+<ul>{{range .Outgoing}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Incoming}}<h3>Referenced by</h3>
+<ul>{{range .Incoming}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{end}}
+{{if .Enums}}<h2>Enums</h2>
+<table>
+<tr><th>Name</th><th>Values</th></tr>
+{{range .Enums}}<tr><td>{{.Name}}</td><td>{{.Values}}</td></tr>
+{{end}}</table>{{end}}
+<p class="muted">Generated by Schema Builder</p>
+</body>
+</html>
+`))
+
+func renderDictionaryHTML(dictionary *dataDictionary) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := dictionaryHTMLTemplate.Execute(&buf, dictionary); err != nil {
+		return nil, fmt.Errorf("failed to render data dictionary: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func renderDictionaryCSV(dictionary *dataDictionary) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	rows := [][]string{{"table", "table_description", "column", "type", "nullable", "default", "keys", "references", "comment"}}
+	for _, table := range dictionary.Tables {
+		for _, column := range table.Columns {
+			rows = append(rows, []string{
+				table.Name, table.Description, column.Name, column.Type, column.Nullable,
+				column.Default, column.Keys, column.References, column.Comment,
+			})
+		}
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write data dictionary: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	schemaDocumentFormat  = "schema-builder"
+	schemaDocumentVersion = 1
+)
+
+// SchemaDocument is the lossless JSON interchange format used by the json
+// export and import.
+type SchemaDocument struct {
+	Format        string             `json:"format"`
+	FormatVersion int                `json:"format_version"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description,omitempty"`
+	DatabaseType  string             `json:"database_type,omitempty"`
+	Namespaces    []models.Namespace `json:"namespaces,omitempty"`
+	Tables        []models.Table     `json:"tables"`
+	Enums         []models.Enum      `json:"enums,omitempty"`
+	Views         []models.View      `json:"views,omitempty"`
+}
+
+func RenderSchemaDocument(schema *models.Schema) ([]byte, error) {
+	document := SchemaDocument{
+		Format:        schemaDocumentFormat,
+		FormatVersion: schemaDocumentVersion,
+		Name:          schema.Name,
+		Description:   schema.Description,
+		DatabaseType:  schema.DatabaseType,
+		Namespaces:    schema.Namespaces,
+		Tables:        schema.Tables,
+		Enums:         schema.Enums,
+		Views:         schema.Views,
+	}
+	if document.Tables == nil {
+		document.Tables = []models.Table{}
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema document: %v", err)
+	}
+	return append(content, '\n'), nil
+}
+
+func ParseSchemaDocument(content string) (*models.CreateSchemaRequest, error) {
+	var document SchemaDocument
+	if err := json.Unmarshal([]byte(content), &document); err != nil {
+		return nil, fmt.Errorf("invalid schema document: %v", err)
+	}
+
+	if document.Format != "" && document.Format != schemaDocumentFormat {
+		return nil, fmt.Errorf("unknown document format %q", document.Format)
+	}
+	if document.FormatVersion > schemaDocumentVersion {
+		return nil, fmt.Errorf("document format version %d is newer than supported version %d", document.FormatVersion, schemaDocumentVersion)
+	}
+
+	return &models.CreateSchemaRequest{
+		Name:         document.Name,
+		Description:  document.Description,
+		DatabaseType: document.DatabaseType,
+		Namespaces:   document.Namespaces,
+		Tables:       document.Tables,
+		Enums:        document.Enums,
+		Views:        document.Views,
+	}, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"html"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/gomail.v2"
@@ -178,6 +179,40 @@ func (s *EmailService) SendAccountLinkingNotification(email, firstName string) e
 	return s.sendEmail(email, subject, body)
 }
 
+func (s *EmailService) SendMentionEmail(email, firstName, author, schemaName, anchor, comment string) error {
+	subject := fmt.Sprintf("%s mentioned you on %s - Schema Builder", author, schemaName)
+	body := fmt.Sprintf(`
+<html>
+<body>
+    <div style="max-width: 600px; margin: 0 auto; padding: 20px; font-family: Arial, sans-serif;">
+        <div style="text-align: center; margin-bottom: 30px;">
+            <h1 style="color: #333; margin-bottom: 10px;">Schema Builder</h1>
+            <h2 style="color: #007bff; font-weight: normal;">You Were Mentioned</h2>
+        </div>
+        
+        <div style="background-color: #f8f9fa; padding: 30px; border-radius: 8px;">
+            <p style="font-size: 18px; color: #333; margin-bottom: 20px;">
+                Hi %s,
+            </p>
+            
+            <p style="font-size: 16px; color: #333; margin-bottom: 20px;">
+                %s mentioned you in a comment on %s of <strong>%s</strong>:
+            </p>
+            
+            <div style="background-color: #ffffff; border-left: 4px solid #007bff; padding: 15px 20px; margin: 20px 0; white-space: pre-wrap;">%s</div>
+            
+            <p style="font-size: 14px; color: #666; margin-top: 20px;">
+                Open the schema in Schema Builder to reply.
+            </p>
+        </div>
+    </div>
+</body>
+</html>
+    `, html.EscapeString(firstName), html.EscapeString(author), html.EscapeString(anchor), html.EscapeString(schemaName), html.EscapeString(comment))
+
+	return s.sendEmail(email, subject, body)
+}
+
 func (s *EmailService) sendEmail(to, subject, body string) error {
 	m := gomail.NewMessage()
 	m.SetHeader("From", fmt.Sprintf("%s <%s>", s.config.FromName, s.config.User))
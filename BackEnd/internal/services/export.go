@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/logger"
+)
+
+var fileNameUnsafePattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+type ExportService struct {
+	schemaService *SchemaService
+	log           *logrus.Logger
+}
+
+type ExportOptions struct {
+	Format    string
+	Namespace string
+	// Code generation settings, used by the go format.
+	Package  string
+	Tags     []string
+	Nullable string
+}
+
+type ExportResult struct {
+	Content     []byte
+	ContentType string
+	FileName    string
+}
+
+func NewExportService(schemaService *SchemaService) *ExportService {
+	return &ExportService{
+		schemaService: schemaService,
+		log:           logger.GetLogger(),
+	}
+}
+
+func (s *ExportService) ExportSchema(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, opts ExportOptions) (*ExportResult, error) {
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Namespace != "" {
+		schema = FilterSchemaByNamespace(schema, opts.Namespace)
+	}
+
+	result, err := s.Render(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infof("Schema %s exported as %s", id.Hex(), opts.Format)
+	return result, nil
+}
+
+func (s *ExportService) GenerateDocs(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, opts ExportOptions) (*ExportResult, error) {
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Namespace != "" {
+		schema = FilterSchemaByNamespace(schema, opts.Namespace)
+	}
+
+	result, err := GenerateDataDictionary(schema, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infof("Data dictionary for schema %s generated as %s", id.Hex(), opts.Format)
+	return result, nil
+}
+
+func (s *ExportService) Render(schema *models.Schema, opts ExportOptions) (*ExportResult, error) {
+	if dialect, ok := ParseSQLDialect(opts.Format); ok {
+		return &ExportResult{
+			Content:     []byte(GenerateDDL(schema, dialect)),
+			ContentType: "application/sql; charset=utf-8",
+			FileName:    exportFileName(schema.Name, string(dialect)+".sql"),
+		}, nil
+	}
+
+	switch strings.ToLower(opts.Format) {
+	case "json":
+		content, err := RenderSchemaDocument(schema)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportResult{
+			Content:     content,
+			ContentType: "application/json; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "schema.json"),
+		}, nil
+	case "dbml":
+		return &ExportResult{
+			Content:     []byte(GenerateDBML(schema)),
+			ContentType: "text/plain; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "dbml"),
+		}, nil
+	case "go":
+		content, err := GenerateGoPackage(schema, GoCodeOptions{Package: opts.Package, Tags: opts.Tags, Nullable: opts.Nullable})
+		if err != nil {
+			return nil, err
+		}
+		return &ExportResult{
+			Content:     content,
+			ContentType: "application/zip",
+			FileName:    exportFileName(schema.Name, "go.zip"),
+		}, nil
+	case "typescript", "ts":
+		return &ExportResult{
+			Content:     []byte(GenerateTypeScript(schema, false)),
+			ContentType: "application/typescript; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "types.ts"),
+		}, nil
+	case "zod":
+		return &ExportResult{
+			Content:     []byte(GenerateTypeScript(schema, true)),
+			ContentType: "application/typescript; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "schemas.ts"),
+		}, nil
+	case "graphql", "gql":
+		return &ExportResult{
+			Content:     []byte(GenerateGraphQL(schema)),
+			ContentType: "text/plain; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "graphql"),
+		}, nil
+	case "openapi", "openapi-yaml", "openapi-json":
+		format := "yaml"
+		if strings.HasSuffix(strings.ToLower(opts.Format), "-json") {
+			format = "json"
+		}
+		content, err := GenerateOpenAPI(schema, format)
+		if err != nil {
+			return nil, err
+		}
+		contentType := "application/yaml; charset=utf-8"
+		if format == "json" {
+			contentType = "application/json; charset=utf-8"
+		}
+		return &ExportResult{
+			Content:     content,
+			ContentType: contentType,
+			FileName:    exportFileName(schema.Name, "openapi."+format),
+		}, nil
+	case "jsonschema", "json-schema":
+		content, err := GenerateJSONSchemas(schema)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportResult{
+			Content:     content,
+			ContentType: "application/zip",
+			FileName:    exportFileName(schema.Name, "jsonschema.zip"),
+		}, nil
+	case "mongodb", "mongo":
+		content, err := GenerateMongoValidators(schema)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportResult{
+			Content:     content,
+			ContentType: "application/zip",
+			FileName:    exportFileName(schema.Name, "mongodb.zip"),
+		}, nil
+	case "prisma":
+		return &ExportResult{
+			Content:     []byte(GeneratePrisma(schema)),
+			ContentType: "text/plain; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "prisma"),
+		}, nil
+	case "mermaid":
+		return &ExportResult{
+			Content:     []byte(GenerateMermaid(schema)),
+			ContentType: "text/plain; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "mmd"),
+		}, nil
+	case "plantuml":
+		return &ExportResult{
+			Content:     []byte(GeneratePlantUML(schema)),
+			ContentType: "text/plain; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "puml"),
+		}, nil
+	case "dot", "graphviz":
+		return &ExportResult{
+			Content:     []byte(GenerateDOT(schema)),
+			ContentType: "text/vnd.graphviz; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "dot"),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported export format: %s", opts.Format)
+}
+
+func exportFileName(schemaName, extension string) string {
+	base := strings.Trim(fileNameUnsafePattern.ReplaceAllString(strings.ToLower(schemaName), "_"), "_")
+	if base == "" {
+		base = "schema"
+	}
+	return base + "." + extension
+}
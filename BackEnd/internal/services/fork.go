@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+)
+
+// UpstreamPreview is the result of merging the changes made upstream since
+// the last pull into a fork. In conflicts, "target" is the fork's side and
+// "branch" the upstream's.
+type UpstreamPreview struct {
+	UpstreamID      primitive.ObjectID `json:"upstream_id"`
+	SyncedVersion   int                `json:"synced_version"`
+	UpstreamVersion int                `json:"upstream_version"`
+	ForkVersion     int                `json:"fork_version"`
+	UpToDate        bool               `json:"up_to_date"`
+	Namespaces      []models.Namespace `json:"namespaces"`
+	Tables          []models.Table     `json:"tables"`
+	Enums           []models.Enum      `json:"enums"`
+	Views           []models.View      `json:"views"`
+	Conflicts       []MergeConflict    `json:"conflicts"`
+	Unresolved      int                `json:"unresolved"`
+	Issues          []string           `json:"issues,omitempty"`
+}
+
+// StarStatus is whether the user has starred a schema and its star count.
+type StarStatus struct {
+	Starred   bool `json:"starred"`
+	StarCount int  `json:"star_count"`
+}
+
+// ForkSchema copies a public schema into a private schema of the user that
+// remembers its upstream.
+func (s *SchemaService) ForkSchema(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.ForkSchemaRequest) (*models.Schema, error) {
+	source, err := s.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !source.IsPublic {
+		return nil, fmt.Errorf("access denied: only public schemas can be forked")
+	}
+
+	// Pulls merge against upstream versions, so the forked one has to be
+	// on record.
+	if _, err := s.versionRepo.Get(ctx, id, source.Version); err != nil {
+		s.recordVersion(ctx, source, source.UserID)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = source.Name
+	}
+
+	fork, err := s.createSchema(ctx, userID, &models.Schema{
+		UserID:       userID,
+		Name:         name,
+		Description:  source.Description,
+		DatabaseType: source.DatabaseType,
+		Namespaces:   source.Namespaces,
+		Tables:       source.Tables,
+		Enums:        source.Enums,
+		Views:        source.Views,
+		IsPublic:     false,
+		ForkedFrom: &models.ForkSource{
+			SchemaID:      source.ID,
+			UserID:        source.UserID,
+			Name:          source.Name,
+			Version:       source.Version,
+			SyncedVersion: source.Version,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.schemaRepo.AdjustCount(ctx, id, "fork_count", 1); err != nil {
+		s.log.Errorf("Failed to update fork count of schema %s: %v", id.Hex(), err)
+	}
+
+	s.log.Infof("Schema %s forked as %s at version %d", id.Hex(), fork.ID.Hex(), source.Version)
+	return fork, nil
+}
+
+func (s *SchemaService) ListForks(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) ([]*models.Schema, error) {
+	if _, err := s.GetSchemaByID(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	forks, err := s.schemaRepo.ListForks(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list forks: %v", err)
+	}
+
+	return forks, nil
+}
+
+func (s *SchemaService) PreviewUpstream(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*UpstreamPreview, error) {
+	_, preview, err := s.prepareUpstreamMerge(ctx, id, userID, nil)
+	return preview, err
+}
+
+// PullUpstream merges the upstream changes made since the last pull into
+// the fork. Conflicts have to be resolved in the request.
+func (s *SchemaService) PullUpstream(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.PullUpstreamRequest) (*models.Schema, error) {
+	resolutions := make(map[string]string, len(req.Resolutions))
+	for _, resolution := range req.Resolutions {
+		resolutions[resolution.ConflictID] = resolution.Choice
+	}
+
+	fork, preview, err := s.prepareUpstreamMerge(ctx, id, userID, resolutions)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.UpstreamVersion != 0 && req.UpstreamVersion != preview.UpstreamVersion {
+		return nil, fmt.Errorf("schema version mismatch: pull was previewed for upstream version %d but the upstream is at version %d", req.UpstreamVersion, preview.UpstreamVersion)
+	}
+	if preview.UpToDate {
+		return fork, nil
+	}
+	known := make(map[string]bool, len(preview.Conflicts))
+	for _, conflict := range preview.Conflicts {
+		known[conflict.ID] = true
+	}
+	for _, resolution := range req.Resolutions {
+		if !known[resolution.ConflictID] {
+			return nil, fmt.Errorf("unknown merge conflict: %s", resolution.ConflictID)
+		}
+	}
+	if preview.Unresolved > 0 {
+		return nil, fmt.Errorf("unresolved merge conflicts: %d of %d conflicts have no resolution", preview.Unresolved, len(preview.Conflicts))
+	}
+
+	updated, err := s.UpdateSchema(ctx, id, userID, &models.UpdateSchemaRequest{
+		Namespaces: preview.Namespaces,
+		Tables:     preview.Tables,
+		Enums:      preview.Enums,
+		Views:      preview.Views,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.schemaRepo.SetForkSync(ctx, id, preview.UpstreamVersion); err != nil {
+		s.log.Errorf("Failed to record upstream sync of fork %s: %v", id.Hex(), err)
+	} else {
+		updated.ForkedFrom.SyncedVersion = preview.UpstreamVersion
+	}
+
+	s.log.Infof("Fork %s pulled upstream %s at version %d", id.Hex(), preview.UpstreamID.Hex(), preview.UpstreamVersion)
+	return updated, nil
+}
+
+// prepareUpstreamMerge merges the upstream into the fork, with the last
+// synced upstream version as the base. Only the owner of a fork can pull.
+func (s *SchemaService) prepareUpstreamMerge(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, resolutions map[string]string) (*models.Schema, *UpstreamPreview, error) {
+	fork, err := s.schemaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("schema not found: %v", err)
+	}
+
+	if fork.UserID != userID {
+		return nil, nil, fmt.Errorf("access denied: you can only pull into your own schemas")
+	}
+	if fork.ForkedFrom == nil {
+		return nil, nil, fmt.Errorf("schema is not a fork")
+	}
+
+	upstream, err := s.GetSchemaByID(ctx, fork.ForkedFrom.SchemaID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("upstream schema not found: %v", err)
+	}
+
+	base, err := s.schemaVersion(ctx, upstream, fork.ForkedFrom.SyncedVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidate, conflicts := MergeSchemas(versionSchema(upstream, base), fork, upstream, resolutions)
+	preview := &UpstreamPreview{
+		UpstreamID:      upstream.ID,
+		SyncedVersion:   fork.ForkedFrom.SyncedVersion,
+		UpstreamVersion: upstream.Version,
+		ForkVersion:     fork.Version,
+		UpToDate:        upstream.Version == fork.ForkedFrom.SyncedVersion,
+		Namespaces:      candidate.Namespaces,
+		Tables:          candidate.Tables,
+		Enums:           candidate.Enums,
+		Views:           candidate.Views,
+		Conflicts:       conflicts,
+	}
+	for _, conflict := range conflicts {
+		if conflict.Resolution == "" {
+			preview.Unresolved++
+		}
+	}
+
+	normalizeKeys(candidate.Tables)
+	normalizeReferences(candidate.Tables)
+	if err := ValidateSchemaDefinition(candidate); err != nil {
+		if validationErr, ok := err.(*SchemaValidationError); ok {
+			preview.Issues = validationErr.Issues
+		}
+	}
+
+	return fork, preview, nil
+}
+
+// EnsureStarIndex creates the index that keeps stars unique per user.
+func (s *SchemaService) EnsureStarIndex(ctx context.Context) error {
+	return s.starRepo.EnsureIndex(ctx)
+}
+
+// StarSchema stars or unstars a public schema for the user.
+func (s *SchemaService) StarSchema(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, starred bool) (*StarStatus, error) {
+	schema, err := s.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if starred && !schema.IsPublic {
+		return nil, fmt.Errorf("access denied: only public schemas can be starred")
+	}
+
+	var changed bool
+	delta := 1
+	if starred {
+		changed, err = s.starRepo.Add(ctx, id, userID)
+	} else {
+		changed, err = s.starRepo.Remove(ctx, id, userID)
+		delta = -1
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := &StarStatus{Starred: starred, StarCount: schema.StarCount}
+	if changed {
+		if err := s.schemaRepo.AdjustCount(ctx, id, "star_count", delta); err != nil {
+			return nil, err
+		}
+		status.StarCount += delta
+	}
+
+	return status, nil
+}
+
+func (s *SchemaService) GetStarStatus(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*StarStatus, error) {
+	schema, err := s.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	starred, err := s.starRepo.IsStarred(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StarStatus{Starred: starred, StarCount: schema.StarCount}, nil
+}
@@ -0,0 +1,568 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	GoNullablePointer = "pointer"
+	GoNullableSQL     = "sql"
+)
+
+var goTagNames = []string{"db", "json", "gorm", "bson"}
+
+var goDefaultTags = []string{"json", "gorm"}
+
+var goInitialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "UUID": true, "JSON": true, "XML": true, "API": true, "HTTP": true,
+	"HTTPS": true, "IP": true, "SQL": true, "HTML": true, "CSS": true, "UI": true, "SKU": true, "TTL": true,
+}
+
+// goTypes maps SQL base types to Go types. Unknown types become string.
+var goTypes = map[string]string{
+	"TINYINT": "int8", "SMALLINT": "int16", "INT2": "int16", "SMALLSERIAL": "int16", "YEAR": "int16",
+	"INTEGER": "int32", "INT": "int32", "INT4": "int32", "MEDIUMINT": "int32", "SERIAL": "int32",
+	"BIGINT": "int64", "INT8": "int64", "BIGSERIAL": "int64", "OID": "uint32",
+	"REAL": "float32", "FLOAT4": "float32", "FLOAT": "float64", "FLOAT8": "float64", "DOUBLE": "float64",
+	"DOUBLE PRECISION": "float64", "DECIMAL": "float64", "NUMERIC": "float64", "MONEY": "float64",
+	"BOOLEAN": "bool", "BOOL": "bool",
+	"TIMESTAMP": "time.Time", "TIMESTAMPTZ": "time.Time", "TIMESTAMP WITH TIME ZONE": "time.Time",
+	"TIMESTAMP WITHOUT TIME ZONE": "time.Time", "DATETIME": "time.Time", "DATE": "time.Time",
+	"TIME": "time.Time", "TIMETZ": "time.Time",
+	"JSON": "json.RawMessage", "JSONB": "json.RawMessage",
+	"BYTEA": "[]byte", "BLOB": "[]byte", "BINARY": "[]byte", "VARBINARY": "[]byte", "TINYBLOB": "[]byte",
+	"MEDIUMBLOB": "[]byte", "LONGBLOB": "[]byte",
+}
+
+var goSQLNullTypes = map[string]string{
+	"string":    "sql.NullString",
+	"int16":     "sql.NullInt16",
+	"int32":     "sql.NullInt32",
+	"int64":     "sql.NullInt64",
+	"float64":   "sql.NullFloat64",
+	"bool":      "sql.NullBool",
+	"time.Time": "sql.NullTime",
+}
+
+type GoCodeOptions struct {
+	Package  string
+	Tags     []string
+	Nullable string
+}
+
+// goName converts a column or table name to an exported Go identifier,
+// upper-casing common initialisms: user_id becomes UserID.
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		if goInitialisms[strings.ToUpper(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	result := b.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+func singularize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && !strings.HasSuffix(name, "us") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+func normalizeGoCodeOptions(opts GoCodeOptions) (GoCodeOptions, error) {
+	if opts.Package == "" {
+		opts.Package = "models"
+	}
+	if !token.IsIdentifier(opts.Package) || token.IsKeyword(opts.Package) || opts.Package != strings.ToLower(opts.Package) {
+		return opts, fmt.Errorf("invalid go package name: %s", opts.Package)
+	}
+
+	switch opts.Nullable {
+	case "":
+		opts.Nullable = GoNullablePointer
+	case GoNullablePointer, GoNullableSQL:
+	default:
+		return opts, fmt.Errorf("invalid go nullable style: %s", opts.Nullable)
+	}
+
+	if len(opts.Tags) == 0 {
+		opts.Tags = goDefaultTags
+	}
+	var tags []string
+	for _, name := range goTagNames {
+		for _, tag := range opts.Tags {
+			if strings.EqualFold(strings.TrimSpace(tag), name) {
+				tags = append(tags, name)
+				break
+			}
+		}
+	}
+	for _, tag := range opts.Tags {
+		known := false
+		for _, name := range goTagNames {
+			known = known || strings.EqualFold(strings.TrimSpace(tag), name)
+		}
+		if !known {
+			return opts, fmt.Errorf("invalid go tag: %s", tag)
+		}
+	}
+	opts.Tags = tags
+	return opts, nil
+}
+
+type goGenerator struct {
+	schema    *models.Schema
+	opts      GoCodeOptions
+	structs   map[*models.Table]string
+	enums     map[*models.Enum]string
+	relations []schemaRelation
+}
+
+func (g *goGenerator) hasTag(name string) bool {
+	for _, tag := range g.opts.Tags {
+		if tag == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateGoPackage renders one file per table plus enums.go as a zip of
+// gofmt-formatted Go sources in a directory named after the package.
+func GenerateGoPackage(schema *models.Schema, opts GoCodeOptions) ([]byte, error) {
+	opts, err := normalizeGoCodeOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &goGenerator{
+		schema:    schema,
+		opts:      opts,
+		structs:   make(map[*models.Table]string),
+		enums:     make(map[*models.Enum]string),
+		relations: collectRelations(schema),
+	}
+	taken := make(map[string]bool)
+	name := func(namespace, base string) string {
+		candidate := base
+		if taken[candidate] && namespace != "" {
+			candidate = goName(namespace) + base
+		}
+		for i := 2; taken[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", base, i)
+		}
+		taken[candidate] = true
+		return candidate
+	}
+	for i := range schema.Enums {
+		g.enums[&schema.Enums[i]] = name(schema.Enums[i].Namespace, goName(schema.Enums[i].Name))
+	}
+	for i := range schema.Tables {
+		g.structs[&schema.Tables[i]] = name(schema.Tables[i].Namespace, goName(singularize(schema.Tables[i].Name)))
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := make(map[string]bool)
+	add := func(fileName, source string) error {
+		formatted, err := format.Source([]byte(source))
+		if err != nil {
+			return fmt.Errorf("failed to format generated %s: %v", fileName, err)
+		}
+		for i := 2; files[fileName]; i++ {
+			fileName = fmt.Sprintf("%s_%d.go", strings.TrimSuffix(fileName, ".go"), i)
+		}
+		files[fileName] = true
+		writer, err := archive.Create(opts.Package + "/" + fileName)
+		if err != nil {
+			return fmt.Errorf("failed to write generated code: %v", err)
+		}
+		_, err = writer.Write(formatted)
+		return err
+	}
+
+	if len(schema.Enums) > 0 {
+		if err := add("enums.go", g.enumsFile()); err != nil {
+			return nil, err
+		}
+	}
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		fileName := strings.Trim(fileNameUnsafePattern.ReplaceAllString(strings.ToLower(QualifiedName(table.Namespace, table.Name)), "_"), "_")
+		if fileName == "" {
+			fileName = "table"
+		}
+		if err := add(fileName+".go", g.tableFile(table)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write generated code: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *goGenerator) header(imports map[string]bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by Schema Builder from %q. DO NOT EDIT.\n\npackage %s\n", g.schema.Name, g.opts.Package)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		b.WriteString("\nimport (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+		b.WriteString(")\n")
+	}
+	return b.String()
+}
+
+func goComment(text, indent string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(&b, "%s// %s\n", indent, strings.TrimRight(line, " \t\r"))
+	}
+	return b.String()
+}
+
+func (g *goGenerator) enumsFile() string {
+	var b strings.Builder
+	for i := range g.schema.Enums {
+		enum := &g.schema.Enums[i]
+		typeName := g.enums[enum]
+		fmt.Fprintf(&b, "\n// %s is the %s enum.\n", typeName, QualifiedName(enum.Namespace, enum.Name))
+		if enum.Comment != "" {
+			b.WriteString("//\n" + goComment(enum.Comment, ""))
+		}
+		fmt.Fprintf(&b, "type %s string\n", typeName)
+		if len(enum.Values) == 0 {
+			continue
+		}
+		b.WriteString("\nconst (\n")
+		seen := make(map[string]bool)
+		for _, value := range enum.Values {
+			constName := typeName + goName(value)
+			for k := 2; seen[constName]; k++ {
+				constName = fmt.Sprintf("%s%s%d", typeName, goName(value), k)
+			}
+			seen[constName] = true
+			fmt.Fprintf(&b, "\t%s %s = %q\n", constName, typeName, value)
+		}
+		b.WriteString(")\n")
+	}
+	return g.header(nil) + b.String()
+}
+
+// fieldType returns the Go type of a column and the import it needs.
+func (g *goGenerator) fieldType(table *models.Table, field *models.Field, nullable bool) (string, string) {
+	goType := "string"
+	if enum := resolveEnum(g.schema, table.Namespace, field.Type); enum != nil {
+		goType = g.enums[enum]
+	} else {
+		base, _ := splitColumnType(field)
+		array := strings.HasSuffix(base, "[]")
+		if mapped, ok := goTypes[strings.TrimSuffix(base, "[]")]; ok {
+			goType = mapped
+		}
+		if array {
+			return "[]" + goType, goTypeImport(goType)
+		}
+	}
+
+	if !nullable || strings.HasPrefix(goType, "[]") || goType == "json.RawMessage" {
+		return goType, goTypeImport(goType)
+	}
+	if g.opts.Nullable == GoNullableSQL {
+		if nullType, ok := goSQLNullTypes[goType]; ok {
+			return nullType, "database/sql"
+		}
+	}
+	return "*" + goType, goTypeImport(goType)
+}
+
+func goTypeImport(goType string) string {
+	switch {
+	case strings.Contains(goType, "time."):
+		return "time"
+	case strings.Contains(goType, "json."):
+		return "encoding/json"
+	}
+	return ""
+}
+
+// tag renders the struct tag; db differs from key only for associations,
+// which sqlx-style scanners must skip.
+func (g *goGenerator) tag(key, db string, omitEmpty bool, gorm []string) string {
+	var parts []string
+	for _, name := range g.opts.Tags {
+		value := key
+		switch name {
+		case "db":
+			value = db
+		case "gorm":
+			if len(gorm) == 0 {
+				continue
+			}
+			value = strings.Join(gorm, ";")
+		case "json", "bson":
+			if omitEmpty {
+				value += ",omitempty"
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s:%q", name, value))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "`" + strings.Join(parts, " ") + "`"
+}
+
+type goStructField struct {
+	doc  string
+	name string
+	typ  string
+	tag  string
+}
+
+func (g *goGenerator) tableFile(table *models.Table) string {
+	imports := make(map[string]bool)
+	primaryKey := primaryKeyFields(table)
+	inPrimaryKey := make(map[*models.Field]bool, len(primaryKey))
+	for _, field := range primaryKey {
+		inPrimaryKey[field] = true
+	}
+
+	indexTags := g.indexTags(table)
+	names := make(map[string]bool)
+	fieldNames := make(map[*models.Field]string)
+	var fields []goStructField
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		name := goName(field.Name)
+		for k := 2; names[name]; k++ {
+			name = fmt.Sprintf("%s%d", goName(field.Name), k)
+		}
+		names[name] = true
+		fieldNames[field] = name
+
+		nullable := !field.IsNotNull && !inPrimaryKey[field] && !field.IsPrimaryKey
+		goType, pkg := g.fieldType(table, field, nullable)
+		if pkg != "" {
+			imports[pkg] = true
+		}
+
+		gorm := []string{"column:" + field.Name}
+		if columnType := renderColumnType("", field); columnType != "" && resolveEnum(g.schema, table.Namespace, field.Type) == nil {
+			gorm = append(gorm, "type:"+strings.ToLower(columnType))
+		}
+		if inPrimaryKey[field] {
+			gorm = append(gorm, "primaryKey")
+		}
+		base, _ := splitColumnType(field)
+		switch base {
+		case "SERIAL", "BIGSERIAL", "SMALLSERIAL":
+			gorm = append(gorm, "autoIncrement")
+		default:
+			if inPrimaryKey[field] && len(primaryKey) == 1 && strings.HasPrefix(strings.TrimPrefix(goType, "*"), "int") {
+				gorm = append(gorm, "autoIncrement:false")
+			}
+		}
+		if field.IsNotNull && !inPrimaryKey[field] {
+			gorm = append(gorm, "not null")
+		}
+		if field.IsUnique {
+			gorm = append(gorm, "unique")
+		}
+		if value := strings.TrimSpace(field.DefaultValue); value != "" && !strings.Contains(value, ";") {
+			gorm = append(gorm, "default:"+value)
+		}
+		gorm = append(gorm, indexTags[field]...)
+
+		doc := ""
+		if field.Comment != "" {
+			doc = goComment(field.Comment, "\t")
+		}
+		fields = append(fields, goStructField{doc: doc, name: name, typ: goType, tag: g.tag(field.Name, field.Name, nullable, gorm)})
+	}
+
+	fields = append(fields, g.relationFields(table, names, fieldNames)...)
+
+	structName := g.structs[table]
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n// %s maps the %s table.\n", structName, QualifiedName(table.Namespace, table.Name))
+	if table.Description != "" {
+		b.WriteString("//\n" + goComment(table.Description, ""))
+	}
+	fmt.Fprintf(&b, "type %s struct {\n", structName)
+	for _, field := range fields {
+		b.WriteString(field.doc)
+		fmt.Fprintf(&b, "\t%s %s %s\n", field.name, field.typ, field.tag)
+	}
+	b.WriteString("}\n")
+
+	if g.hasTag("gorm") {
+		fmt.Fprintf(&b, "\n// TableName returns the table name GORM uses for %s.\nfunc (%s) TableName() string {\n\treturn %q\n}\n",
+			structName, structName, QualifiedName(table.Namespace, table.Name))
+	}
+
+	return g.header(imports) + b.String()
+}
+
+// indexTags returns the GORM index and uniqueIndex settings per field. Unique
+// constraints become unique indexes; expression indexes are skipped.
+func (g *goGenerator) indexTags(table *models.Table) map[*models.Field][]string {
+	tags := make(map[*models.Field][]string)
+	add := func(kind, name string, fields []*models.Field) {
+		for i, field := range fields {
+			tag := kind + ":" + name
+			if len(fields) > 1 {
+				tag += fmt.Sprintf(",priority:%d", i+1)
+			}
+			tags[field] = append(tags[field], tag)
+		}
+	}
+
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		if constraintKind(constraint) != ConstraintUnique {
+			continue
+		}
+		fields := constraintFields(table, constraint)
+		if len(fields) < 2 && constraint.Name == "" {
+			continue
+		}
+		name := constraint.Name
+		if name == "" {
+			name = "uq_" + table.Name + "_" + strings.Join(fieldNames(fields), "_")
+		}
+		add("uniqueIndex", name, fields)
+	}
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+		var fields []*models.Field
+		for _, column := range indexColumns(index) {
+			field := findField(table, column.FieldID)
+			if field == nil {
+				fields = nil
+				break
+			}
+			fields = append(fields, field)
+		}
+		if len(fields) == 0 || strings.TrimSpace(index.Where) != "" {
+			continue
+		}
+		kind := "index"
+		if index.IsUnique {
+			kind = "uniqueIndex"
+		}
+		add(kind, indexDisplayName(table, index), fields)
+	}
+	return tags
+}
+
+// relationFields adds GORM associations: a belongs-to pointer on the table
+// holding the foreign key and a has-many slice (or has-one pointer) on the
+// referenced table.
+func (g *goGenerator) relationFields(table *models.Table, names map[string]bool, fieldNames map[*models.Field]string) []goStructField {
+	claim := func(base, fallback string) string {
+		candidate := base
+		if names[candidate] {
+			candidate = base + fallback
+		}
+		for i := 2; names[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", base+fallback, i)
+		}
+		names[candidate] = true
+		return candidate
+	}
+	keys := func(fields []*models.Field) string {
+		parts := make([]string, len(fields))
+		for i, field := range fields {
+			parts[i] = goName(field.Name)
+		}
+		return strings.Join(parts, ",")
+	}
+
+	var fields []goStructField
+	for i := range g.relations {
+		relation := &g.relations[i]
+		by := "By" + strings.ReplaceAll(keys(relation.FromFields), ",", "")
+
+		if relation.From == table {
+			base := g.structs[relation.To]
+			if len(relation.FromFields) == 1 {
+				column := fieldNames[relation.FromFields[0]]
+				if trimmed := strings.TrimSuffix(column, "ID"); trimmed != column && trimmed != "" {
+					base = trimmed
+				}
+			}
+			gorm := []string{"foreignKey:" + keys(relation.FromFields), "references:" + keys(relation.ToFields)}
+			var actions []string
+			if relation.OnDelete != "" {
+				actions = append(actions, "OnDelete:"+strings.ToUpper(relation.OnDelete))
+			}
+			if relation.OnUpdate != "" {
+				actions = append(actions, "OnUpdate:"+strings.ToUpper(relation.OnUpdate))
+			}
+			if len(actions) > 0 {
+				gorm = append(gorm, "constraint:"+strings.Join(actions, ","))
+			}
+			name := claim(base, g.structs[relation.To])
+			fields = append(fields, goStructField{name: name, typ: "*" + g.structs[relation.To], tag: g.tag(goJSONName(name), "-", true, gorm)})
+		}
+
+		if relation.To == table {
+			typ := "[]" + g.structs[relation.From]
+			base := pluralize(singularize(goName(relation.From.Name)))
+			if relation.IsOneToOne() {
+				typ = "*" + g.structs[relation.From]
+				base = g.structs[relation.From]
+			}
+			name := claim(base, by)
+			gorm := []string{"foreignKey:" + keys(relation.FromFields), "references:" + keys(relation.ToFields)}
+			fields = append(fields, goStructField{name: name, typ: typ, tag: g.tag(goJSONName(name), "-", true, gorm)})
+		}
+	}
+	return fields
+}
+
+func goJSONName(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,438 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+var graphQLNamePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// graphQLScalars maps SQL base types to GraphQL scalars. Types without a
+// built-in counterpart use custom scalars declared at the top of the SDL.
+var graphQLScalars = map[string]string{
+	"TINYINT": "Int", "SMALLINT": "Int", "INT2": "Int", "SMALLSERIAL": "Int", "YEAR": "Int",
+	"INTEGER": "Int", "INT": "Int", "INT4": "Int", "MEDIUMINT": "Int", "SERIAL": "Int",
+	"BIGINT": "BigInt", "INT8": "BigInt", "BIGSERIAL": "BigInt", "OID": "BigInt",
+	"REAL": "Float", "FLOAT4": "Float", "FLOAT": "Float", "FLOAT8": "Float", "DOUBLE": "Float", "DOUBLE PRECISION": "Float",
+	"DECIMAL": "Decimal", "NUMERIC": "Decimal", "MONEY": "Decimal",
+	"BOOLEAN": "Boolean", "BOOL": "Boolean",
+	"TIMESTAMP": "DateTime", "TIMESTAMPTZ": "DateTime", "TIMESTAMP WITH TIME ZONE": "DateTime",
+	"TIMESTAMP WITHOUT TIME ZONE": "DateTime", "DATETIME": "DateTime", "DATE": "Date", "TIME": "Time", "TIMETZ": "Time",
+	"JSON": "JSON", "JSONB": "JSON",
+	"BYTEA": "Bytes", "BLOB": "Bytes", "BINARY": "Bytes", "VARBINARY": "Bytes", "TINYBLOB": "Bytes",
+	"MEDIUMBLOB": "Bytes", "LONGBLOB": "Bytes",
+}
+
+var graphQLCustomScalars = map[string]string{
+	"BigInt":   "64-bit integer, serialized as a string when it exceeds the Int range.",
+	"Decimal":  "Exact decimal number, serialized as a string.",
+	"DateTime": "Date and time in ISO 8601 format.",
+	"Date":     "Calendar date in ISO 8601 format.",
+	"Time":     "Time of day in ISO 8601 format.",
+	"JSON":     "Arbitrary JSON value.",
+	"Bytes":    "Binary data, base64 encoded.",
+}
+
+var graphQLReservedNames = []string{"Query", "Mutation", "Subscription", "String", "Int", "Float", "Boolean", "ID"}
+
+func graphQLFieldName(name string) string {
+	result := lowerFirst(pascalCase(name))
+	if strings.HasPrefix(result, "__") {
+		result = strings.TrimLeft(result, "_")
+	}
+	return result
+}
+
+// graphQLEnumValue keeps valid enum values as they are and turns the rest
+// into upper snake case.
+func graphQLEnumValue(value string) string {
+	switch value {
+	case "true", "false", "null":
+		return strings.ToUpper(value)
+	}
+	if graphQLNamePattern.MatchString(value) && !strings.HasPrefix(value, "__") {
+		return value
+	}
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToUpper(value) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteRune('_')
+			underscore = true
+		}
+	}
+	result := strings.TrimRight(b.String(), "_")
+	if result == "" || (result[0] >= '0' && result[0] <= '9') {
+		result = "V_" + result
+	}
+	return result
+}
+
+func graphQLDescription(text, indent string) string {
+	text = strings.TrimSpace(text)
+	if !strings.ContainsAny(text, "\n\"\\") {
+		return fmt.Sprintf("%s\"%s\"\n", indent, text)
+	}
+	var b strings.Builder
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(strings.ReplaceAll(text, `"""`, `\"""`), "\n") {
+		fmt.Fprintf(&b, "%s%s\n", indent, strings.TrimRight(line, " \t\r"))
+	}
+	b.WriteString(indent + `"""` + "\n")
+	return b.String()
+}
+
+type graphQLGenerator struct {
+	schema    *models.Schema
+	types     map[*models.Table]string
+	enums     map[*models.Enum]string
+	relations []schemaRelation
+	scalars   map[string]bool
+}
+
+type graphQLField struct {
+	doc  string
+	name string
+	typ  string
+	args string
+}
+
+// GenerateGraphQL renders a GraphQL SDL with an object type per table,
+// relation fields in both directions, create and update input types and
+// Query and Mutation root fields for basic CRUD.
+func GenerateGraphQL(schema *models.Schema) string {
+	g := &graphQLGenerator{
+		schema:    schema,
+		types:     make(map[*models.Table]string),
+		enums:     make(map[*models.Enum]string),
+		relations: collectRelations(schema),
+		scalars:   make(map[string]bool),
+	}
+
+	taken := make(map[string]bool)
+	for _, name := range graphQLReservedNames {
+		taken[name] = true
+	}
+	for name := range graphQLCustomScalars {
+		taken[name] = true
+	}
+	name := func(namespace, base string) string {
+		candidate := base
+		if taken[candidate] && namespace != "" {
+			candidate = pascalCase(namespace) + base
+		}
+		for i := 2; taken[candidate] || taken["Create"+candidate+"Input"]; i++ {
+			candidate = fmt.Sprintf("%s%d", base, i)
+		}
+		taken[candidate] = true
+		taken["Create"+candidate+"Input"] = true
+		taken["Update"+candidate+"Input"] = true
+		return candidate
+	}
+	for i := range schema.Enums {
+		g.enums[&schema.Enums[i]] = name(schema.Enums[i].Namespace, pascalCase(schema.Enums[i].Name))
+	}
+	for i := range schema.Tables {
+		g.types[&schema.Tables[i]] = name(schema.Tables[i].Namespace, pascalCase(singularize(schema.Tables[i].Name)))
+	}
+
+	var body strings.Builder
+	for i := range schema.Enums {
+		body.WriteString("\n")
+		body.WriteString(g.enum(&schema.Enums[i]))
+	}
+	for i := range schema.Tables {
+		body.WriteString("\n")
+		body.WriteString(g.objectType(&schema.Tables[i]))
+	}
+	for i := range schema.Tables {
+		body.WriteString("\n")
+		body.WriteString(g.inputTypes(&schema.Tables[i]))
+	}
+	body.WriteString(g.rootTypes())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n# Generated by Schema Builder\n", schema.Name)
+	scalars := make([]string, 0, len(g.scalars))
+	for scalar := range g.scalars {
+		scalars = append(scalars, scalar)
+	}
+	sort.Strings(scalars)
+	for _, scalar := range scalars {
+		fmt.Fprintf(&b, "\n%sscalar %s\n", graphQLDescription(graphQLCustomScalars[scalar], ""), scalar)
+	}
+	b.WriteString(body.String())
+	return b.String()
+}
+
+func (g *graphQLGenerator) enum(enum *models.Enum) string {
+	var b strings.Builder
+	if enum.Comment != "" {
+		b.WriteString(graphQLDescription(enum.Comment, ""))
+	}
+	fmt.Fprintf(&b, "enum %s {\n", g.enums[enum])
+	seen := make(map[string]bool)
+	for _, value := range enum.Values {
+		name := graphQLEnumValue(value)
+		for i := 2; seen[name]; i++ {
+			name = fmt.Sprintf("%s_%d", graphQLEnumValue(value), i)
+		}
+		seen[name] = true
+		if name != value {
+			b.WriteString(graphQLDescription(fmt.Sprintf("Stored as %q.", value), "  "))
+		}
+		fmt.Fprintf(&b, "  %s\n", name)
+	}
+	if len(enum.Values) == 0 {
+		// GraphQL enums need at least one value.
+		b.WriteString("  _EMPTY\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// isIDField reports whether a column is the single-column primary key of its
+// table, or references one; such columns use the ID scalar.
+func (g *graphQLGenerator) isIDField(table *models.Table, field *models.Field) bool {
+	if primaryKey := primaryKeyFields(table); len(primaryKey) == 1 && primaryKey[0] == field {
+		return true
+	}
+	for _, relation := range g.relations {
+		if relation.From != table || len(relation.FromFields) != 1 || relation.FromFields[0] != field {
+			continue
+		}
+		if primaryKey := primaryKeyFields(relation.To); len(primaryKey) == 1 && primaryKey[0] == relation.ToFields[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *graphQLGenerator) scalarType(table *models.Table, field *models.Field) string {
+	if enum := resolveEnum(g.schema, table.Namespace, field.Type); enum != nil {
+		return g.enums[enum]
+	}
+	if g.isIDField(table, field) {
+		return "ID"
+	}
+
+	base, _ := splitColumnType(field)
+	array := strings.HasSuffix(base, "[]")
+	scalar, ok := graphQLScalars[strings.TrimSuffix(base, "[]")]
+	if !ok {
+		scalar = "String"
+	}
+	if _, custom := graphQLCustomScalars[scalar]; custom {
+		g.scalars[scalar] = true
+	}
+	if array {
+		return "[" + scalar + "]"
+	}
+	return scalar
+}
+
+func graphQLRequired(table *models.Table, field *models.Field) bool {
+	if field.IsNotNull || field.IsPrimaryKey {
+		return true
+	}
+	for _, key := range primaryKeyFields(table) {
+		if key == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *graphQLGenerator) objectType(table *models.Table) string {
+	names := make(map[string]bool)
+	var fields []graphQLField
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		name := graphQLFieldName(field.Name)
+		for k := 2; names[name]; k++ {
+			name = fmt.Sprintf("%s%d", graphQLFieldName(field.Name), k)
+		}
+		names[name] = true
+		typ := g.scalarType(table, field)
+		if graphQLRequired(table, field) {
+			typ += "!"
+		}
+		doc := ""
+		if field.Comment != "" {
+			doc = graphQLDescription(field.Comment, "  ")
+		}
+		fields = append(fields, graphQLField{doc: doc, name: name, typ: typ})
+	}
+	fields = append(fields, g.relationFields(table, names)...)
+	if len(fields) == 0 {
+		// Object types need at least one field.
+		fields = append(fields, graphQLField{name: "_empty", typ: "Boolean"})
+	}
+
+	var b strings.Builder
+	if table.Description != "" {
+		b.WriteString(graphQLDescription(table.Description, ""))
+	}
+	fmt.Fprintf(&b, "type %s {\n", g.types[table])
+	for _, field := range fields {
+		fmt.Fprintf(&b, "%s  %s%s: %s\n", field.doc, field.name, field.args, field.typ)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// relationFields resolves a foreign key to the referenced object on one
+// side and to the referencing objects on the other.
+func (g *graphQLGenerator) relationFields(table *models.Table, names map[string]bool) []graphQLField {
+	claim := func(base, fallback string) string {
+		candidate := base
+		if names[candidate] {
+			candidate = base + fallback
+		}
+		for i := 2; names[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", base+fallback, i)
+		}
+		names[candidate] = true
+		return candidate
+	}
+
+	var fields []graphQLField
+	for i := range g.relations {
+		relation := &g.relations[i]
+		by := "By" + pascalCase(strings.Join(fieldNames(relation.FromFields), "_"))
+		if relation.From == table {
+			target := g.types[relation.To]
+			base := lowerFirst(target)
+			if len(relation.FromFields) == 1 {
+				column := graphQLFieldName(relation.FromFields[0].Name)
+				if trimmed := strings.TrimSuffix(column, "Id"); trimmed != column && trimmed != "" {
+					base = trimmed
+				}
+			}
+			typ := target + "!"
+			for _, field := range relation.FromFields {
+				if !graphQLRequired(table, field) {
+					typ = target
+				}
+			}
+			fields = append(fields, graphQLField{name: claim(base, by), typ: typ})
+		}
+
+		if relation.To == table {
+			source := g.types[relation.From]
+			if relation.IsOneToOne() {
+				fields = append(fields, graphQLField{name: claim(lowerFirst(source), by), typ: source})
+			} else {
+				fields = append(fields, graphQLField{
+					name: claim(graphQLFieldName(relation.From.Name), by),
+					args: "(limit: Int = 50, offset: Int = 0)",
+					typ:  "[" + source + "!]!",
+				})
+			}
+		}
+	}
+	return fields
+}
+
+// inputTypes renders the create input, where required columns without a
+// default stay required, and the update input, where every column but the
+// primary key is optional.
+func (g *graphQLGenerator) inputTypes(table *models.Table) string {
+	name := g.types[table]
+	inPrimaryKey := make(map[*models.Field]bool)
+	for _, field := range primaryKeyFields(table) {
+		inPrimaryKey[field] = true
+	}
+	var create, update strings.Builder
+	seen := make(map[string]bool)
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		fieldName := graphQLFieldName(field.Name)
+		for k := 2; seen[fieldName]; k++ {
+			fieldName = fmt.Sprintf("%s%d", graphQLFieldName(field.Name), k)
+		}
+		seen[fieldName] = true
+		if isSerialField(field) {
+			continue
+		}
+		typ := g.scalarType(table, field)
+		createType := typ
+		if graphQLRequired(table, field) && strings.TrimSpace(field.DefaultValue) == "" {
+			createType += "!"
+		}
+		fmt.Fprintf(&create, "  %s: %s\n", fieldName, createType)
+		if !inPrimaryKey[field] {
+			fmt.Fprintf(&update, "  %s: %s\n", fieldName, typ)
+		}
+	}
+
+	var b strings.Builder
+	for _, input := range []struct{ prefix, body string }{{"Create", create.String()}, {"Update", update.String()}} {
+		if input.prefix == "Update" {
+			b.WriteString("\n")
+		}
+		if input.body == "" {
+			// Input objects need at least one field.
+			input.body = "  _empty: Boolean\n"
+		}
+		fmt.Fprintf(&b, "input %s%sInput {\n%s}\n", input.prefix, name, input.body)
+	}
+	return b.String()
+}
+
+// keyArguments returns the primary key arguments of a table, or an empty
+// string when the table has no primary key to address rows by.
+func (g *graphQLGenerator) keyArguments(table *models.Table) string {
+	var args []string
+	for _, field := range primaryKeyFields(table) {
+		args = append(args, fmt.Sprintf("%s: %s!", graphQLFieldName(field.Name), g.scalarType(table, field)))
+	}
+	return strings.Join(args, ", ")
+}
+
+func (g *graphQLGenerator) rootTypes() string {
+	var query, mutation strings.Builder
+	taken := make(map[string]bool)
+	claim := func(name string) string {
+		candidate := name
+		for i := 2; taken[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", name, i)
+		}
+		taken[candidate] = true
+		return candidate
+	}
+
+	for i := range g.schema.Tables {
+		table := &g.schema.Tables[i]
+		name := g.types[table]
+		single := lowerFirst(name)
+		list := graphQLFieldName(table.Name)
+		if list == single {
+			list = "all" + name
+		}
+		keys := g.keyArguments(table)
+
+		if keys != "" {
+			fmt.Fprintf(&query, "  %s(%s): %s\n", claim(single), keys, name)
+		}
+		fmt.Fprintf(&query, "  %s(limit: Int = 50, offset: Int = 0): [%s!]!\n", claim(list), name)
+
+		fmt.Fprintf(&mutation, "  %s(input: Create%sInput!): %s!\n", claim("create"+name), name, name)
+		if keys != "" {
+			fmt.Fprintf(&mutation, "  %s(%s, input: Update%sInput!): %s\n", claim("update"+name), keys, name, name)
+			fmt.Fprintf(&mutation, "  %s(%s): Boolean!\n", claim("delete"+name), keys)
+		}
+	}
+
+	if query.Len() == 0 {
+		return ""
+	}
+	return "\ntype Query {\n" + query.String() + "}\n\ntype Mutation {\n" + mutation.String() + "}\n"
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	IndexMethodBTree    = "btree"
+	IndexMethodHash     = "hash"
+	IndexMethodGIN      = "gin"
+	IndexMethodGiST     = "gist"
+	IndexMethodSPGiST   = "spgist"
+	IndexMethodBRIN     = "brin"
+	IndexMethodFullText = "fulltext"
+	IndexMethodSpatial  = "spatial"
+)
+
+var indexMethodsByDialect = map[SQLDialect]map[string]bool{
+	DialectPostgreSQL: {
+		IndexMethodBTree:  true,
+		IndexMethodHash:   true,
+		IndexMethodGIN:    true,
+		IndexMethodGiST:   true,
+		IndexMethodSPGiST: true,
+		IndexMethodBRIN:   true,
+	},
+	DialectMySQL: {
+		IndexMethodBTree:    true,
+		IndexMethodHash:     true,
+		IndexMethodFullText: true,
+		IndexMethodSpatial:  true,
+	},
+	DialectSQLite: {
+		IndexMethodBTree: true,
+	},
+}
+
+func indexMethod(index *models.Index) string {
+	method := strings.ToLower(strings.TrimSpace(index.Type))
+	method = strings.NewReplacer("-", "", "_", "", " ", "").Replace(method)
+	switch method {
+	case "", "index", "normal", "unique":
+		return ""
+	case "fulltextindex", "fts":
+		return IndexMethodFullText
+	}
+	return method
+}
+
+func isKnownIndexMethod(method string) bool {
+	for _, methods := range indexMethodsByDialect {
+		if methods[method] {
+			return true
+		}
+	}
+	return false
+}
+
+// indexColumns returns the key parts of an index, falling back to the plain
+// Fields list for indexes stored before per-column options existed.
+func indexColumns(index *models.Index) []models.IndexColumn {
+	if len(index.Columns) > 0 {
+		return index.Columns
+	}
+	columns := make([]models.IndexColumn, len(index.Fields))
+	for i, ref := range index.Fields {
+		columns[i] = models.IndexColumn{FieldID: ref}
+	}
+	return columns
+}
+
+func indexDisplayName(table *models.Table, index *models.Index) string {
+	if index.Name != "" {
+		return index.Name
+	}
+
+	var parts []string
+	for _, column := range indexColumns(index) {
+		if field := findField(table, column.FieldID); field != nil {
+			parts = append(parts, field.Name)
+		} else if column.Expression != "" {
+			parts = append(parts, "expr")
+		}
+	}
+	return fmt.Sprintf("idx_%s_%s", table.Name, strings.Join(parts, "_"))
+}
+
+// isQualifiedIdentifier accepts a plain or namespace-qualified name, such as
+// pg_catalog.text_pattern_ops.
+func isQualifiedIdentifier(name string) bool {
+	for _, part := range strings.SplitN(name, ".", 2) {
+		if !identifierPattern.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateIndexes(schema *models.Schema, table *models.Table, addIssue func(format string, args ...interface{})) {
+	dialect, hasDialect := ParseSQLDialect(schema.DatabaseType)
+	tableName := QualifiedName(table.Namespace, table.Name)
+
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+		label := indexDisplayName(table, index)
+		method := indexMethod(index)
+
+		columns := indexColumns(index)
+		if len(columns) == 0 {
+			addIssue("index %q on %q has no columns", label, tableName)
+		}
+
+		for _, column := range columns {
+			switch {
+			case column.FieldID != "" && column.Expression != "":
+				addIssue("index %q on %q mixes a field and an expression in one column", label, tableName)
+			case column.FieldID == "" && strings.TrimSpace(column.Expression) == "":
+				addIssue("index %q on %q has an empty column", label, tableName)
+			case column.FieldID != "" && findField(table, column.FieldID) == nil:
+				addIssue("index %q on %q references unknown field %q", label, tableName, column.FieldID)
+			}
+
+			if order := strings.ToLower(column.Order); order != "" && order != "asc" && order != "desc" {
+				addIssue("index %q on %q has invalid sort order %q", label, tableName, column.Order)
+			}
+			if nulls := strings.ToLower(column.Nulls); nulls != "" && nulls != "first" && nulls != "last" {
+				addIssue("index %q on %q has invalid nulls ordering %q", label, tableName, column.Nulls)
+			}
+			if column.Collation != "" && !collationPattern.MatchString(column.Collation) {
+				addIssue("index %q on %q has invalid collation %q", label, tableName, column.Collation)
+			}
+			if column.OpClass != "" && !isQualifiedIdentifier(column.OpClass) {
+				addIssue("index %q on %q has invalid operator class %q", label, tableName, column.OpClass)
+			}
+		}
+
+		for _, ref := range index.Include {
+			if findField(table, ref) == nil {
+				addIssue("index %q on %q includes unknown field %q", label, tableName, ref)
+			}
+		}
+
+		if method != "" && !isKnownIndexMethod(method) {
+			addIssue("index %q on %q uses unknown method %q", label, tableName, index.Type)
+			continue
+		}
+		if index.IsUnique && (method == IndexMethodFullText || method == IndexMethodSpatial || method == IndexMethodGIN || method == IndexMethodBRIN) {
+			addIssue("index %q on %q cannot be unique with method %q", label, tableName, method)
+		}
+		if len(index.Include) > 0 && method != "" && method != IndexMethodBTree && method != IndexMethodGiST {
+			addIssue("index %q on %q cannot use INCLUDE columns with method %q", label, tableName, method)
+		}
+
+		if !hasDialect {
+			continue
+		}
+		for _, issue := range unsupportedIndexFeatures(dialect, index) {
+			addIssue("index %q on %q: %s", label, tableName, issue)
+		}
+	}
+}
+
+// unsupportedIndexFeatures lists the parts of an index definition that the
+// dialect cannot express. The DDL generator omits them; validation rejects
+// them when the schema targets that dialect.
+func unsupportedIndexFeatures(dialect SQLDialect, index *models.Index) []string {
+	var issues []string
+
+	if method := indexMethod(index); method != "" && !indexMethodsByDialect[dialect][method] {
+		issues = append(issues, fmt.Sprintf("method %q is not supported by %s", method, dialect))
+	}
+	if len(index.Include) > 0 && dialect != DialectPostgreSQL {
+		issues = append(issues, fmt.Sprintf("INCLUDE columns are not supported by %s", dialect))
+	}
+	if strings.TrimSpace(index.Where) != "" && dialect == DialectMySQL {
+		issues = append(issues, fmt.Sprintf("partial indexes are not supported by %s", dialect))
+	}
+
+	for _, column := range indexColumns(index) {
+		if column.Collation != "" && dialect == DialectMySQL {
+			issues = append(issues, fmt.Sprintf("per-column collation is not supported by %s", dialect))
+			break
+		}
+	}
+	for _, column := range indexColumns(index) {
+		if column.Nulls != "" && dialect != DialectPostgreSQL {
+			issues = append(issues, fmt.Sprintf("NULLS FIRST/LAST is not supported by %s", dialect))
+			break
+		}
+	}
+	for _, column := range indexColumns(index) {
+		if column.OpClass != "" && dialect != DialectPostgreSQL {
+			issues = append(issues, fmt.Sprintf("operator classes are not supported by %s", dialect))
+			break
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Formats are annotations unless a validator opts in, so the string types
+// with a fixed layout are also checked with a pattern.
+var jsonSchemaPatterns = map[string]string{
+	"UUID":   `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+	"TIME":   `^[0-9]{2}:[0-9]{2}(:[0-9]{2}(\.[0-9]+)?)?$`,
+	"TIMETZ": `^[0-9]{2}:[0-9]{2}(:[0-9]{2}(\.[0-9]+)?)?(Z|[+-][0-9]{2}(:?[0-9]{2})?)?$`,
+}
+
+// tableFileNames returns a file name stem per table, unique within the
+// schema, for generators writing one file per table.
+func tableFileNames(schema *models.Schema) map[*models.Table]string {
+	names := make(map[*models.Table]string, len(schema.Tables))
+	taken := make(map[string]bool)
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		base := strings.Trim(fileNameUnsafePattern.ReplaceAllString(strings.ToLower(QualifiedName(table.Namespace, table.Name)), "_"), "_")
+		if base == "" {
+			base = "table"
+		}
+		name := base
+		for k := 2; taken[name]; k++ {
+			name = fmt.Sprintf("%s_%d", base, k)
+		}
+		taken[name] = true
+		names[table] = name
+	}
+	return names
+}
+
+type archiveFile struct {
+	name    string
+	content []byte
+}
+
+func zipArchive(files []archiveFile) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", file.name, err)
+		}
+		if _, err := writer.Write(file.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalDocument(document interface{}) ([]byte, error) {
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %v", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// GenerateJSONSchemas renders one JSON Schema (draft 2020-12) document per
+// table describing a row, returned as a zip of <table>.schema.json files.
+func GenerateJSONSchemas(schema *models.Schema) ([]byte, error) {
+	fileNames := tableFileNames(schema)
+	var files []archiveFile
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		fileName := fileNames[table] + ".schema.json"
+		content, err := marshalDocument(jsonSchemaDocument(schema, table, fileName))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archiveFile{name: fileName, content: content})
+	}
+	return zipArchive(files)
+}
+
+func jsonSchemaDocument(schema *models.Schema, table *models.Table, id string) *orderedMap {
+	inPrimaryKey := make(map[*models.Field]bool)
+	for _, field := range primaryKeyFields(table) {
+		inPrimaryKey[field] = true
+	}
+
+	defs := newOrderedMap()
+	defNames := make(map[*models.Enum]string)
+	enumRef := func(enum *models.Enum) *orderedMap {
+		name, ok := defNames[enum]
+		if !ok {
+			base := pascalCase(enum.Name)
+			if _, taken := defs.values[base]; taken && enum.Namespace != "" {
+				base = pascalCase(enum.Namespace) + base
+			}
+			name = base
+			for k := 2; defs.values[name] != nil; k++ {
+				name = fmt.Sprintf("%s%d", base, k)
+			}
+			values := make([]interface{}, len(enum.Values))
+			for k, value := range enum.Values {
+				values[k] = value
+			}
+			definition := newOrderedMap().Set("type", "string").Set("enum", values)
+			if enum.Comment != "" {
+				definition.Set("description", enum.Comment)
+			}
+			defs.Set(name, definition)
+			defNames[enum] = name
+		}
+		return newOrderedMap().Set("$ref", "#/$defs/"+name)
+	}
+
+	properties := newOrderedMap()
+	var required []interface{}
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		notNull := field.IsNotNull || field.IsPrimaryKey || inPrimaryKey[field]
+		if notNull {
+			required = append(required, field.Name)
+		}
+
+		var property *orderedMap
+		if enum := resolveEnum(schema, table.Namespace, field.Type); enum != nil {
+			property = enumRef(enum)
+			if !notNull {
+				property = newOrderedMap().Set("anyOf", []interface{}{property, newOrderedMap().Set("type", "null")})
+			}
+		} else {
+			property = jsonSchemaColumn(field, !notNull)
+		}
+		openAPIDefault(property, field.DefaultValue)
+		if field.Comment != "" {
+			property.Set("description", field.Comment)
+		}
+		if isSerialField(field) {
+			property.Set("readOnly", true)
+		}
+		properties.Set(field.Name, property)
+	}
+
+	document := newOrderedMap().
+		Set("$schema", jsonSchemaDialect).
+		Set("$id", id).
+		Set("title", QualifiedName(table.Namespace, table.Name))
+	if table.Description != "" {
+		document.Set("description", table.Description)
+	}
+	document.Set("type", "object")
+	if len(required) > 0 {
+		document.Set("required", required)
+	}
+	document.Set("properties", properties).Set("additionalProperties", false)
+	if len(defs.keys) > 0 {
+		document.Set("$defs", defs)
+	}
+	return document
+}
+
+// jsonSchemaColumn describes the value of a column that is not an enum.
+// Nullable columns accept null through the type list.
+func jsonSchemaColumn(field *models.Field, nullable bool) *orderedMap {
+	base, _ := splitColumnType(field)
+	array := strings.HasSuffix(base, "[]")
+	base = strings.TrimSuffix(base, "[]")
+
+	var typ interface{}
+	attributes := newOrderedMap()
+	if format, ok := openAPIIntegerFormats[base]; ok {
+		typ = "integer"
+		attributes.Set("format", format)
+	} else if format, ok := openAPINumberFormats[base]; ok {
+		typ = "number"
+		if format != "" {
+			attributes.Set("format", format)
+		}
+	} else if base == "BOOLEAN" || base == "BOOL" {
+		typ = "boolean"
+	} else if base != "JSON" && base != "JSONB" {
+		typ = "string"
+		if format, ok := openAPIStringFormats[base]; ok {
+			attributes.Set("format", format)
+		}
+		if pattern, ok := jsonSchemaPatterns[base]; ok {
+			attributes.Set("pattern", pattern)
+		}
+		if openAPIBinaryTypes[base] {
+			attributes.Set("contentEncoding", "base64")
+		}
+		if field.Length > 0 && !array {
+			attributes.Set("maxLength", field.Length)
+		}
+	}
+
+	if array {
+		items := newOrderedMap()
+		if typ != nil {
+			items.Set("type", typ)
+		}
+		for _, key := range attributes.keys {
+			items.Set(key, attributes.values[key])
+		}
+		typ, attributes = "array", newOrderedMap().Set("items", items)
+	}
+
+	// JSON columns accept any value, null included.
+	schema := newOrderedMap()
+	if typ != nil {
+		if nullable {
+			schema.Set("type", []interface{}{typ, "null"})
+		} else {
+			schema.Set("type", typ)
+		}
+	}
+	for _, key := range attributes.keys {
+		schema.Set(key, attributes.values[key])
+	}
+	return schema
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	ConstraintPrimaryKey = "primary_key"
+	ConstraintUnique     = "unique"
+	ConstraintForeignKey = "foreign_key"
+	ConstraintCheck      = "check"
+)
+
+func constraintKind(constraint *models.Constraint) string {
+	kind := strings.ToLower(strings.TrimSpace(constraint.Type))
+	kind = strings.NewReplacer(" ", "_", "-", "_").Replace(kind)
+	switch kind {
+	case "pk", "primary", "primary_key":
+		return ConstraintPrimaryKey
+	case "fk", "foreign", "foreign_key", "references":
+		return ConstraintForeignKey
+	case "unique", "unique_key", "uq":
+		return ConstraintUnique
+	}
+	return kind
+}
+
+// normalizeKeys upgrades tables written before composite keys existed: the
+// primary key becomes an ordered list of field IDs, and single-column
+// constraints move to their list forms. It reports whether anything changed.
+func normalizeKeys(tables []models.Table) bool {
+	changed := false
+
+	for i := range tables {
+		table := &tables[i]
+
+		constraints := table.Constraints[:0]
+		for _, constraint := range table.Constraints {
+			if len(constraint.Fields) == 0 && constraint.Field != "" {
+				constraint.Fields = []string{constraint.Field}
+				constraint.Field = ""
+				changed = true
+			}
+			if len(constraint.ReferenceFields) == 0 && constraint.ReferenceField != "" {
+				constraint.ReferenceFields = []string{constraint.ReferenceField}
+				constraint.ReferenceField = ""
+				changed = true
+			}
+			if constraintKind(&constraint) == ConstraintPrimaryKey {
+				if len(table.PrimaryKey) == 0 {
+					table.PrimaryKey = resolveFieldIDs(table, constraint.Fields)
+				}
+				changed = true
+				continue
+			}
+			constraints = append(constraints, constraint)
+		}
+		if len(constraints) == 0 {
+			constraints = nil
+		}
+		table.Constraints = constraints
+
+		if len(table.PrimaryKey) == 0 {
+			for _, field := range table.Fields {
+				if field.IsPrimaryKey {
+					table.PrimaryKey = append(table.PrimaryKey, field.ID)
+					changed = true
+				}
+			}
+		}
+
+		inKey := make(map[string]bool, len(table.PrimaryKey))
+		for _, id := range table.PrimaryKey {
+			inKey[id] = true
+		}
+		for j := range table.Fields {
+			if table.Fields[j].IsPrimaryKey != inKey[table.Fields[j].ID] {
+				table.Fields[j].IsPrimaryKey = inKey[table.Fields[j].ID]
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+func resolveFieldIDs(table *models.Table, refs []string) []string {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if field := findField(table, ref); field != nil {
+			ids = append(ids, field.ID)
+		}
+	}
+	return ids
+}
+
+func primaryKeyFields(table *models.Table) []*models.Field {
+	var fields []*models.Field
+	if len(table.PrimaryKey) > 0 {
+		for _, ref := range table.PrimaryKey {
+			if field := findField(table, ref); field != nil {
+				fields = append(fields, field)
+			}
+		}
+		return fields
+	}
+
+	for i := range table.Fields {
+		if table.Fields[i].IsPrimaryKey {
+			fields = append(fields, &table.Fields[i])
+		}
+	}
+	return fields
+}
+
+func constraintFields(table *models.Table, constraint *models.Constraint) []*models.Field {
+	refs := constraint.Fields
+	if len(refs) == 0 && constraint.Field != "" {
+		refs = []string{constraint.Field}
+	}
+
+	fields := make([]*models.Field, 0, len(refs))
+	for _, ref := range refs {
+		field := findField(table, ref)
+		if field == nil {
+			return nil
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func constraintReferenceFields(target *models.Table, constraint *models.Constraint) []*models.Field {
+	refs := constraint.ReferenceFields
+	if len(refs) == 0 && constraint.ReferenceField != "" {
+		refs = []string{constraint.ReferenceField}
+	}
+
+	fields := make([]*models.Field, 0, len(refs))
+	for _, ref := range refs {
+		field := findField(target, ref)
+		if field == nil {
+			return nil
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// isCandidateKey reports whether the fields, in any order, form the primary
+// key or a unique constraint or index of the table.
+func isCandidateKey(table *models.Table, fields []*models.Field) bool {
+	matches := func(candidate []*models.Field) bool {
+		if len(candidate) != len(fields) || len(candidate) == 0 {
+			return false
+		}
+		ids := make(map[string]bool, len(candidate))
+		for _, field := range candidate {
+			ids[field.ID] = true
+		}
+		for _, field := range fields {
+			if !ids[field.ID] {
+				return false
+			}
+		}
+		return true
+	}
+
+	if matches(primaryKeyFields(table)) {
+		return true
+	}
+	if len(fields) == 1 && fields[0].IsUnique {
+		return true
+	}
+	for i := range table.Constraints {
+		if constraintKind(&table.Constraints[i]) == ConstraintUnique && matches(constraintFields(table, &table.Constraints[i])) {
+			return true
+		}
+	}
+	for _, index := range table.Indexes {
+		if !index.IsUnique {
+			continue
+		}
+		var indexFields []*models.Field
+		for _, ref := range index.Fields {
+			if field := findField(table, ref); field != nil {
+				indexFields = append(indexFields, field)
+			}
+		}
+		if matches(indexFields) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,940 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	RiskNone   = "none"
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+const (
+	MigrationAddTable         = "add_table"
+	MigrationDropTable        = "drop_table"
+	MigrationRenameTable      = "rename_table"
+	MigrationAddColumn        = "add_column"
+	MigrationAddNotNullColumn = "add_not_null_column"
+	MigrationDropColumn       = "drop_column"
+	MigrationRenameColumn     = "rename_column"
+	MigrationWidenType        = "widen_type"
+	MigrationNarrowType       = "narrow_type"
+	MigrationChangeType       = "change_type"
+	MigrationSetNotNull       = "set_not_null"
+	MigrationDropNotNull      = "drop_not_null"
+	MigrationChangeDefault    = "change_default"
+	MigrationChangePrimaryKey = "change_primary_key"
+	MigrationAddUnique        = "add_unique"
+	MigrationAddIndex         = "add_index"
+	MigrationDropIndex        = "drop_index"
+	MigrationAddForeignKey    = "add_foreign_key"
+	MigrationDropForeignKey   = "drop_foreign_key"
+	MigrationAddCheck         = "add_check"
+	MigrationAddEnumValue     = "add_enum_value"
+	MigrationDropEnumValue    = "drop_enum_value"
+)
+
+var migrationRiskOrder = map[string]int{RiskNone: 0, RiskLow: 1, RiskMedium: 2, RiskHigh: 3}
+
+type MigrationSafetyReport struct {
+	SchemaID    primitive.ObjectID    `json:"schema_id"`
+	FromVersion int                   `json:"from_version"`
+	ToVersion   int                   `json:"to_version"`
+	Dialects    []SQLDialect          `json:"dialects"`
+	Risk        map[SQLDialect]string `json:"risk"`
+	Changes     []MigrationChange     `json:"changes"`
+}
+
+// MigrationChange is one difference between two versions. Impact holds
+// consequences that do not depend on the database.
+type MigrationChange struct {
+	Kind   string          `json:"kind"`
+	Table  string          `json:"table,omitempty"`
+	Field  string          `json:"field,omitempty"`
+	Enum   string          `json:"enum,omitempty"`
+	Detail string          `json:"detail"`
+	Impact string          `json:"impact,omitempty"`
+	Risks  []MigrationRisk `json:"risks"`
+
+	from, to *models.Field
+}
+
+type MigrationRisk struct {
+	Dialect    SQLDialect `json:"dialect"`
+	Risk       string     `json:"risk"`
+	DataLoss   string     `json:"data_loss,omitempty"`
+	Locking    string     `json:"locking"`
+	SaferSteps []string   `json:"safer_steps,omitempty"`
+}
+
+var migrationImpacts = map[string]string{
+	MigrationDropTable:        "Code still reading or writing the table fails once it is gone.",
+	MigrationRenameTable:      "Code still using the old name fails until it is redeployed.",
+	MigrationDropColumn:       "Code still selecting or inserting the column fails once it is gone.",
+	MigrationRenameColumn:     "Code still using the old name fails until it is redeployed.",
+	MigrationAddNotNullColumn: "Existing rows have no value for the new column.",
+	MigrationSetNotNull:       "Existing NULLs make the change fail; inserts that omit the column start failing.",
+	MigrationAddUnique:        "Existing duplicates make the change fail; later duplicate writes are rejected.",
+	MigrationAddForeignKey:    "Existing orphaned rows make the change fail; writes now check the referenced table.",
+	MigrationChangePrimaryKey: "Foreign keys and code that identify rows by the old key have to change with it.",
+	MigrationAddCheck:         "Existing rows that violate the check make the change fail.",
+	MigrationDropEnumValue:    "Rows holding the removed value have to be changed first.",
+}
+
+var (
+	expandContractRename = []string{
+		"Add the column under the new name and write to both columns from the application.",
+		"Backfill the new column from the old one in batches.",
+		"Switch reads to the new column and deploy.",
+		"Drop the old column in a later migration.",
+	}
+	retireBeforeDrop = []string{
+		"Stop reading and writing it in the application and deploy that first.",
+		"Take a backup of the data.",
+		"Drop it in a later migration, once no running code uses it.",
+	}
+	swapColumn = []string{
+		"Add a new column with the target type.",
+		"Keep it in sync with dual writes or a trigger and backfill it in batches, checking every value converts.",
+		"Switch reads to the new column, then swap names in a short transaction and drop the old column later.",
+	}
+	rebuildSQLiteTable = []string{
+		"Create the new table under a temporary name with the target definition.",
+		"Copy the rows with INSERT INTO ... SELECT, converting values where needed.",
+		"Drop the old table, rename the new one and recreate indexes, triggers and views, all in one transaction with foreign_keys off.",
+		"Run PRAGMA foreign_key_check before committing.",
+	}
+	backfillThenNotNull = map[SQLDialect][]string{
+		DialectPostgreSQL: {
+			"Backfill NULLs in batches.",
+			"ADD CONSTRAINT ... CHECK (column IS NOT NULL) NOT VALID, which only locks briefly.",
+			"VALIDATE CONSTRAINT, which scans without blocking writes.",
+			"SET NOT NULL, which PostgreSQL 12+ proves from the validated check without a scan, then drop the check.",
+		},
+		DialectMySQL: {
+			"Backfill NULLs in batches.",
+			"MODIFY the column to NOT NULL with ALGORITHM=INPLACE, LOCK=NONE, or with an online schema change tool such as gh-ost or pt-online-schema-change.",
+		},
+		DialectSQLite: append([]string{"Backfill NULLs in batches."}, rebuildSQLiteTable...),
+	}
+)
+
+// migrationRisks describes each kind of change per dialect. assess refines
+// the entries that depend on the columns involved.
+var migrationRisks = map[string]map[SQLDialect]MigrationRisk{
+	MigrationAddTable: {
+		DialectPostgreSQL: {Risk: RiskLow, Locking: "No existing rows are touched; foreign keys briefly lock the referenced tables against writes."},
+		DialectMySQL:      {Risk: RiskLow, Locking: "No existing rows are touched; foreign keys take metadata locks on the referenced tables."},
+		DialectSQLite:     {Risk: RiskLow, Locking: "No existing rows are touched."},
+	},
+	MigrationDropTable: {
+		DialectPostgreSQL: {Risk: RiskHigh, DataLoss: "Every row of the table is deleted.", Locking: "Takes an ACCESS EXCLUSIVE lock; it waits behind, and then blocks, every query on the table.", SaferSteps: retireBeforeDrop},
+		DialectMySQL:      {Risk: RiskHigh, DataLoss: "Every row of the table is deleted.", Locking: "Takes an exclusive metadata lock; dropping a large table can also stall the server while its pages are purged.", SaferSteps: retireBeforeDrop},
+		DialectSQLite:     {Risk: RiskHigh, DataLoss: "Every row of the table is deleted.", Locking: "Locks the database for writes while the table's pages are freed.", SaferSteps: retireBeforeDrop},
+	},
+	MigrationRenameTable: {
+		DialectPostgreSQL: {Risk: RiskHigh, Locking: "Catalog change under a brief ACCESS EXCLUSIVE lock.", SaferSteps: []string{
+			"Rename the table and create a view with the old name over it, which stays updatable for simple views.",
+			"Deploy code using the new name.",
+			"Drop the view.",
+		}},
+		DialectMySQL: {Risk: RiskHigh, Locking: "RENAME TABLE is an atomic catalog change under a metadata lock.", SaferSteps: []string{
+			"Rename the table and create a view with the old name over it, which stays updatable for simple views.",
+			"Deploy code using the new name.",
+			"Drop the view.",
+		}},
+		DialectSQLite: {Risk: RiskHigh, Locking: "Catalog change; references in triggers and views are rewritten.", SaferSteps: []string{
+			"Deploy code that can use either name, then rename.",
+		}},
+	},
+	MigrationAddColumn: {
+		DialectPostgreSQL: {Risk: RiskLow, Locking: "Catalog change under a brief ACCESS EXCLUSIVE lock; constant defaults are stored without touching rows (PostgreSQL 11+)."},
+		DialectMySQL:      {Risk: RiskLow, Locking: "ALGORITHM=INSTANT in MySQL 8.0 (as the last column before 8.0.29); older versions rebuild the table in place."},
+		DialectSQLite:     {Risk: RiskLow, Locking: "Catalog change; the default must be a constant."},
+	},
+	MigrationAddNotNullColumn: {
+		DialectPostgreSQL: {Risk: RiskHigh, Locking: "Fails with a not-null violation as soon as the table has rows.", SaferSteps: []string{
+			"Add the column as nullable, or with a constant default.",
+			"Backfill existing rows in batches.",
+			"Make it NOT NULL with a validated CHECK first (NOT VALID, then VALIDATE), then SET NOT NULL.",
+		}},
+		DialectMySQL: {Risk: RiskMedium, DataLoss: "Existing rows silently get the type's implicit default, such as 0, '' or a zero date.", Locking: "ALGORITHM=INSTANT in MySQL 8.0; older versions rebuild the table in place.", SaferSteps: []string{
+			"Add the column as nullable, or with an explicit default.",
+			"Backfill existing rows in batches.",
+			"MODIFY the column to NOT NULL with ALGORITHM=INPLACE, LOCK=NONE.",
+		}},
+		DialectSQLite: {Risk: RiskHigh, Locking: "SQLite rejects ADD COLUMN ... NOT NULL without a non-NULL default.", SaferSteps: []string{
+			"Add the column with a constant default, or as nullable.",
+			"Backfill existing rows, then rebuild the table to add NOT NULL if a default is unwanted.",
+		}},
+	},
+	MigrationDropColumn: {
+		DialectPostgreSQL: {Risk: RiskHigh, DataLoss: "The column's values are lost.", Locking: "Brief ACCESS EXCLUSIVE lock: the column is only marked dropped and its space reclaimed by later rewrites.", SaferSteps: retireBeforeDrop},
+		DialectMySQL:      {Risk: RiskHigh, DataLoss: "The column's values are lost.", Locking: "ALGORITHM=INSTANT from MySQL 8.0.29; older versions rebuild the whole table in place, which is slow on large tables.", SaferSteps: retireBeforeDrop},
+		DialectSQLite:     {Risk: RiskHigh, DataLoss: "The column's values are lost.", Locking: "DROP COLUMN (3.35+) rewrites the table with the database locked, and fails if the column is indexed, constrained or referenced.", SaferSteps: retireBeforeDrop},
+	},
+	MigrationRenameColumn: {
+		DialectPostgreSQL: {Risk: RiskHigh, Locking: "Catalog change under a brief ACCESS EXCLUSIVE lock.", SaferSteps: expandContractRename},
+		DialectMySQL:      {Risk: RiskHigh, Locking: "RENAME COLUMN is an instant catalog change in MySQL 8.0; CHANGE COLUMN on older versions can rebuild the table.", SaferSteps: expandContractRename},
+		DialectSQLite:     {Risk: RiskHigh, Locking: "RENAME COLUMN (3.25+) is a catalog change that also rewrites triggers and views.", SaferSteps: expandContractRename},
+	},
+	MigrationWidenType: {
+		DialectPostgreSQL: {Risk: RiskHigh, Locking: "ALTER COLUMN TYPE rewrites the table and its indexes under an ACCESS EXCLUSIVE lock, blocking reads and writes for the whole rewrite.", SaferSteps: swapColumn},
+		DialectMySQL:      {Risk: RiskMedium, Locking: "MODIFY COLUMN copies the table, blocking writes for the duration.", SaferSteps: []string{"Run the change with an online schema change tool such as gh-ost or pt-online-schema-change."}},
+		DialectSQLite:     {Risk: RiskLow, Locking: "Declared types are affinities and lengths are not enforced, so stored values need no change; updating the declared type needs a table rebuild.", SaferSteps: rebuildSQLiteTable},
+	},
+	MigrationNarrowType: {
+		DialectPostgreSQL: {Risk: RiskHigh, DataLoss: "Fails if a value does not fit; a USING cast that makes it fit truncates or rounds values.", Locking: "Rewrites the table and its indexes under an ACCESS EXCLUSIVE lock, blocking reads and writes.", SaferSteps: []string{
+			"Find and fix the values that do not fit.",
+			"Add a CHECK enforcing the new limit as NOT VALID, then VALIDATE it, so no new offending values arrive.",
+			"Swap in a column of the new type: add it, backfill in batches, switch reads, drop the old one.",
+		}},
+		DialectMySQL: {Risk: RiskHigh, DataLoss: "In strict mode the change fails on values that do not fit; otherwise they are truncated or clipped with only a warning.", Locking: "MODIFY COLUMN copies the table, blocking writes for the duration.", SaferSteps: []string{
+			"Find and fix the values that do not fit.",
+			"Make sure the session uses strict SQL mode so nothing is truncated silently.",
+			"Run the change with an online schema change tool such as gh-ost or pt-online-schema-change.",
+		}},
+		DialectSQLite: {Risk: RiskMedium, DataLoss: "None by SQLite itself: lengths are not enforced, so values that no longer fit stay and can break other consumers.", Locking: "Changing a declared type needs a table rebuild with the database locked.", SaferSteps: append([]string{"Find and fix the values that do not fit."}, rebuildSQLiteTable...)},
+	},
+	MigrationChangeType: {
+		DialectPostgreSQL: {Risk: RiskHigh, DataLoss: "Needs a USING cast; values that do not convert make the change fail, and lossy casts change data.", Locking: "Rewrites the table and its indexes under an ACCESS EXCLUSIVE lock, blocking reads and writes.", SaferSteps: swapColumn},
+		DialectMySQL:      {Risk: RiskHigh, DataLoss: "Values are converted implicitly; in non-strict mode those that do not convert become 0, '' or NULL with only a warning.", Locking: "MODIFY COLUMN copies the table, blocking writes for the duration.", SaferSteps: swapColumn},
+		DialectSQLite:     {Risk: RiskMedium, DataLoss: "Stored values keep their storage class but compare and sort by the new affinity.", Locking: "Changing a declared type needs a table rebuild with the database locked.", SaferSteps: rebuildSQLiteTable},
+	},
+	MigrationSetNotNull: {
+		DialectPostgreSQL: {Risk: RiskHigh, Locking: "SET NOT NULL scans the whole table under an ACCESS EXCLUSIVE lock, blocking reads and writes.", SaferSteps: backfillThenNotNull[DialectPostgreSQL]},
+		DialectMySQL:      {Risk: RiskMedium, DataLoss: "Outside strict mode, existing NULLs are replaced by the type's implicit default.", Locking: "MODIFY COLUMN rebuilds the table in place; concurrent writes are allowed but the rebuild is slow on large tables.", SaferSteps: backfillThenNotNull[DialectMySQL]},
+		DialectSQLite:     {Risk: RiskMedium, Locking: "SQLite cannot alter column constraints; the table has to be rebuilt with the database locked.", SaferSteps: backfillThenNotNull[DialectSQLite]},
+	},
+	MigrationDropNotNull: {
+		DialectPostgreSQL: {Risk: RiskLow, Locking: "Catalog change under a brief ACCESS EXCLUSIVE lock."},
+		DialectMySQL:      {Risk: RiskLow, Locking: "MODIFY COLUMN rebuilds the table in place while allowing concurrent writes."},
+		DialectSQLite:     {Risk: RiskMedium, Locking: "SQLite cannot alter column constraints; the table has to be rebuilt with the database locked.", SaferSteps: rebuildSQLiteTable},
+	},
+	MigrationChangeDefault: {
+		DialectPostgreSQL: {Risk: RiskLow, Locking: "Catalog change under a brief ACCESS EXCLUSIVE lock; only new rows are affected."},
+		DialectMySQL:      {Risk: RiskLow, Locking: "ALTER COLUMN ... SET DEFAULT is an instant catalog change."},
+		DialectSQLite:     {Risk: RiskMedium, Locking: "SQLite cannot alter defaults; the table has to be rebuilt with the database locked.", SaferSteps: rebuildSQLiteTable},
+	},
+	MigrationChangePrimaryKey: {
+		DialectPostgreSQL: {Risk: RiskHigh, Locking: "Dropping and adding the key rebuilds its index with writes blocked, and referencing foreign keys have to be dropped first.", SaferSteps: []string{
+			"Make the new key columns NOT NULL safely and CREATE UNIQUE INDEX CONCURRENTLY on them.",
+			"In one short transaction drop the old key and ADD PRIMARY KEY USING INDEX.",
+			"Recreate referencing foreign keys as NOT VALID, then VALIDATE them.",
+		}},
+		DialectMySQL: {Risk: RiskHigh, Locking: "InnoDB clusters rows by the primary key, so changing it rebuilds the whole table.", SaferSteps: []string{
+			"Run the change with an online schema change tool such as gh-ost or pt-online-schema-change.",
+		}},
+		DialectSQLite: {Risk: RiskHigh, Locking: "The primary key cannot be altered; the table has to be rebuilt with the database locked.", SaferSteps: rebuildSQLiteTable},
+	},
+	MigrationAddUnique: {
+		DialectPostgreSQL: {Risk: RiskHigh, Locking: "Building the unique index blocks writes for its whole duration, which is long on a large table.", SaferSteps: []string{
+			"Find and resolve duplicate values.",
+			"CREATE UNIQUE INDEX CONCURRENTLY, which does not block writes but cannot run inside a transaction.",
+			"If a constraint is wanted, ADD CONSTRAINT ... UNIQUE USING INDEX.",
+		}},
+		DialectMySQL: {Risk: RiskMedium, Locking: "Adding a unique index is online (ALGORITHM=INPLACE, LOCK=NONE), but fails if duplicates exist or are written during the build.", SaferSteps: []string{
+			"Find and resolve duplicate values and stop the application from writing new ones.",
+			"Add the index with ALGORITHM=INPLACE, LOCK=NONE.",
+		}},
+		DialectSQLite: {Risk: RiskMedium, Locking: "CREATE UNIQUE INDEX locks the database for writes until the index is built.", SaferSteps: []string{
+			"Find and resolve duplicate values.",
+		}},
+	},
+	MigrationAddIndex: {
+		DialectPostgreSQL: {Risk: RiskMedium, Locking: "CREATE INDEX blocks writes to the table until the index is built.", SaferSteps: []string{
+			"Use CREATE INDEX CONCURRENTLY outside a transaction; drop and retry if it leaves an INVALID index.",
+		}},
+		DialectMySQL:  {Risk: RiskLow, Locking: "Adding a secondary index is online (ALGORITHM=INPLACE, LOCK=NONE)."},
+		DialectSQLite: {Risk: RiskLow, Locking: "CREATE INDEX locks the database for writes until the index is built."},
+	},
+	MigrationDropIndex: {
+		DialectPostgreSQL: {Risk: RiskLow, Locking: "DROP INDEX takes an ACCESS EXCLUSIVE lock; queries that used the index may slow down.", SaferSteps: []string{
+			"Use DROP INDEX CONCURRENTLY.",
+		}},
+		DialectMySQL:  {Risk: RiskLow, Locking: "Dropping an index is an in-place catalog change; queries that used it may slow down."},
+		DialectSQLite: {Risk: RiskLow, Locking: "Locks the database for writes briefly; queries that used the index may slow down."},
+	},
+	MigrationAddForeignKey: {
+		DialectPostgreSQL: {Risk: RiskHigh, Locking: "Takes SHARE ROW EXCLUSIVE locks on both tables, blocking writes while every row is checked.", SaferSteps: []string{
+			"Clean up orphaned rows.",
+			"ADD CONSTRAINT ... FOREIGN KEY ... NOT VALID, which only locks briefly and checks new rows.",
+			"VALIDATE CONSTRAINT in a separate transaction, which does not block writes.",
+		}},
+		DialectMySQL: {Risk: RiskMedium, Locking: "With foreign_key_checks on, the table is copied with writes blocked; in place is only possible with checks off, which skips validating existing rows.", SaferSteps: []string{
+			"Clean up orphaned rows.",
+			"Add the key with foreign_key_checks=0 and ALGORITHM=INPLACE, then verify with a query for orphans.",
+		}},
+		DialectSQLite: {Risk: RiskMedium, Locking: "Foreign keys cannot be added to an existing table; it has to be rebuilt with the database locked.", SaferSteps: append([]string{"Clean up orphaned rows."}, rebuildSQLiteTable...)},
+	},
+	MigrationDropForeignKey: {
+		DialectPostgreSQL: {Risk: RiskLow, Locking: "Brief locks on both tables."},
+		DialectMySQL:      {Risk: RiskLow, Locking: "In-place catalog change."},
+		DialectSQLite:     {Risk: RiskMedium, Locking: "Foreign keys cannot be dropped from a table; it has to be rebuilt with the database locked.", SaferSteps: rebuildSQLiteTable},
+	},
+	MigrationAddCheck: {
+		DialectPostgreSQL: {Risk: RiskMedium, Locking: "Scans the table under an ACCESS EXCLUSIVE lock, blocking reads and writes.", SaferSteps: []string{
+			"Fix rows that violate the check.",
+			"ADD CONSTRAINT ... CHECK ... NOT VALID, then VALIDATE CONSTRAINT in a separate transaction.",
+		}},
+		DialectMySQL:  {Risk: RiskMedium, Locking: "Adding a check (enforced from MySQL 8.0.16) copies the table, blocking writes.", SaferSteps: []string{"Fix rows that violate the check first."}},
+		DialectSQLite: {Risk: RiskMedium, Locking: "Checks cannot be added to an existing table; it has to be rebuilt with the database locked.", SaferSteps: append([]string{"Fix rows that violate the check."}, rebuildSQLiteTable...)},
+	},
+	MigrationAddEnumValue: {
+		DialectPostgreSQL: {Risk: RiskLow, Locking: "ALTER TYPE ... ADD VALUE is a catalog change; before PostgreSQL 12 it cannot run inside a transaction block."},
+		DialectMySQL:      {Risk: RiskLow, Locking: "Appending ENUM members is instant; inserting them elsewhere, or growing past 255 members, copies each table using the enum."},
+		DialectSQLite:     {Risk: RiskMedium, Locking: "Enums are CHECK constraints, so each table using the enum has to be rebuilt.", SaferSteps: rebuildSQLiteTable},
+	},
+	MigrationDropEnumValue: {
+		DialectPostgreSQL: {Risk: RiskHigh, DataLoss: "The cast to the new type fails on rows still holding the removed value.", Locking: "Enum values cannot be dropped: a new type has to be created and every column switched with ALTER COLUMN TYPE, rewriting those tables under ACCESS EXCLUSIVE locks.", SaferSteps: []string{
+			"Update rows holding the value to a replacement value.",
+			"Create the new enum type, switch columns with ALTER COLUMN TYPE ... USING column::text::new_type, then drop the old type.",
+		}},
+		DialectMySQL: {Risk: RiskHigh, DataLoss: "Outside strict mode rows holding the removed value become ''.", Locking: "MODIFY COLUMN copies each table using the enum, blocking writes.", SaferSteps: []string{
+			"Update rows holding the value to a replacement value first.",
+		}},
+		DialectSQLite: {Risk: RiskHigh, DataLoss: "Copying rows into the rebuilt table fails on rows holding the removed value.", Locking: "Enums are CHECK constraints, so each table using the enum has to be rebuilt.", SaferSteps: append([]string{"Update rows holding the value to a replacement value."}, rebuildSQLiteTable...)},
+	},
+}
+
+// AnalyzeMigrationSafety compares two versions of a schema. From defaults
+// to the version before To; an empty dialect assesses every SQL dialect.
+func (s *SchemaService) AnalyzeMigrationSafety(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, from, to int, dialect string) (*MigrationSafetyReport, error) {
+	dialects := []SQLDialect{DialectPostgreSQL, DialectMySQL, DialectSQLite}
+	if dialect != "" {
+		parsed, ok := ParseSQLDialect(dialect)
+		if !ok {
+			return nil, fmt.Errorf("unsupported dialect: %s", dialect)
+		}
+		dialects = []SQLDialect{parsed}
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	schema, err := s.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	fromVersion, err := s.GetSchemaVersion(ctx, id, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.GetSchemaVersion(ctx, id, userID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := AnalyzeMigrationSafety(versionSchema(schema, fromVersion), versionSchema(schema, toVersion), dialects)
+	report.SchemaID = id
+	s.log.Infof("Migration safety of schema %s from version %d to %d: %d changes", id.Hex(), from, to, len(report.Changes))
+	return report, nil
+}
+
+// AnalyzeMigrationSafety lists the changes from one schema to another,
+// matching tables, fields and enums by ID and then by name, and rates each
+// change per dialect. Tables are assumed to hold data and possibly be large.
+func AnalyzeMigrationSafety(from, to *models.Schema, dialects []SQLDialect) *MigrationSafetyReport {
+	changes := diffForMigration(from, to)
+	report := &MigrationSafetyReport{
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Dialects:    dialects,
+		Risk:        make(map[SQLDialect]string, len(dialects)),
+		Changes:     changes,
+	}
+	for _, dialect := range dialects {
+		report.Risk[dialect] = RiskNone
+	}
+	for i := range report.Changes {
+		change := &report.Changes[i]
+		change.Impact = migrationImpacts[change.Kind]
+		for _, dialect := range dialects {
+			risk := assessMigrationChange(change, dialect)
+			change.Risks = append(change.Risks, risk)
+			if migrationRiskOrder[risk.Risk] > migrationRiskOrder[report.Risk[dialect]] {
+				report.Risk[dialect] = risk.Risk
+			}
+		}
+	}
+	return report
+}
+
+func assessMigrationChange(change *MigrationChange, dialect SQLDialect) MigrationRisk {
+	risk := migrationRisks[change.Kind][dialect]
+	risk.Dialect = dialect
+
+	switch change.Kind {
+	case MigrationAddColumn:
+		if dialect == DialectPostgreSQL && (isSerialField(change.to) || isVolatileDefault(change.to.DefaultValue)) {
+			risk.Risk = RiskHigh
+			risk.Locking = "A serial column or volatile default is evaluated for every existing row, rewriting the table under an ACCESS EXCLUSIVE lock."
+			risk.SaferSteps = []string{
+				"Add the column without a default.",
+				"Set the default for new rows with ALTER COLUMN ... SET DEFAULT.",
+				"Backfill existing rows in batches.",
+			}
+		}
+	case MigrationWidenType:
+		fromBase, _ := splitColumnType(change.from)
+		toBase, _ := splitColumnType(change.to)
+		switch dialect {
+		case DialectPostgreSQL:
+			if (isVarcharType(fromBase) && (isVarcharType(toBase) || toBase == "TEXT")) || (decimalTypes[fromBase] && decimalTypes[toBase] && change.from.Scale == change.to.Scale) {
+				risk.Risk = RiskLow
+				risk.Locking = "Catalog change under a brief ACCESS EXCLUSIVE lock; rows are not rewritten when only a length or precision limit grows."
+				risk.SaferSteps = nil
+			}
+		case DialectMySQL:
+			// With utf8mb4 a VARCHAR stores its length in one byte up to 255
+			// bytes; crossing that boundary forces a copy.
+			if isVarcharType(fromBase) && isVarcharType(toBase) && change.from.Length > 0 && change.to.Length > 0 &&
+				(change.to.Length*4 <= 255 || change.from.Length*4 > 255) {
+				risk.Risk = RiskLow
+				risk.Locking = "Growing a VARCHAR within the same length-prefix size is an in-place catalog change (ALGORITHM=INPLACE, LOCK=NONE), assuming utf8mb4."
+				risk.SaferSteps = nil
+			}
+		}
+	}
+	return risk
+}
+
+func isVarcharType(base string) bool {
+	return base == "VARCHAR" || base == "CHARACTER VARYING" || base == "NVARCHAR"
+}
+
+// isVolatileDefault reports whether a default is a function call evaluated
+// per row, as opposed to a constant or the transaction timestamp.
+func isVolatileDefault(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if !functionLiteralPattern.MatchString(value) {
+		return false
+	}
+	switch value {
+	case "now()", "current_timestamp()", "current_date()", "localtimestamp()", "transaction_timestamp()":
+		return false
+	}
+	return true
+}
+
+func diffForMigration(from, to *models.Schema) []MigrationChange {
+	var changes []MigrationChange
+	matched := make(map[*models.Table]bool)
+	for i := range to.Tables {
+		newTable := &to.Tables[i]
+		oldTable := findTable(from, newTable.ID)
+		if oldTable == nil || matched[oldTable] {
+			oldTable = findTable(from, QualifiedName(newTable.Namespace, newTable.Name))
+		}
+		if oldTable == nil || matched[oldTable] {
+			changes = append(changes, MigrationChange{
+				Kind:   MigrationAddTable,
+				Table:  QualifiedName(newTable.Namespace, newTable.Name),
+				Detail: fmt.Sprintf("Create table %s.", QualifiedName(newTable.Namespace, newTable.Name)),
+			})
+			continue
+		}
+		matched[oldTable] = true
+		changes = append(changes, diffTableForMigration(from, to, oldTable, newTable)...)
+	}
+	for i := range from.Tables {
+		oldTable := &from.Tables[i]
+		if !matched[oldTable] {
+			name := QualifiedName(oldTable.Namespace, oldTable.Name)
+			changes = append(changes, MigrationChange{Kind: MigrationDropTable, Table: name, Detail: fmt.Sprintf("Drop table %s.", name)})
+		}
+	}
+
+	return append(changes, diffEnumsForMigration(from, to)...)
+}
+
+func diffTableForMigration(fromSchema, toSchema *models.Schema, oldTable, newTable *models.Table) []MigrationChange {
+	var changes []MigrationChange
+	oldName := QualifiedName(oldTable.Namespace, oldTable.Name)
+	tableName := QualifiedName(newTable.Namespace, newTable.Name)
+	if oldName != tableName {
+		changes = append(changes, MigrationChange{Kind: MigrationRenameTable, Table: tableName, Detail: fmt.Sprintf("Rename table %s to %s.", oldName, tableName)})
+	}
+
+	// Fields are paired by ID, then by name.
+	pairs := make(map[*models.Field]*models.Field)
+	oldFields := make(map[*models.Field]*models.Field)
+	for i := range newTable.Fields {
+		field := &newTable.Fields[i]
+		old := findField(oldTable, field.ID)
+		if old == nil || oldFields[old] != nil {
+			old = findField(oldTable, field.Name)
+		}
+		if old != nil && oldFields[old] == nil {
+			pairs[field] = old
+			oldFields[old] = field
+		}
+	}
+
+	inOldKey := fieldSet(primaryKeyFields(oldTable))
+	inNewKey := fieldSet(primaryKeyFields(newTable))
+	for i := range newTable.Fields {
+		field := &newTable.Fields[i]
+		old, ok := pairs[field]
+		if !ok {
+			kind := MigrationAddColumn
+			if (field.IsNotNull || inNewKey[field]) && strings.TrimSpace(field.DefaultValue) == "" && !isSerialField(field) {
+				kind = MigrationAddNotNullColumn
+			}
+			changes = append(changes, MigrationChange{
+				Kind: kind, Table: tableName, Field: field.Name, to: field,
+				Detail: fmt.Sprintf("Add column %s %s%s.", field.Name, renderColumnType("", field), migrationColumnSuffix(field, inNewKey[field])),
+			})
+			continue
+		}
+
+		if old.Name != field.Name {
+			changes = append(changes, MigrationChange{
+				Kind: MigrationRenameColumn, Table: tableName, Field: field.Name, from: old, to: field,
+				Detail: fmt.Sprintf("Rename column %s to %s.", old.Name, field.Name),
+			})
+		}
+		if kind := compareColumnTypes(fromSchema, oldTable, old, toSchema, newTable, field); kind != "" {
+			changes = append(changes, MigrationChange{
+				Kind: kind, Table: tableName, Field: field.Name, from: old, to: field,
+				Detail: fmt.Sprintf("Change type of %s from %s to %s.", field.Name, renderColumnType("", old), renderColumnType("", field)),
+			})
+		}
+		wasNotNull := old.IsNotNull || inOldKey[old]
+		isNotNull := field.IsNotNull || inNewKey[field]
+		switch {
+		case isNotNull && !wasNotNull:
+			changes = append(changes, MigrationChange{Kind: MigrationSetNotNull, Table: tableName, Field: field.Name, from: old, to: field, Detail: fmt.Sprintf("Make %s NOT NULL.", field.Name)})
+		case wasNotNull && !isNotNull:
+			changes = append(changes, MigrationChange{Kind: MigrationDropNotNull, Table: tableName, Field: field.Name, from: old, to: field, Detail: fmt.Sprintf("Allow NULL in %s.", field.Name)})
+		}
+		if strings.TrimSpace(old.DefaultValue) != strings.TrimSpace(field.DefaultValue) {
+			changes = append(changes, MigrationChange{
+				Kind: MigrationChangeDefault, Table: tableName, Field: field.Name, from: old, to: field,
+				Detail: fmt.Sprintf("Change default of %s from %s to %s.", field.Name, migrationDefault(old.DefaultValue), migrationDefault(field.DefaultValue)),
+			})
+		}
+	}
+	for i := range oldTable.Fields {
+		old := &oldTable.Fields[i]
+		if oldFields[old] == nil {
+			changes = append(changes, MigrationChange{Kind: MigrationDropColumn, Table: tableName, Field: old.Name, from: old, Detail: fmt.Sprintf("Drop column %s.", old.Name)})
+		}
+	}
+
+	// Keys, indexes and constraints are compared by the fields they cover,
+	// identified through the old table so renamed fields still match.
+	existing := func(fields []*models.Field) bool {
+		for _, field := range fields {
+			if _, ok := pairs[field]; !ok {
+				return false
+			}
+		}
+		return len(fields) > 0
+	}
+	oldSignature := func(fields []*models.Field) string {
+		ids := make([]string, len(fields))
+		for i, field := range fields {
+			ids[i] = field.ID
+		}
+		return strings.Join(ids, ",")
+	}
+	newSignature := func(fields []*models.Field) string {
+		ids := make([]string, len(fields))
+		for i, field := range fields {
+			if old, ok := pairs[field]; ok {
+				ids[i] = old.ID
+			} else {
+				ids[i] = "new:" + field.ID
+			}
+		}
+		return strings.Join(ids, ",")
+	}
+
+	oldKey, newKey := primaryKeyFields(oldTable), primaryKeyFields(newTable)
+	if oldSignature(oldKey) != newSignature(newKey) {
+		changes = append(changes, MigrationChange{
+			Kind: MigrationChangePrimaryKey, Table: tableName,
+			Detail: fmt.Sprintf("Change primary key from (%s) to (%s).", strings.Join(fieldNames(oldKey), ", "), strings.Join(fieldNames(newKey), ", ")),
+		})
+	}
+
+	oldUnique := migrationUniqueKeys(oldTable, oldSignature)
+	newUnique := migrationUniqueKeys(newTable, newSignature)
+	for _, key := range newUnique.order {
+		if _, ok := oldUnique.fields[key]; !ok && existing(newUnique.fields[key]) {
+			changes = append(changes, MigrationChange{
+				Kind: MigrationAddUnique, Table: tableName,
+				Detail: fmt.Sprintf("Add unique key %s on (%s).", newUnique.names[key], strings.Join(fieldNames(newUnique.fields[key]), ", ")),
+			})
+		}
+	}
+	for _, key := range oldUnique.order {
+		if _, ok := newUnique.fields[key]; !ok {
+			changes = append(changes, MigrationChange{
+				Kind: MigrationDropIndex, Table: tableName,
+				Detail: fmt.Sprintf("Drop unique key %s on (%s).", oldUnique.names[key], strings.Join(fieldNames(oldUnique.fields[key]), ", ")),
+			})
+		}
+	}
+
+	oldIndexes := migrationIndexes(oldTable, oldSignature)
+	newIndexes := migrationIndexes(newTable, newSignature)
+	for _, key := range newIndexes.order {
+		if _, ok := oldIndexes.fields[key]; !ok && existing(newIndexes.fields[key]) {
+			changes = append(changes, MigrationChange{Kind: MigrationAddIndex, Table: tableName, Detail: fmt.Sprintf("Add index %s.", newIndexes.names[key])})
+		}
+	}
+	for _, key := range oldIndexes.order {
+		if _, ok := newIndexes.fields[key]; !ok {
+			changes = append(changes, MigrationChange{Kind: MigrationDropIndex, Table: tableName, Detail: fmt.Sprintf("Drop index %s.", oldIndexes.names[key])})
+		}
+	}
+
+	oldRelations := migrationForeignKeys(fromSchema, oldTable, oldSignature)
+	newRelations := migrationForeignKeys(toSchema, newTable, newSignature)
+	for _, key := range newRelations.order {
+		if _, ok := oldRelations.fields[key]; !ok && existing(newRelations.fields[key]) {
+			changes = append(changes, MigrationChange{Kind: MigrationAddForeignKey, Table: tableName, Detail: fmt.Sprintf("Add foreign key %s.", newRelations.names[key])})
+		}
+	}
+	for _, key := range oldRelations.order {
+		if _, ok := newRelations.fields[key]; !ok {
+			changes = append(changes, MigrationChange{Kind: MigrationDropForeignKey, Table: tableName, Detail: fmt.Sprintf("Drop foreign key %s.", oldRelations.names[key])})
+		}
+	}
+
+	oldChecks := make(map[string]bool)
+	for i := range oldTable.Constraints {
+		if constraintKind(&oldTable.Constraints[i]) == ConstraintCheck {
+			oldChecks[strings.Join(strings.Fields(oldTable.Constraints[i].CheckCondition), " ")] = true
+		}
+	}
+	for i := range newTable.Constraints {
+		constraint := &newTable.Constraints[i]
+		condition := strings.Join(strings.Fields(constraint.CheckCondition), " ")
+		if constraintKind(constraint) == ConstraintCheck && !oldChecks[condition] {
+			changes = append(changes, MigrationChange{Kind: MigrationAddCheck, Table: tableName, Detail: fmt.Sprintf("Add check %s.", condition)})
+		}
+	}
+	return changes
+}
+
+func fieldSet(fields []*models.Field) map[*models.Field]bool {
+	set := make(map[*models.Field]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return set
+}
+
+func migrationColumnSuffix(field *models.Field, inKey bool) string {
+	var parts []string
+	if field.IsNotNull || inKey {
+		parts = append(parts, "NOT NULL")
+	}
+	if value := strings.TrimSpace(field.DefaultValue); value != "" {
+		parts = append(parts, "DEFAULT "+value)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " ")
+}
+
+func migrationDefault(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "none"
+	}
+	return strings.TrimSpace(value)
+}
+
+// migrationKeySet holds keys of a table by signature, in table order.
+type migrationKeySet struct {
+	order  []string
+	fields map[string][]*models.Field
+	names  map[string]string
+}
+
+func (k *migrationKeySet) add(signature, name string, fields []*models.Field) {
+	if _, ok := k.fields[signature]; ok {
+		return
+	}
+	k.order = append(k.order, signature)
+	k.fields[signature] = fields
+	k.names[signature] = name
+}
+
+func newMigrationKeySet() *migrationKeySet {
+	return &migrationKeySet{fields: make(map[string][]*models.Field), names: make(map[string]string)}
+}
+
+// migrationUniqueKeys collects unique fields, constraints and full unique
+// indexes, ignoring column order.
+func migrationUniqueKeys(table *models.Table, signature func([]*models.Field) string) *migrationKeySet {
+	keys := newMigrationKeySet()
+	add := func(name string, fields []*models.Field) {
+		if len(fields) == 0 {
+			return
+		}
+		sorted := append([]*models.Field(nil), fields...)
+		sort.Slice(sorted, func(i, j int) bool { return signature(sorted[i:i+1]) < signature(sorted[j:j+1]) })
+		keys.add(signature(sorted), name, fields)
+	}
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		if field.IsUnique && !field.IsPrimaryKey {
+			add(fmt.Sprintf("%s_%s_key", table.Name, field.Name), []*models.Field{field})
+		}
+	}
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		if constraintKind(constraint) == ConstraintUnique {
+			add(constraint.Name, constraintFields(table, constraint))
+		}
+	}
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+		if index.IsUnique && index.Where == "" {
+			if fields, ok := migrationIndexFields(table, index); ok {
+				add(indexDisplayName(table, index), fields)
+			}
+		}
+	}
+	return keys
+}
+
+// migrationIndexes collects non-unique and partial indexes by method,
+// columns and predicate.
+func migrationIndexes(table *models.Table, signature func([]*models.Field) string) *migrationKeySet {
+	keys := newMigrationKeySet()
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+		if index.IsUnique && index.Where == "" {
+			continue
+		}
+		fields, ok := migrationIndexFields(table, index)
+		name := indexDisplayName(table, index)
+		key := name
+		if ok {
+			key = fmt.Sprintf("%s|%s|%t|%s", indexMethod(index), signature(fields), index.IsUnique, strings.Join(strings.Fields(index.Where), " "))
+		}
+		keys.add(key, name, fields)
+	}
+	return keys
+}
+
+// migrationIndexFields resolves index columns; expression indexes report
+// false and are compared by name.
+func migrationIndexFields(table *models.Table, index *models.Index) ([]*models.Field, bool) {
+	var fields []*models.Field
+	for _, column := range indexColumns(index) {
+		field := findField(table, column.FieldID)
+		if field == nil {
+			return nil, false
+		}
+		fields = append(fields, field)
+	}
+	return fields, len(fields) > 0
+}
+
+func migrationForeignKeys(schema *models.Schema, table *models.Table, signature func([]*models.Field) string) *migrationKeySet {
+	keys := newMigrationKeySet()
+	for _, relation := range collectRelations(schema) {
+		if relation.From != table {
+			continue
+		}
+		targetFields := make([]string, len(relation.ToFields))
+		for i, field := range relation.ToFields {
+			targetFields[i] = field.Name
+		}
+		target := QualifiedName(relation.To.Namespace, relation.To.Name)
+		keys.add(
+			signature(relation.FromFields)+"->"+strings.ToLower(target)+"("+strings.ToLower(strings.Join(targetFields, ","))+")",
+			fmt.Sprintf("%s (%s) -> %s (%s)", relation.Name, strings.Join(fieldNames(relation.FromFields), ", "), target, strings.Join(targetFields, ", ")),
+			relation.FromFields,
+		)
+	}
+	return keys
+}
+
+var migrationIntegerSizes = map[string]int{
+	"TINYINT": 1, "SMALLINT": 2, "INT2": 2, "SMALLSERIAL": 2, "MEDIUMINT": 3,
+	"INTEGER": 4, "INT": 4, "INT4": 4, "SERIAL": 4, "BIGINT": 8, "INT8": 8, "BIGSERIAL": 8,
+}
+
+var migrationIntegerDigits = map[int]int{1: 3, 2: 5, 3: 8, 4: 10, 8: 19}
+
+var migrationFloatSizes = map[string]int{
+	"REAL": 4, "FLOAT4": 4, "FLOAT": 8, "FLOAT8": 8, "DOUBLE": 8, "DOUBLE PRECISION": 8,
+}
+
+// Text types by the number of characters they hold; 0 means unbounded.
+var migrationTextLimits = map[string]int{
+	"VARCHAR": -1, "CHARACTER VARYING": -1, "NVARCHAR": -1, "CHAR": -1, "CHARACTER": -1, "NCHAR": -1,
+	"TINYTEXT": 255, "TEXT": 0, "MEDIUMTEXT": 0, "LONGTEXT": 0, "CLOB": 0,
+}
+
+var migrationTimeRanks = map[string]int{
+	"DATE": 1, "DATETIME": 2, "TIMESTAMP": 2, "TIMESTAMP WITHOUT TIME ZONE": 2,
+}
+
+// compareColumnTypes classifies a type change as widening, narrowing or
+// another change, or returns "" when the type is unchanged.
+func compareColumnTypes(fromSchema *models.Schema, oldTable *models.Table, old *models.Field, toSchema *models.Schema, newTable *models.Table, field *models.Field) string {
+	oldEnum := resolveEnum(fromSchema, oldTable.Namespace, old.Type)
+	newEnum := resolveEnum(toSchema, newTable.Namespace, field.Type)
+	if oldEnum != nil || newEnum != nil {
+		if oldEnum != nil && newEnum != nil && (oldEnum.ID == newEnum.ID || strings.EqualFold(QualifiedName(oldEnum.Namespace, oldEnum.Name), QualifiedName(newEnum.Namespace, newEnum.Name))) {
+			return ""
+		}
+		return MigrationChangeType
+	}
+
+	if renderColumnType("", old) == renderColumnType("", field) {
+		return ""
+	}
+	oldBase, _ := splitColumnType(old)
+	newBase, _ := splitColumnType(field)
+	compare := func(before, after int) string {
+		switch {
+		case after > before:
+			return MigrationWidenType
+		case after < before:
+			return MigrationNarrowType
+		}
+		return ""
+	}
+
+	if oldSize, ok := migrationIntegerSizes[oldBase]; ok {
+		if newSize, ok := migrationIntegerSizes[newBase]; ok {
+			return compare(oldSize, newSize)
+		}
+		if decimalTypes[newBase] {
+			if field.Precision == 0 || field.Precision-field.Scale >= migrationIntegerDigits[oldSize] {
+				return MigrationWidenType
+			}
+			return MigrationNarrowType
+		}
+		if newSize, ok := migrationFloatSizes[newBase]; ok {
+			// Doubles hold integers exactly up to 2^53.
+			if newSize == 8 && oldSize <= 4 {
+				return MigrationWidenType
+			}
+			return MigrationNarrowType
+		}
+	}
+	if decimalTypes[oldBase] {
+		if decimalTypes[newBase] {
+			switch {
+			case old.Precision == 0 && field.Precision == 0:
+				return ""
+			case old.Precision == 0:
+				return MigrationNarrowType
+			case field.Precision == 0:
+				return MigrationWidenType
+			}
+			if field.Scale < old.Scale || field.Precision-field.Scale < old.Precision-old.Scale {
+				return MigrationNarrowType
+			}
+			return MigrationWidenType
+		}
+		if _, ok := migrationIntegerSizes[newBase]; ok {
+			return MigrationNarrowType
+		}
+	}
+	if oldSize, ok := migrationFloatSizes[oldBase]; ok {
+		if newSize, ok := migrationFloatSizes[newBase]; ok {
+			if kind := compare(oldSize, newSize); kind != "" {
+				return kind
+			}
+			return ""
+		}
+		if _, ok := migrationIntegerSizes[newBase]; ok {
+			return MigrationNarrowType
+		}
+	}
+	if oldLimit, ok := migrationTextLimits[oldBase]; ok {
+		if newLimit, ok := migrationTextLimits[newBase]; ok {
+			if oldLimit < 0 {
+				oldLimit = old.Length
+			}
+			if newLimit < 0 {
+				newLimit = field.Length
+			}
+			switch {
+			case oldLimit == newLimit:
+				return ""
+			case newLimit == 0:
+				return MigrationWidenType
+			case oldLimit == 0:
+				return MigrationNarrowType
+			}
+			return compare(oldLimit, newLimit)
+		}
+	}
+	if oldRank, ok := migrationTimeRanks[oldBase]; ok {
+		if newRank, ok := migrationTimeRanks[newBase]; ok {
+			if kind := compare(oldRank, newRank); kind != "" {
+				return kind
+			}
+			return ""
+		}
+	}
+	return MigrationChangeType
+}
+
+func diffEnumsForMigration(from, to *models.Schema) []MigrationChange {
+	var changes []MigrationChange
+	for i := range to.Enums {
+		enum := &to.Enums[i]
+		var old *models.Enum
+		for j := range from.Enums {
+			if from.Enums[j].ID == enum.ID && enum.ID != "" {
+				old = &from.Enums[j]
+			}
+		}
+		if old == nil {
+			old = resolveEnum(from, enum.Namespace, enum.Name)
+		}
+		if old == nil {
+			continue
+		}
+
+		name := QualifiedName(enum.Namespace, enum.Name)
+		oldValues := make(map[string]bool, len(old.Values))
+		for _, value := range old.Values {
+			oldValues[value] = true
+		}
+		newValues := make(map[string]bool, len(enum.Values))
+		var added []string
+		for _, value := range enum.Values {
+			newValues[value] = true
+			if !oldValues[value] {
+				added = append(added, value)
+			}
+		}
+		var removed []string
+		for _, value := range old.Values {
+			if !newValues[value] {
+				removed = append(removed, value)
+			}
+		}
+		if len(added) > 0 {
+			changes = append(changes, MigrationChange{Kind: MigrationAddEnumValue, Enum: name, Detail: fmt.Sprintf("Add %s to enum %s.", sqlStringList(added), name)})
+		}
+		if len(removed) > 0 {
+			changes = append(changes, MigrationChange{Kind: MigrationDropEnumValue, Enum: name, Detail: fmt.Sprintf("Remove %s from enum %s.", sqlStringList(removed), name)})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+var mongoBSONTypes = map[string][]interface{}{
+	"TINYINT": {"int"}, "SMALLINT": {"int"}, "INT2": {"int"}, "SMALLSERIAL": {"int"}, "YEAR": {"int"},
+	"INTEGER": {"int"}, "INT": {"int"}, "INT4": {"int"}, "MEDIUMINT": {"int"}, "SERIAL": {"int"},
+	"BIGINT": {"long", "int"}, "INT8": {"long", "int"}, "BIGSERIAL": {"long", "int"}, "OID": {"long", "int"},
+	"REAL": {"double"}, "FLOAT4": {"double"}, "FLOAT": {"double"}, "FLOAT8": {"double"}, "DOUBLE": {"double"},
+	"DOUBLE PRECISION": {"double"}, "DECIMAL": {"decimal"}, "NUMERIC": {"decimal"}, "MONEY": {"decimal"},
+	"BOOLEAN": {"bool"}, "BOOL": {"bool"},
+	"DATE": {"date"}, "TIMESTAMP": {"date"}, "TIMESTAMPTZ": {"date"}, "TIMESTAMP WITH TIME ZONE": {"date"},
+	"TIMESTAMP WITHOUT TIME ZONE": {"date"}, "DATETIME": {"date"},
+	"BYTEA": {"binData"}, "BLOB": {"binData"}, "BINARY": {"binData"}, "VARBINARY": {"binData"},
+	"TINYBLOB": {"binData"}, "MEDIUMBLOB": {"binData"}, "LONGBLOB": {"binData"},
+}
+
+// mongoColumnTypes returns the BSON types a column value may have, or nil
+// when any value is accepted.
+func mongoColumnTypes(schema *models.Schema, table *models.Table, field *models.Field) []interface{} {
+	if resolveEnum(schema, table.Namespace, field.Type) != nil {
+		return []interface{}{"string"}
+	}
+	base, _ := splitColumnType(field)
+	if strings.HasSuffix(base, "[]") {
+		return []interface{}{"array"}
+	}
+	if base == "JSON" || base == "JSONB" {
+		return nil
+	}
+	if types, ok := mongoBSONTypes[base]; ok {
+		return types
+	}
+	return []interface{}{"string"}
+}
+
+// mongoColumnSchema describes a column in the $jsonSchema dialect, which
+// has bsonType instead of type and no $ref, format or default keywords.
+func mongoColumnSchema(schema *models.Schema, table *models.Table, field *models.Field, nullable bool) *orderedMap {
+	property := newOrderedMap()
+	if enum := resolveEnum(schema, table.Namespace, field.Type); enum != nil {
+		values := make([]interface{}, 0, len(enum.Values)+1)
+		for _, value := range enum.Values {
+			values = append(values, value)
+		}
+		if nullable {
+			property.Set("bsonType", []interface{}{"string", "null"})
+			values = append(values, nil)
+		} else {
+			property.Set("bsonType", "string")
+		}
+		property.Set("enum", values)
+		if field.Comment == "" && enum.Comment != "" {
+			property.Set("description", enum.Comment)
+		}
+	} else {
+		base, _ := splitColumnType(field)
+		array := strings.HasSuffix(base, "[]")
+		base = strings.TrimSuffix(base, "[]")
+
+		item := newOrderedMap()
+		if types, ok := mongoBSONTypes[base]; ok {
+			item.Set("bsonType", mongoTypeValue(types))
+		} else if base != "JSON" && base != "JSONB" {
+			item.Set("bsonType", "string")
+			if pattern, ok := jsonSchemaPatterns[base]; ok {
+				item.Set("pattern", pattern)
+			}
+			if field.Length > 0 && !array {
+				item.Set("maxLength", field.Length)
+			}
+		}
+
+		if array {
+			property.Set("bsonType", "array").Set("items", item)
+		} else {
+			property = item
+		}
+		if types, ok := property.values["bsonType"]; ok && nullable {
+			property.Set("bsonType", mongoTypeValue(append(mongoTypeList(types), "null")))
+		}
+	}
+	if field.Comment != "" {
+		property.Set("description", field.Comment)
+	}
+	return property
+}
+
+func mongoTypeList(value interface{}) []interface{} {
+	if types, ok := value.([]interface{}); ok {
+		return append([]interface{}{}, types...)
+	}
+	return []interface{}{value}
+}
+
+func mongoTypeValue(types []interface{}) interface{} {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return types
+}
+
+// mongoValidator returns the collection validator for a table. Not null
+// columns are required; _id and other extra fields stay allowed.
+func mongoValidator(schema *models.Schema, table *models.Table) *orderedMap {
+	inPrimaryKey := make(map[*models.Field]bool)
+	for _, field := range primaryKeyFields(table) {
+		inPrimaryKey[field] = true
+	}
+
+	properties := newOrderedMap()
+	var required []interface{}
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		notNull := field.IsNotNull || field.IsPrimaryKey || inPrimaryKey[field]
+		if notNull {
+			required = append(required, field.Name)
+		}
+		properties.Set(field.Name, mongoColumnSchema(schema, table, field, !notNull))
+	}
+
+	definition := newOrderedMap().Set("bsonType", "object").Set("title", QualifiedName(table.Namespace, table.Name))
+	if table.Description != "" {
+		definition.Set("description", table.Description)
+	}
+	if len(required) > 0 {
+		definition.Set("required", required)
+	}
+	definition.Set("properties", properties)
+	return newOrderedMap().Set("$jsonSchema", definition)
+}
+
+type mongoIndex struct {
+	keys    *orderedMap
+	options *orderedMap
+	note    string
+}
+
+// mongoIndexes translates the primary key, unique columns and constraints
+// and Table.Indexes into createIndex calls. Unique indexes over nullable
+// columns only cover documents where those columns hold a value, matching
+// SQL where NULLs never collide.
+func mongoIndexes(schema *models.Schema, table *models.Table) []mongoIndex {
+	primaryKey := primaryKeyFields(table)
+	inPrimaryKey := make(map[*models.Field]bool, len(primaryKey))
+	for _, field := range primaryKey {
+		inPrimaryKey[field] = true
+	}
+
+	var indexes []mongoIndex
+	seen := make(map[string]string)
+	add := func(name string, fields []*models.Field, directions []interface{}, unique bool, note string) {
+		keys := newOrderedMap()
+		var signature []string
+		for i, field := range fields {
+			keys.Set(field.Name, directions[i])
+			signature = append(signature, fmt.Sprintf("%s:%v", field.Name, directions[i]))
+		}
+		// MongoDB allows a single index per key pattern.
+		key := strings.Join(signature, ",")
+		if existing, ok := seen[key]; ok {
+			indexes = append(indexes, mongoIndex{note: fmt.Sprintf("Skipped %s: same keys as %s.", name, existing)})
+			return
+		}
+		seen[key] = name
+
+		options := newOrderedMap().Set("name", name)
+		if unique {
+			options.Set("unique", true)
+			filter := newOrderedMap()
+			for _, field := range fields {
+				if field.IsNotNull || field.IsPrimaryKey || inPrimaryKey[field] {
+					continue
+				}
+				if types := mongoColumnTypes(schema, table, field); types != nil {
+					filter.Set(field.Name, newOrderedMap().Set("$type", mongoTypeValue(types)))
+				} else {
+					filter.Set(field.Name, newOrderedMap().Set("$exists", true))
+				}
+			}
+			if len(filter.keys) > 0 {
+				options.Set("partialFilterExpression", filter)
+			}
+		}
+		indexes = append(indexes, mongoIndex{keys: keys, options: options, note: note})
+	}
+	ascending := func(count int) []interface{} {
+		directions := make([]interface{}, count)
+		for i := range directions {
+			directions[i] = 1
+		}
+		return directions
+	}
+
+	if len(primaryKey) > 0 {
+		add(table.Name+"_pkey", primaryKey, ascending(len(primaryKey)), true, "")
+	}
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		if field.IsUnique && !field.IsPrimaryKey {
+			add(fmt.Sprintf("%s_%s_key", table.Name, field.Name), []*models.Field{field}, ascending(1), true, "")
+		}
+	}
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		if constraintKind(constraint) != ConstraintUnique {
+			continue
+		}
+		fields := constraintFields(table, constraint)
+		if len(fields) == 0 {
+			continue
+		}
+		name := constraint.Name
+		if name == "" {
+			name = fmt.Sprintf("%s_%s_key", table.Name, strings.Join(fieldNames(fields), "_"))
+		}
+		add(name, fields, ascending(len(fields)), true, "")
+	}
+
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+		name := indexDisplayName(table, index)
+		method := indexMethod(index)
+		var fields []*models.Field
+		var directions []interface{}
+		expression := false
+		for _, column := range indexColumns(index) {
+			field := findField(table, column.FieldID)
+			if field == nil {
+				expression = true
+				break
+			}
+			var direction interface{} = 1
+			switch {
+			case method == IndexMethodFullText:
+				direction = "text"
+			case method == IndexMethodHash && len(fields) == 0:
+				direction = "hashed"
+			case strings.EqualFold(column.Order, "DESC"):
+				direction = -1
+			}
+			fields = append(fields, field)
+			directions = append(directions, direction)
+		}
+
+		predicate := strings.Join(strings.Fields(index.Where), " ")
+		switch {
+		case expression || len(fields) == 0:
+			indexes = append(indexes, mongoIndex{note: fmt.Sprintf("Skipped %s: expression keys have no MongoDB equivalent.", name)})
+		case index.Where != "" && index.IsUnique:
+			indexes = append(indexes, mongoIndex{note: fmt.Sprintf("Skipped %s: unique only WHERE %s, which has no MongoDB equivalent.", name, predicate)})
+		case index.Where != "":
+			add(name, fields, directions, false, fmt.Sprintf("%s covers every document; its SQL predicate WHERE %s was not translated.", name, predicate))
+		default:
+			add(name, fields, directions, index.IsUnique, "")
+		}
+	}
+	return indexes
+}
+
+func mongoScriptJSON(value interface{}) (string, error) {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode document: %v", err)
+	}
+	return string(content), nil
+}
+
+// GenerateMongoValidators renders a $jsonSchema validator per table as
+// validators/<table>.json, plus a mongosh script that creates or updates
+// each collection with its validator and indexes. Collections are named
+// after the qualified table name.
+func GenerateMongoValidators(schema *models.Schema) ([]byte, error) {
+	fileNames := tableFileNames(schema)
+	var files []archiveFile
+	var script strings.Builder
+	fmt.Fprintf(&script, "// Generated by Schema Builder from %s\n", tsString(schema.Name))
+	script.WriteString("// Run with: mongosh <connection-string> create_collections.js\n\n")
+	script.WriteString(`function ensureCollection(name, validator) {
+  const options = { validator: validator, validationLevel: "strict", validationAction: "error" };
+  if (db.getCollectionNames().includes(name)) {
+    db.runCommand(Object.assign({ collMod: name }, options));
+  } else {
+    db.createCollection(name, options);
+  }
+}
+`)
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		validator := mongoValidator(schema, table)
+		content, err := marshalDocument(validator)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archiveFile{name: "validators/" + fileNames[table] + ".json", content: content})
+
+		collection := tsString(QualifiedName(table.Namespace, table.Name))
+		encoded, err := mongoScriptJSON(validator)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&script, "\nensureCollection(%s, %s);\n", collection, encoded)
+		for _, index := range mongoIndexes(schema, table) {
+			if index.note != "" {
+				fmt.Fprintf(&script, "// %s\n", index.note)
+			}
+			if index.keys == nil {
+				continue
+			}
+			keys, err := json.Marshal(index.keys)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode document: %v", err)
+			}
+			options, err := json.Marshal(index.options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode document: %v", err)
+			}
+			fmt.Fprintf(&script, "db.getCollection(%s).createIndex(%s, %s);\n", collection, keys, options)
+		}
+	}
+
+	files = append(files, archiveFile{name: "create_collections.js", content: []byte(script.String())})
+	return zipArchive(files)
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"sort"
+
+	"schema-builder-backend/internal/models"
+)
+
+type NamespaceSummary struct {
+	Name       string `json:"name"`
+	Comment    string `json:"comment,omitempty"`
+	TableCount int    `json:"table_count"`
+	EnumCount  int    `json:"enum_count"`
+	ViewCount  int    `json:"view_count"`
+}
+
+func QualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// The default namespace is reported with an empty name.
+func SchemaNamespaces(schema *models.Schema) []NamespaceSummary {
+	summaries := make(map[string]*NamespaceSummary)
+	get := func(name string) *NamespaceSummary {
+		if summary, ok := summaries[name]; ok {
+			return summary
+		}
+		summary := &NamespaceSummary{Name: name}
+		summaries[name] = summary
+		return summary
+	}
+
+	for _, namespace := range schema.Namespaces {
+		get(namespace.Name).Comment = namespace.Comment
+	}
+	for _, table := range schema.Tables {
+		get(table.Namespace).TableCount++
+	}
+	for _, enum := range schema.Enums {
+		get(enum.Namespace).EnumCount++
+	}
+	for _, view := range schema.Views {
+		get(view.Namespace).ViewCount++
+	}
+
+	result := make([]NamespaceSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// References into other namespaces are kept so callers can still follow them.
+func FilterSchemaByNamespace(schema *models.Schema, namespace string) *models.Schema {
+	filtered := *schema
+	filtered.Namespaces = nil
+	filtered.Tables = []models.Table{}
+	filtered.Enums = nil
+	filtered.Views = nil
+
+	for _, ns := range schema.Namespaces {
+		if ns.Name == namespace {
+			filtered.Namespaces = append(filtered.Namespaces, ns)
+		}
+	}
+	for _, table := range schema.Tables {
+		if table.Namespace == namespace {
+			filtered.Tables = append(filtered.Tables, table)
+		}
+	}
+	for _, enum := range schema.Enums {
+		if enum.Namespace == namespace {
+			filtered.Enums = append(filtered.Enums, enum)
+		}
+	}
+	for _, view := range schema.Views {
+		if view.Namespace == namespace {
+			filtered.Views = append(filtered.Views, view)
+		}
+	}
+
+	return &filtered
+}
+
+// Every stored reference carries the namespace of its target table. It is
+// taken from the table on each save, so references follow a table that
+// moves to another namespace.
+func normalizeReferences(tables []models.Table) {
+	namespaces := make(map[string]string, len(tables))
+	for _, table := range tables {
+		namespaces[table.ID] = table.Namespace
+	}
+
+	for i := range tables {
+		for j := range tables[i].Fields {
+			ref := tables[i].Fields[j].References
+			if ref == nil {
+				continue
+			}
+			if namespace, ok := namespaces[ref.TableID]; ok {
+				ref.Namespace = namespace
+			}
+		}
+		// Constraints naming their target by ID are kept in step too; those
+		// naming it by name rely on the namespace to find it.
+		for j := range tables[i].Constraints {
+			constraint := &tables[i].Constraints[j]
+			if namespace, ok := namespaces[constraint.ReferenceTable]; ok && constraint.ReferenceTable != "" {
+				constraint.ReferenceNamespace = namespace
+			}
+		}
+	}
+}
@@ -0,0 +1,969 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	NormalizationListColumn           = "list_column"
+	NormalizationRepeatingGroup       = "repeating_group"
+	NormalizationPartialDependency    = "partial_dependency"
+	NormalizationTransitiveDependency = "transitive_dependency"
+	NormalizationDuplicatedColumns    = "duplicated_columns"
+)
+
+type NormalizationReport struct {
+	SchemaID    primitive.ObjectID        `json:"schema_id"`
+	Version     int                       `json:"version"`
+	Suggestions []NormalizationSuggestion `json:"suggestions"`
+}
+
+// NormalizationSuggestion describes a likely normal form violation. Fields
+// are qualified as table.field. Split is nil when the fix cannot be derived
+// from the design alone.
+type NormalizationSuggestion struct {
+	ID      string              `json:"id"`
+	Form    string              `json:"form"`
+	Kind    string              `json:"kind"`
+	Tables  []string            `json:"tables"`
+	Fields  []string            `json:"fields"`
+	Message string              `json:"message"`
+	Split   *NormalizationSplit `json:"split,omitempty"`
+}
+
+// NormalizationSplit adds Tables to the schema and then applies Changes to
+// existing tables, matched by ID.
+type NormalizationSplit struct {
+	Tables  []models.Table             `json:"tables,omitempty"`
+	Changes []NormalizationTableChange `json:"changes,omitempty"`
+}
+
+// References maps the ID of an existing field to the reference it gains.
+type NormalizationTableChange struct {
+	TableID      string                      `json:"table_id"`
+	Table        string                      `json:"table"`
+	RemoveFields []string                    `json:"remove_fields,omitempty"`
+	AddFields    []models.Field              `json:"add_fields,omitempty"`
+	References   map[string]models.Reference `json:"references,omitempty"`
+}
+
+var (
+	listCommentPattern   = regexp.MustCompile(`(?i)\b(comma|semicolon|pipe|space)[- ]?(separated|delimited)\b|\bcsv\b|\blist of\b`)
+	repeatingNamePattern = regexp.MustCompile(`^([a-z][a-z_]*?[a-z])_?([0-9]+)(?:_([a-z][a-z0-9_]*))?$`)
+)
+
+var listColumnNames = map[string]bool{
+	"tags": true, "categories": true, "keywords": true, "labels": true, "emails": true, "phones": true,
+	"phone_numbers": true, "skills": true, "roles": true, "permissions": true, "recipients": true,
+}
+
+var normalizationTextTypes = map[string]bool{
+	"VARCHAR": true, "CHAR": true, "CHARACTER": true, "CHARACTER VARYING": true, "NVARCHAR": true,
+	"NCHAR": true, "TEXT": true, "TINYTEXT": true, "MEDIUMTEXT": true, "LONGTEXT": true, "CLOB": true,
+}
+
+// Columns sharing these prefixes across tables are bookkeeping, not a
+// hidden entity.
+var normalizationGenericPrefixes = map[string]bool{
+	"is": true, "has": true, "can": true, "created": true, "updated": true, "deleted": true, "modified": true,
+	"last": true, "first": true, "total": true, "max": true, "min": true, "num": true, "date": true,
+	"time": true, "sort": true, "display": true, "external": true, "meta": true,
+}
+
+var normalizationKeySuffixes = []string{"_id", "_fk", "_key", "_code", "_no", "_number", "id"}
+
+// AnalyzeNormalization inspects a schema the user can read. The report
+// carries the schema version so suggestions can be applied against it.
+func (s *SchemaService) AnalyzeNormalization(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*NormalizationReport, error) {
+	schema, err := s.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := AnalyzeNormalization(schema)
+	s.log.Infof("Normalization analysis of schema %s found %d suggestions", id.Hex(), len(report.Suggestions))
+	return report, nil
+}
+
+// ApplyNormalization re-runs the analysis, applies the selected suggestions
+// and saves the result through UpdateSchema, which bumps the version.
+func (s *SchemaService) ApplyNormalization(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.ApplyNormalizationRequest) (*models.Schema, error) {
+	schema, err := s.schemaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("schema not found: %v", err)
+	}
+
+	if schema.UserID != userID {
+		return nil, fmt.Errorf("access denied: you can only update your own schemas")
+	}
+
+	if req.Version != 0 && req.Version != schema.Version {
+		return nil, fmt.Errorf("schema version mismatch: suggestions were made for version %d but the schema is at version %d", req.Version, schema.Version)
+	}
+
+	report := AnalyzeNormalization(schema)
+	byID := make(map[string]*NormalizationSuggestion, len(report.Suggestions))
+	for i := range report.Suggestions {
+		byID[report.Suggestions[i].ID] = &report.Suggestions[i]
+	}
+	selected := make([]*NormalizationSuggestion, 0, len(req.Suggestions))
+	for _, suggestionID := range req.Suggestions {
+		suggestion, ok := byID[suggestionID]
+		if !ok {
+			return nil, fmt.Errorf("unknown normalization suggestion: %s", suggestionID)
+		}
+		if suggestion.Split == nil {
+			return nil, fmt.Errorf("invalid normalization suggestion: %s has no proposed split", suggestionID)
+		}
+		selected = append(selected, suggestion)
+	}
+
+	tables, err := applyNormalization(schema.Tables, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.UpdateSchema(ctx, id, userID, &models.UpdateSchemaRequest{Tables: tables})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infof("Applied %d normalization suggestions to schema %s", len(selected), id.Hex())
+	return updated, nil
+}
+
+// AnalyzeNormalization looks for list columns and repeating groups (1NF),
+// attributes that depend on part of a composite key (2NF) and attributes
+// copied from referenced or implied tables (3NF). Only plain columns are
+// considered: keys, indexed, constrained and referenced columns are taken
+// to be deliberate. Each column is part of at most one suggestion, so any
+// subset of the suggestions can be applied together.
+func AnalyzeNormalization(schema *models.Schema) *NormalizationReport {
+	a := newNormalizationAnalyzer(schema)
+	for i := range schema.Tables {
+		a.listColumns(&schema.Tables[i])
+	}
+	for i := range schema.Tables {
+		a.repeatingGroups(&schema.Tables[i])
+	}
+	for i := range a.relations {
+		a.mirroredAttributes(&a.relations[i])
+	}
+	for i := range schema.Tables {
+		a.partialKeyDependencies(&schema.Tables[i])
+	}
+	a.duplicatedColumns()
+
+	suggestions := a.suggestions
+	if suggestions == nil {
+		suggestions = []NormalizationSuggestion{}
+	}
+	return &NormalizationReport{SchemaID: schema.ID, Version: schema.Version, Suggestions: suggestions}
+}
+
+type normalizationAnalyzer struct {
+	schema      *models.Schema
+	relations   []schemaRelation
+	keyed       map[*models.Field]bool
+	claimed     map[*models.Field]bool
+	tableNames  map[string]bool
+	fieldNames  map[*models.Table]map[string]bool
+	placed      map[*models.Table]int
+	suggestions []NormalizationSuggestion
+}
+
+func newNormalizationAnalyzer(schema *models.Schema) *normalizationAnalyzer {
+	a := &normalizationAnalyzer{
+		schema:     schema,
+		relations:  collectRelations(schema),
+		keyed:      make(map[*models.Field]bool),
+		claimed:    make(map[*models.Field]bool),
+		tableNames: make(map[string]bool),
+		fieldNames: make(map[*models.Table]map[string]bool),
+		placed:     make(map[*models.Table]int),
+	}
+
+	for _, relation := range a.relations {
+		for _, field := range relation.FromFields {
+			a.keyed[field] = true
+		}
+		for _, field := range relation.ToFields {
+			a.keyed[field] = true
+		}
+	}
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		a.tableNames[strings.ToLower(QualifiedName(table.Namespace, table.Name))] = true
+		names := make(map[string]bool, len(table.Fields))
+		for j := range table.Fields {
+			names[strings.ToLower(table.Fields[j].Name)] = true
+		}
+		a.fieldNames[table] = names
+
+		mark := func(ref string) {
+			if field := findField(table, ref); field != nil {
+				a.keyed[field] = true
+			}
+		}
+		for _, field := range primaryKeyFields(table) {
+			a.keyed[field] = true
+		}
+		for j := range table.Indexes {
+			for _, column := range indexColumns(&table.Indexes[j]) {
+				mark(column.FieldID)
+			}
+			for _, ref := range table.Indexes[j].Include {
+				mark(ref)
+			}
+		}
+		for j := range table.Constraints {
+			for _, field := range constraintFields(table, &table.Constraints[j]) {
+				a.keyed[field] = true
+			}
+		}
+		if table.Options != nil && table.Options.Partitioning != nil {
+			for _, ref := range table.Options.Partitioning.Fields {
+				mark(ref)
+			}
+		}
+	}
+	return a
+}
+
+// plain reports whether a column is an unclaimed ordinary attribute.
+func (a *normalizationAnalyzer) plain(field *models.Field) bool {
+	return !a.keyed[field] && !a.claimed[field] && !field.IsPrimaryKey && !field.IsUnique &&
+		!field.IsForeignKey && field.References == nil
+}
+
+func (a *normalizationAnalyzer) add(suggestion NormalizationSuggestion, fields []*models.Field) {
+	for _, field := range fields {
+		a.claimed[field] = true
+	}
+	a.suggestions = append(a.suggestions, suggestion)
+}
+
+func (a *normalizationAnalyzer) tableName(namespace, base string) string {
+	name := base
+	for k := 2; a.tableNames[strings.ToLower(QualifiedName(namespace, name))]; k++ {
+		name = fmt.Sprintf("%s_%d", base, k)
+	}
+	a.tableNames[strings.ToLower(QualifiedName(namespace, name))] = true
+	return name
+}
+
+func (a *normalizationAnalyzer) fieldName(table *models.Table, base string) string {
+	name := base
+	for k := 2; a.fieldNames[table][strings.ToLower(name)]; k++ {
+		name = fmt.Sprintf("%s_%d", base, k)
+	}
+	a.fieldNames[table][strings.ToLower(name)] = true
+	return name
+}
+
+// newTable starts a table in the source table's namespace, placed to its
+// right on the canvas.
+func (a *normalizationAnalyzer) newTable(source *models.Table, base, description string) models.Table {
+	offset := a.placed[source]
+	a.placed[source]++
+	return models.Table{
+		ID:          newElementID("table"),
+		Namespace:   source.Namespace,
+		Name:        a.tableName(source.Namespace, base),
+		Description: description,
+		Position:    models.Position{X: source.Position.X + 360, Y: source.Position.Y + float64(offset)*240},
+	}
+}
+
+// referencingColumns returns columns of a new table that reference the key
+// of target, named after prefix, plus the foreign key constraint needed when
+// the key has more than one column.
+func referencingColumns(target *models.Table, key []*models.Field, prefix string) ([]models.Field, []models.Constraint) {
+	fields := make([]models.Field, 0, len(key))
+	for _, keyField := range key {
+		name := keyField.Name
+		if strings.EqualFold(name, "id") {
+			name = prefix + "_id"
+		} else if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)+"_") {
+			name = prefix + "_" + name
+		}
+		fields = append(fields, referenceColumn(keyField, name))
+	}
+	if len(key) == 1 {
+		fields[0].References = &models.Reference{Namespace: target.Namespace, TableID: target.ID, FieldID: key[0].ID}
+		return fields, nil
+	}
+
+	constraint := models.Constraint{
+		Name:               fmt.Sprintf("fk_%s_%s", prefix, target.Name),
+		Type:               ConstraintForeignKey,
+		ReferenceNamespace: target.Namespace,
+		ReferenceTable:     target.Name,
+	}
+	for i, keyField := range key {
+		constraint.Fields = append(constraint.Fields, fields[i].ID)
+		constraint.ReferenceFields = append(constraint.ReferenceFields, keyField.ID)
+	}
+	return fields, []models.Constraint{constraint}
+}
+
+// referenceColumn copies the type of a key column for a column pointing at
+// it; serial keys are referenced with their plain integer type.
+func referenceColumn(key *models.Field, name string) models.Field {
+	field := models.Field{
+		ID:           newElementID("f"),
+		Name:         name,
+		Type:         key.Type,
+		Length:       key.Length,
+		Precision:    key.Precision,
+		Scale:        key.Scale,
+		IsNotNull:    true,
+		IsForeignKey: true,
+	}
+	switch base, _ := splitColumnType(key); base {
+	case "SERIAL":
+		field.Type = "INTEGER"
+	case "BIGSERIAL":
+		field.Type = "BIGINT"
+	case "SMALLSERIAL":
+		field.Type = "SMALLINT"
+	}
+	return field
+}
+
+// movedColumn copies a column into another table under a new name.
+func movedColumn(source *models.Field, name string) models.Field {
+	return models.Field{
+		ID:           newElementID("f"),
+		Name:         name,
+		Type:         source.Type,
+		Length:       source.Length,
+		Precision:    source.Precision,
+		Scale:        source.Scale,
+		IsNotNull:    source.IsNotNull,
+		DefaultValue: source.DefaultValue,
+		Comment:      source.Comment,
+		Charset:      source.Charset,
+		Collation:    source.Collation,
+	}
+}
+
+// childTable creates a table holding one row per value of the parent, keyed
+// on the parent's key plus the given columns.
+func (a *normalizationAnalyzer) childTable(parent *models.Table, key []*models.Field, base, description string, columns []models.Field, keyColumns int) models.Table {
+	prefix := singularize(strings.ToLower(parent.Name))
+	table := a.newTable(parent, prefix+"_"+base, description)
+	parentColumns, constraints := referencingColumns(parent, key, prefix)
+	table.Fields = append(parentColumns, columns...)
+	table.Constraints = constraints
+	for i := range table.Fields[:len(parentColumns)+keyColumns] {
+		table.Fields[i].IsPrimaryKey = true
+		table.Fields[i].IsNotNull = true
+		table.PrimaryKey = append(table.PrimaryKey, table.Fields[i].ID)
+	}
+	return table
+}
+
+func (a *normalizationAnalyzer) qualifiedField(table *models.Table, field *models.Field) string {
+	return QualifiedName(table.Namespace, table.Name) + "." + field.Name
+}
+
+func suggestionID(kind string, parts ...string) string {
+	return kind + ":" + strings.Join(parts, ":")
+}
+
+func fieldIDs(fields []*models.Field) string {
+	ids := make([]string, len(fields))
+	for i, field := range fields {
+		ids[i] = field.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func (a *normalizationAnalyzer) listColumns(table *models.Table) {
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		name := strings.ToLower(field.Name)
+		base, _ := splitColumnType(field)
+		if !a.plain(field) || !normalizationTextTypes[base] || resolveEnum(a.schema, table.Namespace, field.Type) != nil {
+			continue
+		}
+		element := ""
+		switch {
+		case strings.HasSuffix(name, "_ids") && len(name) > 4:
+			element = strings.TrimSuffix(name, "_ids")
+		case strings.HasSuffix(name, "_list") && len(name) > 5:
+			element = strings.TrimSuffix(name, "_list")
+		case strings.HasSuffix(name, "_csv") && len(name) > 4:
+			element = strings.TrimSuffix(name, "_csv")
+		case listColumnNames[name] || listCommentPattern.MatchString(field.Comment):
+			element = name
+		default:
+			continue
+		}
+		element = singularize(element)
+
+		suggestion := NormalizationSuggestion{
+			ID:     suggestionID(NormalizationListColumn, table.ID, field.ID),
+			Form:   "1NF",
+			Kind:   NormalizationListColumn,
+			Tables: []string{QualifiedName(table.Namespace, table.Name)},
+			Fields: []string{a.qualifiedField(table, field)},
+		}
+		key := primaryKeyFields(table)
+		if len(key) == 0 {
+			suggestion.Message = fmt.Sprintf("%s appears to hold a list of values in one column. Give %s a primary key so the values can move to a table with one row per value.",
+				suggestion.Fields[0], suggestion.Tables[0])
+			a.add(suggestion, []*models.Field{field})
+			continue
+		}
+
+		// A list of IDs of a known table becomes a link table.
+		var value models.Field
+		target := findTable(a.schema, QualifiedName(table.Namespace, pluralize(element)))
+		if target == nil {
+			target = findTable(a.schema, pluralize(element))
+		}
+		targetKey := []*models.Field(nil)
+		if target != nil {
+			targetKey = primaryKeyFields(target)
+		}
+		if strings.HasSuffix(name, "_ids") && len(targetKey) == 1 && target != table {
+			value = referenceColumn(targetKey[0], element+"_id")
+			value.References = &models.Reference{Namespace: target.Namespace, TableID: target.ID, FieldID: targetKey[0].ID}
+		} else {
+			if strings.HasSuffix(name, "_ids") {
+				element += "_id"
+			}
+			value = movedColumn(field, element)
+			value.DefaultValue = ""
+			value.Comment = ""
+			// Unbounded text cannot be part of a key everywhere.
+			if !lengthTypes[base] {
+				value.Type, value.Length = "VARCHAR", 255
+			}
+		}
+
+		child := a.childTable(table, key, pluralize(element), fmt.Sprintf("One row per value of %s", suggestion.Fields[0]), []models.Field{value}, 1)
+		suggestion.Message = fmt.Sprintf("%s appears to hold a list of values in one column, which breaks first normal form. Store one value per row in %s and drop the column.",
+			suggestion.Fields[0], QualifiedName(child.Namespace, child.Name))
+		suggestion.Split = &NormalizationSplit{
+			Tables:  []models.Table{child},
+			Changes: []NormalizationTableChange{{TableID: table.ID, Table: suggestion.Tables[0], RemoveFields: []string{field.ID}}},
+		}
+		a.add(suggestion, []*models.Field{field})
+	}
+}
+
+func (a *normalizationAnalyzer) repeatingGroups(table *models.Table) {
+	type member struct {
+		number    int
+		attribute string
+		field     *models.Field
+	}
+	groups := make(map[string][]member)
+	var stems []string
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		if !a.plain(field) {
+			continue
+		}
+		match := repeatingNamePattern.FindStringSubmatch(strings.ToLower(field.Name))
+		if match == nil {
+			continue
+		}
+		var number int
+		fmt.Sscanf(match[2], "%d", &number)
+		if _, ok := groups[match[1]]; !ok {
+			stems = append(stems, match[1])
+		}
+		groups[match[1]] = append(groups[match[1]], member{number: number, attribute: match[3], field: field})
+	}
+
+	for _, stem := range stems {
+		members := groups[stem]
+		numbers := make(map[int]bool)
+		for _, m := range members {
+			numbers[m.number] = true
+		}
+		if len(numbers) < 2 {
+			continue
+		}
+		sort.SliceStable(members, func(i, j int) bool { return members[i].number < members[j].number })
+
+		fields := make([]*models.Field, len(members))
+		qualified := make([]string, len(members))
+		for i, m := range members {
+			fields[i] = m.field
+			qualified[i] = a.qualifiedField(table, m.field)
+		}
+		tableName := QualifiedName(table.Namespace, table.Name)
+		suggestion := NormalizationSuggestion{
+			ID:     suggestionID(NormalizationRepeatingGroup, table.ID, fieldIDs(fields)),
+			Form:   "1NF",
+			Kind:   NormalizationRepeatingGroup,
+			Tables: []string{tableName},
+			Fields: qualified,
+		}
+		key := primaryKeyFields(table)
+		if len(key) == 0 {
+			suggestion.Message = fmt.Sprintf("%s repeat the same attribute %d times. Give %s a primary key so the group can move to a table with one row per entry.",
+				strings.Join(qualified, ", "), len(numbers), tableName)
+			a.add(suggestion, fields)
+			continue
+		}
+
+		// One column per attribute, typed after its first occurrence.
+		var attributes []string
+		templates := make(map[string]*models.Field)
+		for _, m := range members {
+			if _, ok := templates[m.attribute]; !ok {
+				attributes = append(attributes, m.attribute)
+				templates[m.attribute] = m.field
+			}
+		}
+		taken := map[string]bool{"position": true}
+		columns := []models.Field{{ID: newElementID("f"), Name: "position", Type: "INTEGER", IsNotNull: true}}
+		for _, attribute := range attributes {
+			base := attribute
+			if base == "" {
+				base = stem
+			}
+			name := base
+			for k := 2; taken[name]; k++ {
+				name = fmt.Sprintf("%s_%d", base, k)
+			}
+			taken[name] = true
+			column := movedColumn(templates[attribute], name)
+			column.DefaultValue = ""
+			columns = append(columns, column)
+		}
+
+		child := a.childTable(table, key, pluralize(stem), fmt.Sprintf("One row per numbered %s of %s", stem, tableName), columns, 1)
+		suggestion.Message = fmt.Sprintf("%s are a repeating group, which breaks first normal form and caps the number of entries at %d. Store one entry per row in %s, numbered by position.",
+			strings.Join(qualified, ", "), len(numbers), QualifiedName(child.Namespace, child.Name))
+		removed := make([]string, len(fields))
+		for i, field := range fields {
+			removed[i] = field.ID
+		}
+		suggestion.Split = &NormalizationSplit{
+			Tables:  []models.Table{child},
+			Changes: []NormalizationTableChange{{TableID: table.ID, Table: tableName, RemoveFields: removed}},
+		}
+		a.add(suggestion, fields)
+	}
+}
+
+// keyPrefix strips a key suffix such as _id from a column name.
+func keyPrefix(name string) string {
+	name = strings.ToLower(name)
+	for _, suffix := range normalizationKeySuffixes {
+		if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+			return strings.TrimSuffix(strings.TrimSuffix(name, suffix), "_")
+		}
+	}
+	return name
+}
+
+func sameColumnType(a, b *models.Field) bool {
+	baseA, _ := splitColumnType(a)
+	baseB, _ := splitColumnType(b)
+	return baseA == baseB
+}
+
+// mirroredAttributes finds columns named <entity>_<attribute> next to a
+// foreign key whose target has that attribute, like orders.customer_email
+// beside customer_id. They depend on the key of another table, so they
+// belong there only.
+func (a *normalizationAnalyzer) mirroredAttributes(relation *schemaRelation) {
+	from, to := relation.From, relation.To
+	if from == to {
+		return
+	}
+	prefixes := []string{singularize(strings.ToLower(to.Name))}
+	if len(relation.FromFields) == 1 {
+		if prefix := keyPrefix(relation.FromFields[0].Name); prefix != prefixes[0] && prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	targetKey := make(map[*models.Field]bool)
+	for _, field := range relation.ToFields {
+		targetKey[field] = true
+	}
+	for _, field := range primaryKeyFields(to) {
+		targetKey[field] = true
+	}
+
+	var fields []*models.Field
+	var qualified, mirrored []string
+	for i := range from.Fields {
+		field := &from.Fields[i]
+		if !a.plain(field) {
+			continue
+		}
+		name := strings.ToLower(field.Name)
+		for j := range to.Fields {
+			attribute := &to.Fields[j]
+			if targetKey[attribute] || !sameColumnType(field, attribute) {
+				continue
+			}
+			matched := false
+			for _, prefix := range prefixes {
+				if name == prefix+"_"+strings.ToLower(attribute.Name) {
+					matched = true
+				}
+			}
+			if matched {
+				fields = append(fields, field)
+				qualified = append(qualified, a.qualifiedField(from, field))
+				mirrored = append(mirrored, a.qualifiedField(to, attribute))
+				break
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	form, kind, reason := "3NF", NormalizationTransitiveDependency, "a transitive dependency that breaks third normal form"
+	key := primaryKeyFields(from)
+	if len(key) > len(relation.FromFields) {
+		inKey := 0
+		for _, field := range relation.FromFields {
+			for _, keyField := range key {
+				if field == keyField {
+					inKey++
+				}
+			}
+		}
+		if inKey == len(relation.FromFields) {
+			form, kind, reason = "2NF", NormalizationPartialDependency, "only part of the composite primary key, which breaks second normal form"
+		}
+	}
+
+	removed := make([]string, len(fields))
+	for i, field := range fields {
+		removed[i] = field.ID
+	}
+	fromName := QualifiedName(from.Namespace, from.Name)
+	a.add(NormalizationSuggestion{
+		ID:     suggestionID(kind, from.ID, fieldIDs(fields)),
+		Form:   form,
+		Kind:   kind,
+		Tables: []string{fromName, QualifiedName(to.Namespace, to.Name)},
+		Fields: qualified,
+		Message: fmt.Sprintf("%s: copied from %s through %s, %s. Drop the copies and join on the foreign key instead, so the values cannot drift apart.",
+			strings.Join(qualified, ", "), strings.Join(mirrored, ", "), strings.Join(fieldNames(relation.FromFields), ", "), reason),
+		Split: &NormalizationSplit{
+			Changes: []NormalizationTableChange{{TableID: from.ID, Table: fromName, RemoveFields: removed}},
+		},
+	}, fields)
+}
+
+// partialKeyDependencies handles composite key columns that reference
+// nothing: columns sharing their prefix, like course_title beside
+// course_code, move to a new table keyed on that column alone.
+func (a *normalizationAnalyzer) partialKeyDependencies(table *models.Table) {
+	key := primaryKeyFields(table)
+	if len(key) < 2 {
+		return
+	}
+	referencing := make(map[*models.Field]bool)
+	for _, relation := range a.relations {
+		if relation.From == table {
+			for _, field := range relation.FromFields {
+				referencing[field] = true
+			}
+		}
+	}
+
+	tableName := QualifiedName(table.Namespace, table.Name)
+	for _, keyField := range key {
+		if referencing[keyField] || keyField.References != nil {
+			continue
+		}
+		prefix := keyPrefix(keyField.Name)
+		var fields []*models.Field
+		var qualified []string
+		for i := range table.Fields {
+			field := &table.Fields[i]
+			name := strings.ToLower(field.Name)
+			if a.plain(field) && strings.HasPrefix(name, prefix+"_") && len(name) > len(prefix)+1 {
+				fields = append(fields, field)
+				qualified = append(qualified, a.qualifiedField(table, field))
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		suggestion := NormalizationSuggestion{
+			ID:     suggestionID(NormalizationPartialDependency, table.ID, fieldIDs(fields)),
+			Form:   "2NF",
+			Kind:   NormalizationPartialDependency,
+			Tables: []string{tableName},
+			Fields: qualified,
+		}
+		if findTable(a.schema, QualifiedName(table.Namespace, pluralize(prefix))) != nil {
+			suggestion.Message = fmt.Sprintf("%s: determined by %s alone rather than the whole primary key. %s already exists; move these columns there and make %s reference it.",
+				strings.Join(qualified, ", "), keyField.Name, pluralize(prefix), keyField.Name)
+			a.add(suggestion, fields)
+			continue
+		}
+
+		entity := a.newTable(table, pluralize(prefix), "")
+		entityKey := movedColumn(keyField, keyField.Name)
+		entityKey.IsPrimaryKey, entityKey.IsNotNull, entityKey.DefaultValue = true, true, ""
+		entity.Fields = []models.Field{entityKey}
+		entity.PrimaryKey = []string{entityKey.ID}
+		removed := make([]string, len(fields))
+		for i, field := range fields {
+			entity.Fields = append(entity.Fields, movedColumn(field, strings.TrimPrefix(strings.ToLower(field.Name), prefix+"_")))
+			removed[i] = field.ID
+		}
+
+		entityName := QualifiedName(entity.Namespace, entity.Name)
+		suggestion.Message = fmt.Sprintf("%s: determined by %s alone rather than the whole primary key, which breaks second normal form. Move these columns to %s keyed on %s and make %s.%s reference it.",
+			strings.Join(qualified, ", "), keyField.Name, entityName, keyField.Name, tableName, keyField.Name)
+		suggestion.Split = &NormalizationSplit{
+			Tables: []models.Table{entity},
+			Changes: []NormalizationTableChange{{
+				TableID:      table.ID,
+				Table:        tableName,
+				RemoveFields: removed,
+				References:   map[string]models.Reference{keyField.ID: {Namespace: entity.Namespace, TableID: entity.ID, FieldID: entityKey.ID}},
+			}},
+		}
+		a.add(suggestion, fields)
+	}
+}
+
+// duplicatedColumns finds groups of <entity>_<attribute> columns repeated
+// in several tables, like customer_name and customer_email on both orders
+// and invoices. They describe an entity of their own, which is created
+// unless a table of that name exists already.
+func (a *normalizationAnalyzer) duplicatedColumns() {
+	type occurrence struct {
+		table      *models.Table
+		attributes map[string]*models.Field
+	}
+	byPrefix := make(map[string][]*occurrence)
+	var prefixes []string
+	for i := range a.schema.Tables {
+		table := &a.schema.Tables[i]
+		own := singularize(strings.ToLower(table.Name))
+		seen := make(map[string]*occurrence)
+		for j := range table.Fields {
+			field := &table.Fields[j]
+			name := strings.ToLower(field.Name)
+			cut := strings.Index(name, "_")
+			if !a.plain(field) || cut < 2 || cut == len(name)-1 {
+				continue
+			}
+			prefix, attribute := name[:cut], name[cut+1:]
+			if normalizationGenericPrefixes[prefix] || prefix == own {
+				continue
+			}
+			entry, ok := seen[prefix]
+			if !ok {
+				entry = &occurrence{table: table, attributes: make(map[string]*models.Field)}
+				seen[prefix] = entry
+				if len(byPrefix[prefix]) == 0 {
+					prefixes = append(prefixes, prefix)
+				}
+				byPrefix[prefix] = append(byPrefix[prefix], entry)
+			}
+			entry.attributes[attribute] = field
+		}
+	}
+
+	for _, prefix := range prefixes {
+		occurrences := byPrefix[prefix]
+		if len(occurrences) < 2 {
+			continue
+		}
+
+		// An attribute is shared when another table has it with the same type.
+		var shared []string
+		templates := make(map[string]*models.Field)
+		for _, entry := range occurrences {
+			for attribute, field := range entry.attributes {
+				if _, ok := templates[attribute]; ok {
+					continue
+				}
+				for _, other := range occurrences {
+					if otherField, ok := other.attributes[attribute]; ok && other != entry && sameColumnType(field, otherField) {
+						templates[attribute] = field
+						shared = append(shared, attribute)
+						break
+					}
+				}
+			}
+		}
+		sort.Strings(shared)
+
+		var participants []*occurrence
+		for _, entry := range occurrences {
+			count := 0
+			for _, attribute := range shared {
+				if field, ok := entry.attributes[attribute]; ok && sameColumnType(field, templates[attribute]) {
+					count++
+				}
+			}
+			if count >= 2 {
+				participants = append(participants, entry)
+			}
+		}
+		if len(participants) < 2 {
+			continue
+		}
+
+		var fields []*models.Field
+		var qualified, tableIDs, tableNames []string
+		for _, entry := range participants {
+			tableIDs = append(tableIDs, entry.table.ID)
+			tableNames = append(tableNames, QualifiedName(entry.table.Namespace, entry.table.Name))
+			for _, attribute := range shared {
+				if field, ok := entry.attributes[attribute]; ok && sameColumnType(field, templates[attribute]) {
+					fields = append(fields, field)
+					qualified = append(qualified, a.qualifiedField(entry.table, field))
+				}
+			}
+		}
+		suggestion := NormalizationSuggestion{
+			ID:     suggestionID(NormalizationDuplicatedColumns, prefix, strings.Join(tableIDs, ",")),
+			Form:   "3NF",
+			Kind:   NormalizationDuplicatedColumns,
+			Tables: tableNames,
+			Fields: qualified,
+		}
+
+		source := participants[0].table
+		entity := findTable(a.schema, QualifiedName(source.Namespace, pluralize(prefix)))
+		if entity == nil {
+			entity = findTable(a.schema, pluralize(prefix))
+		}
+		split := &NormalizationSplit{}
+		var entityKey []*models.Field
+		if entity != nil {
+			entityKey = primaryKeyFields(entity)
+			if len(entityKey) != 1 {
+				suggestion.Message = fmt.Sprintf("%s describe the same %s in several tables. Reference %s from each of them instead, once it has a single-column primary key.",
+					strings.Join(qualified, ", "), prefix, QualifiedName(entity.Namespace, entity.Name))
+				a.add(suggestion, fields)
+				continue
+			}
+			change := NormalizationTableChange{TableID: entity.ID, Table: QualifiedName(entity.Namespace, entity.Name)}
+			for _, attribute := range shared {
+				if findField(entity, attribute) == nil {
+					change.AddFields = append(change.AddFields, movedColumn(templates[attribute], a.fieldName(entity, attribute)))
+				}
+			}
+			if len(change.AddFields) > 0 {
+				split.Changes = append(split.Changes, change)
+			}
+		} else {
+			created := a.newTable(source, pluralize(prefix), "")
+			key := models.Field{ID: newElementID("f"), Name: "id", Type: "SERIAL", IsPrimaryKey: true, IsNotNull: true}
+			if sourceKey := primaryKeyFields(source); len(sourceKey) == 1 {
+				key.Type, key.Length, key.DefaultValue = sourceKey[0].Type, sourceKey[0].Length, sourceKey[0].DefaultValue
+			}
+			created.Fields = []models.Field{key}
+			created.PrimaryKey = []string{key.ID}
+			for _, attribute := range shared {
+				created.Fields = append(created.Fields, movedColumn(templates[attribute], attribute))
+			}
+			split.Tables = []models.Table{created}
+			entity = &split.Tables[0]
+			entityKey = []*models.Field{&entity.Fields[0]}
+		}
+
+		for _, entry := range participants {
+			change := NormalizationTableChange{TableID: entry.table.ID, Table: QualifiedName(entry.table.Namespace, entry.table.Name)}
+			for _, attribute := range shared {
+				if field, ok := entry.attributes[attribute]; ok && sameColumnType(field, templates[attribute]) {
+					change.RemoveFields = append(change.RemoveFields, field.ID)
+				}
+			}
+			column := referenceColumn(entityKey[0], a.fieldName(entry.table, prefix+"_id"))
+			column.IsNotNull = false
+			column.References = &models.Reference{Namespace: entity.Namespace, TableID: entity.ID, FieldID: entityKey[0].ID}
+			change.AddFields = []models.Field{column}
+			split.Changes = append(split.Changes, change)
+		}
+
+		suggestion.Message = fmt.Sprintf("%s describe the same %s in several tables, so the same facts are stored more than once, which breaks third normal form. Keep them once in %s and reference it from %s.",
+			strings.Join(qualified, ", "), prefix, QualifiedName(entity.Namespace, entity.Name), strings.Join(tableNames, ", "))
+		suggestion.Split = split
+		a.add(suggestion, fields)
+	}
+}
+
+func pluralize(name string) string {
+	switch {
+	case name == "":
+		return name
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+// applyNormalization returns a copy of tables with the splits applied.
+func applyNormalization(tables []models.Table, suggestions []*NormalizationSuggestion) ([]models.Table, error) {
+	result := make([]models.Table, len(tables))
+	for i, table := range tables {
+		table.Fields = append([]models.Field(nil), table.Fields...)
+		table.PrimaryKey = append([]string(nil), table.PrimaryKey...)
+		result[i] = table
+	}
+
+	var created []models.Table
+	for _, suggestion := range suggestions {
+		created = append(created, suggestion.Split.Tables...)
+		for _, change := range suggestion.Split.Changes {
+			var table *models.Table
+			for i := range result {
+				if result[i].ID == change.TableID {
+					table = &result[i]
+				}
+			}
+			if table == nil {
+				return nil, fmt.Errorf("conflicting normalization suggestions: table %s no longer exists", change.Table)
+			}
+
+			remove := make(map[string]bool, len(change.RemoveFields))
+			for _, fieldID := range change.RemoveFields {
+				if findField(table, fieldID) == nil {
+					return nil, fmt.Errorf("conflicting normalization suggestions: a field of %s was already changed", change.Table)
+				}
+				remove[fieldID] = true
+			}
+			fields := make([]models.Field, 0, len(table.Fields)+len(change.AddFields))
+			for _, field := range table.Fields {
+				if remove[field.ID] {
+					continue
+				}
+				if reference, ok := change.References[field.ID]; ok {
+					reference := reference
+					field.References = &reference
+					field.IsForeignKey = true
+				}
+				fields = append(fields, field)
+			}
+			table.Fields = append(fields, change.AddFields...)
+		}
+	}
+	return append(result, created...), nil
+}
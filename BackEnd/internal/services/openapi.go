@@ -0,0 +1,501 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	openAPIDefaultLimit = 50
+	openAPIMaxLimit     = 1000
+)
+
+var openAPIPathUnsafePattern = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// orderedMap is a JSON/YAML object that keeps keys in insertion order, so
+// both encodings of the document read the same way.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (m *orderedMap) Set(key string, value interface{}) *orderedMap {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+	return m
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (m *orderedMap) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range m.keys {
+		var value yaml.Node
+		if err := value.Encode(m.values[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &value)
+	}
+	return node, nil
+}
+
+var openAPIIntegerFormats = map[string]string{
+	"TINYINT": "int32", "SMALLINT": "int32", "INT2": "int32", "SMALLSERIAL": "int32", "YEAR": "int32",
+	"INTEGER": "int32", "INT": "int32", "INT4": "int32", "MEDIUMINT": "int32", "SERIAL": "int32",
+	"BIGINT": "int64", "INT8": "int64", "BIGSERIAL": "int64", "OID": "int64",
+}
+
+var openAPINumberFormats = map[string]string{
+	"REAL": "float", "FLOAT4": "float", "FLOAT": "double", "FLOAT8": "double", "DOUBLE": "double",
+	"DOUBLE PRECISION": "double", "DECIMAL": "", "NUMERIC": "", "MONEY": "",
+}
+
+var openAPIStringFormats = map[string]string{
+	"UUID": "uuid", "DATE": "date", "TIME": "time", "TIMETZ": "time", "TIMESTAMP": "date-time",
+	"TIMESTAMPTZ": "date-time", "TIMESTAMP WITH TIME ZONE": "date-time", "TIMESTAMP WITHOUT TIME ZONE": "date-time",
+	"DATETIME": "date-time", "INET": "ipv4",
+}
+
+var openAPIBinaryTypes = map[string]bool{
+	"BYTEA": true, "BLOB": true, "BINARY": true, "VARBINARY": true, "TINYBLOB": true, "MEDIUMBLOB": true, "LONGBLOB": true,
+}
+
+type openAPIGenerator struct {
+	schema    *models.Schema
+	names     map[*models.Table]string
+	enums     map[*models.Enum]string
+	relations []schemaRelation
+}
+
+// GenerateOpenAPI renders an OpenAPI 3.1 document with component schemas per
+// table and list, get, create, update and delete operations, plus nested
+// list routes along foreign keys. format is "yaml" or "json".
+func GenerateOpenAPI(schema *models.Schema, format string) ([]byte, error) {
+	g := &openAPIGenerator{
+		schema:    schema,
+		names:     make(map[*models.Table]string),
+		enums:     make(map[*models.Enum]string),
+		relations: collectRelations(schema),
+	}
+	taken := map[string]bool{"Error": true}
+	name := func(namespace, base string) string {
+		candidate := base
+		if taken[candidate] && namespace != "" {
+			candidate = pascalCase(namespace) + base
+		}
+		for i := 2; taken[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", base, i)
+		}
+		for _, suffix := range []string{"", "Create", "Update", "Page"} {
+			taken[candidate+suffix] = true
+		}
+		return candidate
+	}
+	for i := range schema.Enums {
+		g.enums[&schema.Enums[i]] = name(schema.Enums[i].Namespace, pascalCase(schema.Enums[i].Name))
+	}
+	for i := range schema.Tables {
+		g.names[&schema.Tables[i]] = name(schema.Tables[i].Namespace, pascalCase(singularize(schema.Tables[i].Name)))
+	}
+
+	document := g.document()
+	switch strings.ToLower(format) {
+	case "", "yaml", "yml":
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return nil, fmt.Errorf("failed to encode OpenAPI document: %v", err)
+		}
+		return buf.Bytes(), nil
+	case "json":
+		content, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode OpenAPI document: %v", err)
+		}
+		return append(content, '\n'), nil
+	}
+	return nil, fmt.Errorf("unsupported export format: openapi-%s", format)
+}
+
+func openAPIRef(kind, name string) *orderedMap {
+	return newOrderedMap().Set("$ref", "#/components/"+kind+"/"+name)
+}
+
+func (g *openAPIGenerator) document() *orderedMap {
+	info := newOrderedMap().Set("title", g.schema.Name).Set("version", "1.0.0")
+	if g.schema.Description != "" {
+		info.Set("description", g.schema.Description)
+	}
+
+	var tags []interface{}
+	for i := range g.schema.Tables {
+		table := &g.schema.Tables[i]
+		tag := newOrderedMap().Set("name", g.names[table])
+		if table.Description != "" {
+			tag.Set("description", table.Description)
+		}
+		tags = append(tags, tag)
+	}
+
+	schemas := newOrderedMap()
+	for i := range g.schema.Enums {
+		enum := &g.schema.Enums[i]
+		values := make([]interface{}, len(enum.Values))
+		for k, value := range enum.Values {
+			values[k] = value
+		}
+		definition := newOrderedMap().Set("type", "string").Set("enum", values)
+		if enum.Comment != "" {
+			definition.Set("description", enum.Comment)
+		}
+		schemas.Set(g.enums[enum], definition)
+	}
+	for i := range g.schema.Tables {
+		table := &g.schema.Tables[i]
+		name := g.names[table]
+		schemas.Set(name, g.tableSchema(table, "read"))
+		schemas.Set(name+"Create", g.tableSchema(table, "create"))
+		schemas.Set(name+"Update", g.tableSchema(table, "update"))
+		schemas.Set(name+"Page", newOrderedMap().
+			Set("type", "object").
+			Set("required", []interface{}{"items", "limit", "offset"}).
+			Set("properties", newOrderedMap().
+				Set("items", newOrderedMap().Set("type", "array").Set("items", openAPIRef("schemas", name))).
+				Set("total", newOrderedMap().Set("type", "integer").Set("minimum", 0)).
+				Set("limit", newOrderedMap().Set("type", "integer")).
+				Set("offset", newOrderedMap().Set("type", "integer"))))
+	}
+	schemas.Set("Error", newOrderedMap().
+		Set("type", "object").
+		Set("required", []interface{}{"error", "message"}).
+		Set("properties", newOrderedMap().
+			Set("error", newOrderedMap().Set("type", "string")).
+			Set("message", newOrderedMap().Set("type", "string"))))
+
+	errorResponse := func(description string) *orderedMap {
+		return newOrderedMap().Set("description", description).Set("content",
+			newOrderedMap().Set("application/json", newOrderedMap().Set("schema", openAPIRef("schemas", "Error"))))
+	}
+	components := newOrderedMap().
+		Set("schemas", schemas).
+		Set("parameters", newOrderedMap().
+			Set("Limit", newOrderedMap().Set("name", "limit").Set("in", "query").Set("description", "Maximum number of items to return.").
+				Set("schema", newOrderedMap().Set("type", "integer").Set("minimum", 1).Set("maximum", openAPIMaxLimit).Set("default", openAPIDefaultLimit))).
+			Set("Offset", newOrderedMap().Set("name", "offset").Set("in", "query").Set("description", "Number of items to skip.").
+				Set("schema", newOrderedMap().Set("type", "integer").Set("minimum", 0).Set("default", 0)))).
+		Set("responses", newOrderedMap().
+			Set("BadRequest", errorResponse("Invalid request")).
+			Set("NotFound", errorResponse("Not found")))
+
+	document := newOrderedMap().Set("openapi", "3.1.0").Set("info", info)
+	if len(tags) > 0 {
+		document.Set("tags", tags)
+	}
+	return document.Set("paths", g.paths()).Set("components", components)
+}
+
+// columnSchema describes a column value; nullable columns accept null as
+// well, which OpenAPI 3.1 expresses through the JSON Schema type list.
+func (g *openAPIGenerator) columnSchema(table *models.Table, field *models.Field, nullable bool) *orderedMap {
+	if enum := resolveEnum(g.schema, table.Namespace, field.Type); enum != nil {
+		ref := openAPIRef("schemas", g.enums[enum])
+		if !nullable {
+			return ref
+		}
+		return newOrderedMap().Set("anyOf", []interface{}{ref, newOrderedMap().Set("type", "null")})
+	}
+
+	return jsonSchemaColumn(field, nullable)
+}
+
+// openAPIDefault turns a stored default into a JSON value when it is a plain
+// literal; function calls and SQL keywords have no JSON counterpart.
+func openAPIDefault(schema *orderedMap, value string) {
+	value = strings.TrimSpace(value)
+	upper := strings.ToUpper(value)
+	typ := valueSchemaType(schema)
+	switch {
+	case value == "" || upper == "NULL":
+	case upper == "TRUE" || upper == "FALSE":
+		if typ == "boolean" {
+			schema.Set("default", upper == "TRUE")
+		}
+	case numericLiteralPattern.MatchString(value):
+		if n, err := strconv.ParseFloat(value, 64); err == nil && (typ == "integer" || typ == "number") {
+			if n == float64(int64(n)) && !strings.Contains(value, ".") {
+				schema.Set("default", int64(n))
+			} else {
+				schema.Set("default", n)
+			}
+		}
+	case len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+		schema.Set("default", strings.ReplaceAll(value[1:len(value)-1], "''", "'"))
+	case sqlKeywordDefaults[upper] || functionLiteralPattern.MatchString(value):
+	default:
+		if typ == "string" || typ == "$ref" {
+			schema.Set("default", value)
+		}
+	}
+}
+
+// valueSchemaType returns the non-null type a value schema describes, "$ref"
+// for references, looking through the type lists and anyOf wrappers used for
+// nullable columns.
+func valueSchemaType(schema *orderedMap) string {
+	if schema.values["$ref"] != nil {
+		return "$ref"
+	}
+	if alternatives, ok := schema.values["anyOf"].([]interface{}); ok && len(alternatives) > 0 {
+		if first, ok := alternatives[0].(*orderedMap); ok {
+			return valueSchemaType(first)
+		}
+	}
+	switch typ := schema.values["type"].(type) {
+	case string:
+		return typ
+	case []interface{}:
+		if len(typ) > 0 {
+			name, _ := typ[0].(string)
+			return name
+		}
+	}
+	return ""
+}
+
+// tableSchema renders the read model, the create payload (generated
+// columns left out, required when not null without a default) or the update
+// payload (primary key left out, nothing required).
+func (g *openAPIGenerator) tableSchema(table *models.Table, mode string) *orderedMap {
+	inPrimaryKey := make(map[*models.Field]bool)
+	for _, field := range primaryKeyFields(table) {
+		inPrimaryKey[field] = true
+	}
+
+	properties := newOrderedMap()
+	var required []interface{}
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		generated := isSerialField(field)
+		notNull := field.IsNotNull || field.IsPrimaryKey || inPrimaryKey[field]
+		switch mode {
+		case "create":
+			if generated {
+				continue
+			}
+			if notNull && strings.TrimSpace(field.DefaultValue) == "" {
+				required = append(required, field.Name)
+			}
+		case "update":
+			if inPrimaryKey[field] || generated {
+				continue
+			}
+		default:
+			if notNull {
+				required = append(required, field.Name)
+			}
+		}
+
+		property := g.columnSchema(table, field, !notNull)
+		if mode != "update" {
+			openAPIDefault(property, field.DefaultValue)
+		}
+		if field.Comment != "" {
+			property.Set("description", field.Comment)
+		}
+		if mode == "read" && generated {
+			property.Set("readOnly", true)
+		}
+		properties.Set(field.Name, property)
+	}
+
+	schema := newOrderedMap().Set("type", "object")
+	if mode == "read" && table.Description != "" {
+		schema.Set("description", table.Description)
+	}
+	if len(required) > 0 {
+		schema.Set("required", required)
+	}
+	return schema.Set("properties", properties)
+}
+
+func openAPISegment(name string) string {
+	segment := strings.Trim(openAPIPathUnsafePattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if segment == "" {
+		segment = "items"
+	}
+	return segment
+}
+
+func (g *openAPIGenerator) collectionPath(table *models.Table) string {
+	if table.Namespace != "" {
+		return "/" + openAPISegment(table.Namespace) + "/" + openAPISegment(table.Name)
+	}
+	return "/" + openAPISegment(table.Name)
+}
+
+// keyParameters returns the path template suffix and the path parameters
+// addressing a single row, or nothing when the table has no primary key.
+func (g *openAPIGenerator) keyParameters(table *models.Table) (string, []interface{}) {
+	var template strings.Builder
+	var parameters []interface{}
+	for _, field := range primaryKeyFields(table) {
+		fmt.Fprintf(&template, "/{%s}", field.Name)
+		parameters = append(parameters, newOrderedMap().
+			Set("name", field.Name).
+			Set("in", "path").
+			Set("required", true).
+			Set("schema", g.columnSchema(table, field, false)))
+	}
+	return template.String(), parameters
+}
+
+func jsonContent(schema interface{}) *orderedMap {
+	return newOrderedMap().Set("application/json", newOrderedMap().Set("schema", schema))
+}
+
+func (g *openAPIGenerator) paths() *orderedMap {
+	paths := newOrderedMap()
+	pageParameters := []interface{}{openAPIRef("parameters", "Limit"), openAPIRef("parameters", "Offset")}
+	listResponse := func(name string) *orderedMap {
+		return newOrderedMap().Set("200", newOrderedMap().Set("description", "A page of "+name+" items").
+			Set("content", jsonContent(openAPIRef("schemas", name+"Page")))).
+			Set("400", openAPIRef("responses", "BadRequest"))
+	}
+
+	for i := range g.schema.Tables {
+		table := &g.schema.Tables[i]
+		name := g.names[table]
+		plural := pascalCase(table.Name)
+		if plural == name {
+			plural = "All" + name
+		}
+		collection := g.collectionPath(table)
+
+		paths.Set(collection, newOrderedMap().
+			Set("get", newOrderedMap().
+				Set("tags", []interface{}{name}).
+				Set("operationId", "list"+plural).
+				Set("parameters", pageParameters).
+				Set("responses", listResponse(name))).
+			Set("post", newOrderedMap().
+				Set("tags", []interface{}{name}).
+				Set("operationId", "create"+name).
+				Set("requestBody", newOrderedMap().Set("required", true).Set("content", jsonContent(openAPIRef("schemas", name+"Create")))).
+				Set("responses", newOrderedMap().
+					Set("201", newOrderedMap().Set("description", "Created").Set("content", jsonContent(openAPIRef("schemas", name)))).
+					Set("400", openAPIRef("responses", "BadRequest")))))
+
+		template, parameters := g.keyParameters(table)
+		if template == "" {
+			continue
+		}
+		paths.Set(collection+template, newOrderedMap().
+			Set("parameters", parameters).
+			Set("get", newOrderedMap().
+				Set("tags", []interface{}{name}).
+				Set("operationId", "get"+name).
+				Set("responses", newOrderedMap().
+					Set("200", newOrderedMap().Set("description", "The "+name).Set("content", jsonContent(openAPIRef("schemas", name)))).
+					Set("404", openAPIRef("responses", "NotFound")))).
+			Set("patch", newOrderedMap().
+				Set("tags", []interface{}{name}).
+				Set("operationId", "update"+name).
+				Set("requestBody", newOrderedMap().Set("required", true).Set("content", jsonContent(openAPIRef("schemas", name+"Update")))).
+				Set("responses", newOrderedMap().
+					Set("200", newOrderedMap().Set("description", "Updated").Set("content", jsonContent(openAPIRef("schemas", name)))).
+					Set("400", openAPIRef("responses", "BadRequest")).
+					Set("404", openAPIRef("responses", "NotFound")))).
+			Set("delete", newOrderedMap().
+				Set("tags", []interface{}{name}).
+				Set("operationId", "delete"+name).
+				Set("responses", newOrderedMap().
+					Set("204", newOrderedMap().Set("description", "Deleted")).
+					Set("404", openAPIRef("responses", "NotFound")))))
+	}
+
+	// Nested routes list the rows referencing a parent row when the foreign
+	// key covers the parent's primary key.
+	for i := range g.relations {
+		relation := &g.relations[i]
+		parentKey := primaryKeyFields(relation.To)
+		if len(parentKey) == 0 || len(parentKey) != len(relation.ToFields) {
+			continue
+		}
+		var parameters []interface{}
+		var template strings.Builder
+		matches := true
+		for _, key := range parentKey {
+			found := false
+			for _, field := range relation.ToFields {
+				found = found || field == key
+			}
+			if !found {
+				matches = false
+				break
+			}
+			fmt.Fprintf(&template, "/{%s}", key.Name)
+			parameters = append(parameters, newOrderedMap().
+				Set("name", key.Name).
+				Set("in", "path").
+				Set("required", true).
+				Set("schema", g.columnSchema(relation.To, key, false)))
+		}
+		if !matches {
+			continue
+		}
+
+		segment := openAPISegment(relation.From.Name)
+		path := g.collectionPath(relation.To) + template.String() + "/" + segment
+		if _, exists := paths.values[path]; exists {
+			path += "-by-" + openAPISegment(strings.Join(fieldNames(relation.FromFields), "-"))
+		}
+		name := g.names[relation.From]
+		tags := []interface{}{name}
+		if g.names[relation.To] != name {
+			tags = append(tags, g.names[relation.To])
+		}
+		paths.Set(path, newOrderedMap().
+			Set("parameters", append(parameters, pageParameters...)).
+			Set("get", newOrderedMap().
+				Set("tags", tags).
+				Set("operationId", "list"+g.names[relation.To]+pascalCase(relation.From.Name)+"By"+pascalCase(strings.Join(fieldNames(relation.FromFields), "_"))).
+				Set("responses", listResponse(name).Set("404", openAPIRef("responses", "NotFound")))))
+	}
+	return paths
+}
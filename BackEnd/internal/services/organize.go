@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/repository"
+	"schema-builder-backend/pkg/logger"
+)
+
+// OrganizeService manages the folders and tags users file their own
+// schemas under.
+type OrganizeService struct {
+	schemaRepo repository.SchemaRepository
+	folderRepo repository.FolderRepository
+	log        *logrus.Logger
+}
+
+// FolderTree lists a user's folders with schema counts. SchemaCount counts
+// the schemas directly in a folder, TotalCount those in its subfolders too.
+type FolderTree struct {
+	Folders []*FolderSummary `json:"folders"`
+	Unfiled int64            `json:"unfiled"`
+	Total   int64            `json:"total"`
+}
+
+type FolderSummary struct {
+	*models.Folder
+	Path        string `json:"path"`
+	SchemaCount int64  `json:"schema_count"`
+	TotalCount  int64  `json:"total_count"`
+}
+
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+func NewOrganizeService(schemaRepo repository.SchemaRepository, folderRepo repository.FolderRepository) *OrganizeService {
+	return &OrganizeService{
+		schemaRepo: schemaRepo,
+		folderRepo: folderRepo,
+		log:        logger.GetLogger(),
+	}
+}
+
+func (s *OrganizeService) ListFolders(ctx context.Context, userID primitive.ObjectID) (*FolderTree, error) {
+	folders, err := s.folderRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.schemaRepo.CountByFolder(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*FolderSummary, len(folders))
+	tree := &FolderTree{Folders: make([]*FolderSummary, 0, len(folders))}
+	for _, folder := range folders {
+		summary := &FolderSummary{Folder: folder, SchemaCount: counts[folder.ID]}
+		byID[folder.ID] = summary
+		tree.Folders = append(tree.Folders, summary)
+	}
+
+	for _, summary := range tree.Folders {
+		var path []string
+		for folder := summary; folder != nil; folder = parentSummary(byID, folder) {
+			path = append([]string{folder.Name}, path...)
+			folder.TotalCount += summary.SchemaCount
+		}
+		summary.Path = strings.Join(path, "/")
+	}
+	sort.Slice(tree.Folders, func(i, j int) bool {
+		return tree.Folders[i].Path < tree.Folders[j].Path
+	})
+
+	for folderID, count := range counts {
+		tree.Total += count
+		if _, ok := byID[folderID]; !ok {
+			// Schemas in no folder, or in a folder that no longer exists.
+			tree.Unfiled += count
+		}
+	}
+
+	return tree, nil
+}
+
+func parentSummary(byID map[primitive.ObjectID]*FolderSummary, folder *FolderSummary) *FolderSummary {
+	if folder.ParentID == nil {
+		return nil
+	}
+	return byID[*folder.ParentID]
+}
+
+func (s *OrganizeService) CreateFolder(ctx context.Context, userID primitive.ObjectID, req *models.CreateFolderRequest) (*models.Folder, error) {
+	folder := &models.Folder{UserID: userID, Name: strings.TrimSpace(req.Name)}
+	if folder.Name == "" {
+		return nil, fmt.Errorf("invalid folder: name cannot be empty")
+	}
+	if req.ParentID != "" {
+		parent, err := s.ownFolder(ctx, req.ParentID, userID)
+		if err != nil {
+			return nil, err
+		}
+		folder.ParentID = &parent.ID
+	}
+
+	if err := s.checkFolderName(ctx, userID, folder); err != nil {
+		return nil, err
+	}
+
+	if err := s.folderRepo.Create(ctx, folder); err != nil {
+		s.log.Errorf("Failed to create folder: %v", err)
+		return nil, err
+	}
+
+	return folder, nil
+}
+
+// UpdateFolder renames a folder or moves it under another folder. A folder
+// cannot be moved into itself or one of its subfolders.
+func (s *OrganizeService) UpdateFolder(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.UpdateFolderRequest) (*models.Folder, error) {
+	folder, err := s.ownFolder(ctx, id.Hex(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		folder.Name = strings.TrimSpace(req.Name)
+		if folder.Name == "" {
+			return nil, fmt.Errorf("invalid folder: name cannot be empty")
+		}
+	}
+	if req.ParentID != nil {
+		folder.ParentID = nil
+		if *req.ParentID != "" {
+			parent, err := s.ownFolder(ctx, *req.ParentID, userID)
+			if err != nil {
+				return nil, err
+			}
+			for ancestor := parent; ; {
+				if ancestor.ID == folder.ID {
+					return nil, fmt.Errorf("invalid folder: a folder cannot be moved into itself or its subfolders")
+				}
+				if ancestor.ParentID == nil {
+					break
+				}
+				if ancestor, err = s.folderRepo.GetByID(ctx, *ancestor.ParentID); err != nil {
+					break
+				}
+			}
+			folder.ParentID = &parent.ID
+		}
+	}
+
+	if err := s.checkFolderName(ctx, userID, folder); err != nil {
+		return nil, err
+	}
+
+	if err := s.folderRepo.Update(ctx, folder); err != nil {
+		s.log.Errorf("Failed to update folder %s: %v", id.Hex(), err)
+		return nil, err
+	}
+
+	return folder, nil
+}
+
+// DeleteFolder deletes a folder. Its schemas and subfolders move up to the
+// folder's parent; subfolders whose names are taken there get a numbered
+// suffix.
+func (s *OrganizeService) DeleteFolder(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) error {
+	folder, err := s.ownFolder(ctx, id.Hex(), userID)
+	if err != nil {
+		return err
+	}
+
+	folders, err := s.folderRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool)
+	for _, other := range folders {
+		if other.ID != folder.ID && sameFolder(other.ParentID, folder.ParentID) {
+			taken[strings.ToLower(other.Name)] = true
+		}
+	}
+	for _, child := range folders {
+		if child.ParentID == nil || *child.ParentID != folder.ID {
+			continue
+		}
+		name := child.Name
+		for i := 2; taken[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s (%d)", child.Name, i)
+		}
+		taken[strings.ToLower(name)] = true
+		if name != child.Name {
+			child.Name = name
+			if err := s.folderRepo.Update(ctx, child); err != nil {
+				s.log.Errorf("Failed to rename folder %s: %v", child.ID.Hex(), err)
+				return err
+			}
+		}
+	}
+
+	if err := s.schemaRepo.ReassignFolder(ctx, userID, folder.ID, folder.ParentID); err != nil {
+		return err
+	}
+	if err := s.folderRepo.Reparent(ctx, userID, folder.ID, folder.ParentID); err != nil {
+		return err
+	}
+	if err := s.folderRepo.Delete(ctx, folder.ID); err != nil {
+		s.log.Errorf("Failed to delete folder %s: %v", id.Hex(), err)
+		return err
+	}
+
+	return nil
+}
+
+// MoveSchemas files schemas of the user into a folder, or at the root.
+// Schemas of other users are skipped; the count moved is returned.
+func (s *OrganizeService) MoveSchemas(ctx context.Context, userID primitive.ObjectID, req *models.MoveSchemasRequest) (int64, error) {
+	ids, err := parseObjectIDs(req.SchemaIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	var folderID *primitive.ObjectID
+	if req.FolderID != "" {
+		folder, err := s.ownFolder(ctx, req.FolderID, userID)
+		if err != nil {
+			return 0, err
+		}
+		folderID = &folder.ID
+	}
+
+	return s.schemaRepo.MoveToFolder(ctx, userID, ids, folderID)
+}
+
+// SetSchemaTags replaces the tags of one of the user's schemas.
+func (s *OrganizeService) SetSchemaTags(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.SetSchemaTagsRequest) ([]string, error) {
+	schema, err := s.schemaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("schema not found: %v", err)
+	}
+
+	if schema.UserID != userID {
+		return nil, fmt.Errorf("access denied: you can only tag your own schemas")
+	}
+
+	tags := normalizeTags(req.Tags)
+	if err := s.schemaRepo.SetTags(ctx, id, tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// TagSchemas adds and removes tags on schemas of the user and returns how
+// many schemas changed.
+func (s *OrganizeService) TagSchemas(ctx context.Context, userID primitive.ObjectID, req *models.TagSchemasRequest) (int64, error) {
+	ids, err := parseObjectIDs(req.SchemaIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	add, remove := normalizeTags(req.Add), normalizeTags(req.Remove)
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, fmt.Errorf("invalid tags: nothing to add or remove")
+	}
+
+	var changed int64
+	if len(add) > 0 {
+		count, err := s.schemaRepo.AddTags(ctx, userID, ids, add)
+		if err != nil {
+			return 0, err
+		}
+		changed += count
+	}
+	if len(remove) > 0 {
+		count, err := s.schemaRepo.RemoveTags(ctx, userID, ids, remove)
+		if err != nil {
+			return 0, err
+		}
+		changed += count
+	}
+
+	return changed, nil
+}
+
+// ListTags returns the tags the user has used, with their schema counts.
+func (s *OrganizeService) ListTags(ctx context.Context, userID primitive.ObjectID) ([]TagCount, error) {
+	counts, err := s.schemaRepo.CountByTag(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]TagCount, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, TagCount{Name: name, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+func (s *OrganizeService) RenameTag(ctx context.Context, userID primitive.ObjectID, tag string, req *models.RenameTagRequest) (int64, error) {
+	from, to := NormalizeTag(tag), NormalizeTag(req.Name)
+	if from == "" || to == "" {
+		return 0, fmt.Errorf("invalid tags: tag names cannot be empty")
+	}
+	if from == to {
+		return 0, nil
+	}
+
+	return s.schemaRepo.RenameTag(ctx, userID, from, to)
+}
+
+// DeleteTag removes a tag from all of the user's schemas.
+func (s *OrganizeService) DeleteTag(ctx context.Context, userID primitive.ObjectID, tag string) (int64, error) {
+	return s.schemaRepo.RemoveTags(ctx, userID, nil, []string{NormalizeTag(tag)})
+}
+
+func (s *OrganizeService) ownFolder(ctx context.Context, id string, userID primitive.ObjectID) (*models.Folder, error) {
+	folderID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid folder: %s is not a folder ID", id)
+	}
+
+	folder, err := s.folderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	// Other users' folders are reported as missing.
+	if folder.UserID != userID {
+		return nil, fmt.Errorf("folder not found")
+	}
+
+	return folder, nil
+}
+
+// checkFolderName rejects a name already used by a sibling folder.
+func (s *OrganizeService) checkFolderName(ctx context.Context, userID primitive.ObjectID, folder *models.Folder) error {
+	folders, err := s.folderRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range folders {
+		if other.ID != folder.ID && strings.EqualFold(other.Name, folder.Name) && sameFolder(other.ParentID, folder.ParentID) {
+			return fmt.Errorf("folder already exists: %s", folder.Name)
+		}
+	}
+	return nil
+}
+
+func sameFolder(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// NormalizeTag trims and lower-cases a tag so tags compare case-insensitively.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" && !contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+func parseObjectIDs(values []string) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, value := range values {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema ID: %s", value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,703 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"schema-builder-backend/internal/models"
+)
+
+var prismaNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// prismaScalars maps SQL base types to Prisma scalar types. Anything not
+// listed becomes Unsupported("...").
+var prismaScalars = map[string]string{
+	"INTEGER": "Int", "INT": "Int", "INT4": "Int", "SMALLINT": "Int", "INT2": "Int", "MEDIUMINT": "Int",
+	"TINYINT": "Int", "YEAR": "Int", "OID": "Int", "SERIAL": "Int", "SMALLSERIAL": "Int",
+	"BIGINT": "BigInt", "INT8": "BigInt", "BIGSERIAL": "BigInt",
+	"REAL": "Float", "FLOAT": "Float", "FLOAT4": "Float", "FLOAT8": "Float", "DOUBLE": "Float", "DOUBLE PRECISION": "Float",
+	"DECIMAL": "Decimal", "NUMERIC": "Decimal", "MONEY": "Decimal",
+	"BOOLEAN": "Boolean", "BOOL": "Boolean",
+	"TEXT": "String", "VARCHAR": "String", "CHAR": "String", "CHARACTER": "String", "CHARACTER VARYING": "String",
+	"NVARCHAR": "String", "NCHAR": "String", "UUID": "String", "CITEXT": "String", "XML": "String", "INET": "String",
+	"TINYTEXT": "String", "MEDIUMTEXT": "String", "LONGTEXT": "String", "STRING": "String", "BIT": "String", "VARBIT": "String",
+	"TIMESTAMP": "DateTime", "TIMESTAMPTZ": "DateTime", "TIMESTAMP WITH TIME ZONE": "DateTime", "TIMESTAMP WITHOUT TIME ZONE": "DateTime",
+	"DATETIME": "DateTime", "DATE": "DateTime", "TIME": "DateTime", "TIMETZ": "DateTime",
+	"JSON": "Json", "JSONB": "Json",
+	"BYTEA": "Bytes", "BLOB": "Bytes", "BINARY": "Bytes", "VARBINARY": "Bytes", "TINYBLOB": "Bytes", "MEDIUMBLOB": "Bytes", "LONGBLOB": "Bytes",
+}
+
+// prismaDefaultTypes is the column type Prisma creates for a scalar without
+// a native type attribute.
+var prismaDefaultTypes = map[string]map[string]string{
+	"postgresql": {
+		"Int": "INTEGER", "BigInt": "BIGINT", "Float": "DOUBLE PRECISION", "Decimal": "DECIMAL", "Boolean": "BOOLEAN",
+		"String": "TEXT", "DateTime": "TIMESTAMP", "Json": "JSONB", "Bytes": "BYTEA",
+	},
+	"mysql": {
+		"Int": "INT", "BigInt": "BIGINT", "Float": "DOUBLE", "Decimal": "DECIMAL", "Boolean": "BOOLEAN",
+		"String": "VARCHAR", "DateTime": "DATETIME", "Json": "JSON", "Bytes": "LONGBLOB",
+	},
+	"sqlite": {
+		"Int": "INTEGER", "BigInt": "BIGINT", "Float": "REAL", "Decimal": "DECIMAL", "Boolean": "BOOLEAN",
+		"String": "TEXT", "DateTime": "DATETIME", "Json": "JSONB", "Bytes": "BLOB",
+	},
+}
+
+// prismaNativeTypes lists the @db native type attributes per provider with
+// the column type each one stands for.
+var prismaNativeTypes = map[string]map[string]string{
+	"postgresql": {
+		"Text": "TEXT", "Char": "CHAR", "VarChar": "VARCHAR", "Bit": "BIT", "VarBit": "VARBIT", "Uuid": "UUID", "Xml": "XML",
+		"Inet": "INET", "Citext": "CITEXT", "Boolean": "BOOLEAN", "Integer": "INTEGER", "SmallInt": "SMALLINT", "Oid": "OID",
+		"BigInt": "BIGINT", "Real": "REAL", "DoublePrecision": "DOUBLE PRECISION", "Decimal": "DECIMAL", "Money": "MONEY",
+		"Timestamp": "TIMESTAMP", "Timestamptz": "TIMESTAMPTZ", "Date": "DATE", "Time": "TIME", "Timetz": "TIMETZ",
+		"Json": "JSON", "JsonB": "JSONB", "ByteA": "BYTEA",
+	},
+	"mysql": {
+		"VarChar": "VARCHAR", "Text": "TEXT", "Char": "CHAR", "TinyText": "TINYTEXT", "MediumText": "MEDIUMTEXT",
+		"LongText": "LONGTEXT", "Binary": "BINARY", "VarBinary": "VARBINARY", "TinyBlob": "TINYBLOB", "Blob": "BLOB",
+		"MediumBlob": "MEDIUMBLOB", "LongBlob": "LONGBLOB", "Bit": "BIT", "TinyInt": "TINYINT", "SmallInt": "SMALLINT",
+		"MediumInt": "MEDIUMINT", "Int": "INT", "BigInt": "BIGINT", "Year": "YEAR", "Float": "FLOAT", "Double": "DOUBLE",
+		"Decimal": "DECIMAL", "DateTime": "DATETIME", "Timestamp": "TIMESTAMP", "Date": "DATE", "Time": "TIME", "Json": "JSON",
+	},
+}
+
+// prismaTypeAliases folds spellings of the same column type together before
+// a native type is chosen.
+var prismaTypeAliases = map[string]string{
+	"INT4": "INTEGER", "INT2": "SMALLINT", "INT8": "BIGINT", "FLOAT4": "REAL", "FLOAT8": "DOUBLE PRECISION",
+	"BOOL": "BOOLEAN", "NUMERIC": "DECIMAL", "CHARACTER": "CHAR", "CHARACTER VARYING": "VARCHAR",
+	"TIMESTAMP WITH TIME ZONE": "TIMESTAMPTZ", "TIMESTAMP WITHOUT TIME ZONE": "TIMESTAMP",
+}
+
+var prismaReferentialActions = map[string]string{
+	"CASCADE":     "Cascade",
+	"SET NULL":    "SetNull",
+	"SET DEFAULT": "SetDefault",
+	"RESTRICT":    "Restrict",
+	"NO ACTION":   "NoAction",
+}
+
+var prismaIndexMethods = map[string]string{
+	IndexMethodBTree:  "BTree",
+	IndexMethodHash:   "Hash",
+	IndexMethodGIN:    "Gin",
+	IndexMethodGiST:   "Gist",
+	IndexMethodSPGiST: "SpGist",
+	IndexMethodBRIN:   "Brin",
+}
+
+func prismaProvider(databaseType string) string {
+	if _, ok := prismaDefaultTypes[databaseType]; ok {
+		return databaseType
+	}
+	return "postgresql"
+}
+
+func pascalCase(name string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			b.WriteRune(unicode.ToUpper(r))
+			upperNext = false
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	result := b.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		result = "T" + result
+	}
+	return result
+}
+
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return name
+	}
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// prismaFieldName returns a valid Prisma identifier for a column; the caller
+// adds @map when it differs from the column name.
+func prismaFieldName(name string) string {
+	if prismaNamePattern.MatchString(name) {
+		return name
+	}
+	return lowerFirst(pascalCase(name))
+}
+
+// splitColumnType separates "VARCHAR(255)" into its base type and argument
+// list, using the length, precision and scale of the field when the type
+// itself carries none.
+func splitColumnType(field *models.Field) (string, string) {
+	rendered := renderColumnType("", field)
+	array := ""
+	for strings.HasSuffix(rendered, "[]") {
+		rendered = strings.TrimSuffix(rendered, "[]")
+		array += "[]"
+	}
+	base, args := rendered, ""
+	if idx := strings.Index(rendered, "("); idx >= 0 && strings.HasSuffix(rendered, ")") {
+		base, args = strings.TrimSpace(rendered[:idx]), strings.ReplaceAll(rendered[idx+1:len(rendered)-1], " ", "")
+	}
+	return strings.ToUpper(base) + array, args
+}
+
+type prismaWriter struct {
+	schema     *models.Schema
+	provider   string
+	multi      bool
+	models     map[*models.Table]string
+	enums      map[*models.Enum]string
+	relations  []schemaRelation
+	fieldNames map[*models.Table]map[string]bool
+}
+
+func GeneratePrisma(schema *models.Schema) string {
+	w := &prismaWriter{
+		schema:     schema,
+		provider:   prismaProvider(schema.DatabaseType),
+		models:     make(map[*models.Table]string),
+		enums:      make(map[*models.Enum]string),
+		relations:  collectRelations(schema),
+		fieldNames: make(map[*models.Table]map[string]bool),
+	}
+
+	namespaces := map[string]bool{}
+	for _, table := range schema.Tables {
+		namespaces[table.Namespace] = true
+	}
+	for _, enum := range schema.Enums {
+		namespaces[enum.Namespace] = true
+	}
+	delete(namespaces, "")
+	w.multi = len(namespaces) > 0 && w.provider == "postgresql"
+
+	// Prisma rejects models without fields or a unique criteria, so those
+	// tables are left out together with their relations.
+	skipped := make(map[*models.Table]string)
+	for i := range schema.Tables {
+		if reason := prismaUnsupportedModel(&schema.Tables[i]); reason != "" {
+			skipped[&schema.Tables[i]] = reason
+		}
+	}
+	relations := w.relations[:0]
+	for _, relation := range w.relations {
+		if skipped[relation.From] == "" && skipped[relation.To] == "" {
+			relations = append(relations, relation)
+		}
+	}
+	w.relations = relations
+
+	taken := make(map[string]bool)
+	name := func(namespace, base string) string {
+		candidate := pascalCase(base)
+		if taken[candidate] && namespace != "" {
+			candidate = pascalCase(namespace) + candidate
+		}
+		for i := 2; taken[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", pascalCase(base), i)
+		}
+		taken[candidate] = true
+		return candidate
+	}
+	for i := range schema.Tables {
+		w.models[&schema.Tables[i]] = name(schema.Tables[i].Namespace, schema.Tables[i].Name)
+	}
+	for i := range schema.Enums {
+		w.enums[&schema.Enums[i]] = name(schema.Enums[i].Namespace, schema.Enums[i].Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n// Generated by Schema Builder\n\n", schema.Name)
+	b.WriteString("generator client {\n  provider = \"prisma-client-js\"\n")
+	if w.multi {
+		b.WriteString("  previewFeatures = [\"multiSchema\"]\n")
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "datasource db {\n  provider = %q\n  url      = env(\"DATABASE_URL\")\n", w.provider)
+	if w.multi {
+		names := []string{"public"}
+		for namespace := range namespaces {
+			if namespace != "public" {
+				names = append(names, namespace)
+			}
+		}
+		sort.Strings(names[1:])
+		quoted := make([]string, len(names))
+		for i, namespace := range names {
+			quoted[i] = fmt.Sprintf("%q", namespace)
+		}
+		fmt.Fprintf(&b, "  schemas  = [%s]\n", strings.Join(quoted, ", "))
+	}
+	b.WriteString("}\n")
+
+	for i := range schema.Tables {
+		b.WriteString("\n")
+		if reason := skipped[&schema.Tables[i]]; reason != "" {
+			fmt.Fprintf(&b, "// table %s is not supported by Prisma: %s\n", QualifiedName(schema.Tables[i].Namespace, schema.Tables[i].Name), reason)
+			continue
+		}
+		b.WriteString(w.model(&schema.Tables[i]))
+	}
+	for i := range schema.Enums {
+		b.WriteString("\n")
+		b.WriteString(w.enum(&schema.Enums[i]))
+	}
+
+	return b.String()
+}
+
+func (w *prismaWriter) schemaAttribute(namespace string) string {
+	if !w.multi {
+		return ""
+	}
+	if namespace == "" {
+		namespace = "public"
+	}
+	return fmt.Sprintf("@@schema(%q)", namespace)
+}
+
+func (w *prismaWriter) enum(enum *models.Enum) string {
+	var b strings.Builder
+	if enum.Comment != "" {
+		b.WriteString(prismaDocComment(enum.Comment, ""))
+	}
+	fmt.Fprintf(&b, "enum %s {\n", w.enums[enum])
+	for _, value := range enum.Values {
+		name := prismaFieldName(value)
+		if name == value {
+			fmt.Fprintf(&b, "  %s\n", value)
+		} else {
+			fmt.Fprintf(&b, "  %s @map(%q)\n", name, value)
+		}
+	}
+	if w.enums[enum] != enum.Name {
+		fmt.Fprintf(&b, "\n  @@map(%q)\n", enum.Name)
+	}
+	if attribute := w.schemaAttribute(enum.Namespace); attribute != "" {
+		fmt.Fprintf(&b, "  %s\n", attribute)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func prismaDocComment(text, indent string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(&b, "%s/// %s\n", indent, strings.TrimRight(line, " \t\r"))
+	}
+	return b.String()
+}
+
+// fieldType returns the Prisma type and the native type attribute, if any.
+func (w *prismaWriter) fieldType(table *models.Table, field *models.Field) (string, string) {
+	if enum := resolveEnum(w.schema, table.Namespace, field.Type); enum != nil {
+		return w.enums[enum], ""
+	}
+
+	base, args := splitColumnType(field)
+	list := strings.HasSuffix(base, "[]")
+	base = strings.TrimSuffix(base, "[]")
+	suffix := ""
+	if list {
+		suffix = "[]"
+	}
+
+	scalar, ok := prismaScalars[base]
+	if !ok {
+		return fmt.Sprintf("Unsupported(%q)", strings.TrimSpace(field.Type)), ""
+	}
+	if base == "SERIAL" || base == "BIGSERIAL" || base == "SMALLSERIAL" {
+		if base == "SMALLSERIAL" && w.provider == "postgresql" {
+			return scalar + suffix, "@db.SmallInt"
+		}
+		return scalar + suffix, ""
+	}
+
+	canonical := base
+	if alias, ok := prismaTypeAliases[base]; ok {
+		canonical = alias
+	}
+	if canonical == prismaDefaultTypes[w.provider][scalar] && args == "" {
+		return scalar + suffix, ""
+	}
+	for native, sqlType := range prismaNativeTypes[w.provider] {
+		if sqlType == canonical {
+			if args != "" {
+				return scalar + suffix, fmt.Sprintf("@db.%s(%s)", native, args)
+			}
+			return scalar + suffix, "@db." + native
+		}
+	}
+	return scalar + suffix, ""
+}
+
+func (w *prismaWriter) defaultAttribute(table *models.Table, field *models.Field, prismaType string) string {
+	base, _ := splitColumnType(field)
+	if base == "SERIAL" || base == "BIGSERIAL" || base == "SMALLSERIAL" {
+		return "@default(autoincrement())"
+	}
+
+	value := strings.TrimSpace(field.DefaultValue)
+	if value == "" || strings.EqualFold(value, "NULL") {
+		return ""
+	}
+	upper := strings.ToUpper(value)
+	quoted := len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'")
+
+	if enum := resolveEnum(w.schema, table.Namespace, field.Type); enum != nil {
+		literal := strings.Trim(value, "'")
+		for _, enumValue := range enum.Values {
+			if enumValue == literal {
+				return fmt.Sprintf("@default(%s)", prismaFieldName(enumValue))
+			}
+		}
+	}
+
+	switch {
+	case upper == "CURRENT_TIMESTAMP" || upper == "NOW()":
+		return "@default(now())"
+	case upper == "UUID()" || upper == "CUID()":
+		return "@default(" + strings.ToLower(value) + ")"
+	case (upper == "TRUE" || upper == "FALSE") && strings.HasPrefix(prismaType, "Boolean"):
+		return "@default(" + strings.ToLower(value) + ")"
+	case numericLiteralPattern.MatchString(value) && !strings.HasPrefix(prismaType, "String"):
+		return "@default(" + value + ")"
+	case quoted:
+		return fmt.Sprintf("@default(%q)", strings.ReplaceAll(value[1:len(value)-1], "''", "'"))
+	case sqlKeywordDefaults[upper] || functionLiteralPattern.MatchString(value):
+		return fmt.Sprintf("@default(dbgenerated(%q))", value)
+	}
+	return fmt.Sprintf("@default(%q)", value)
+}
+
+type prismaLine struct {
+	doc        string
+	name       string
+	fieldType  string
+	attributes []string
+}
+
+func (w *prismaWriter) model(table *models.Table) string {
+	names := make(map[string]bool)
+	w.fieldNames[table] = names
+	fieldName := make(map[*models.Field]string)
+	for i := range table.Fields {
+		name := prismaFieldName(table.Fields[i].Name)
+		names[name] = true
+		fieldName[&table.Fields[i]] = name
+	}
+
+	primaryKey := primaryKeyFields(table)
+	inPrimaryKey := make(map[*models.Field]bool, len(primaryKey))
+	for _, field := range primaryKey {
+		inPrimaryKey[field] = true
+	}
+	var lines []prismaLine
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		line := prismaLine{name: fieldName[field]}
+		prismaType, native := w.fieldType(table, field)
+		line.fieldType = prismaType
+		if prismaOptional(field, inPrimaryKey) && !strings.HasSuffix(prismaType, "[]") {
+			line.fieldType += "?"
+		}
+		if len(primaryKey) == 1 && primaryKey[0] == field {
+			line.attributes = append(line.attributes, "@id")
+		}
+		if field.IsUnique {
+			line.attributes = append(line.attributes, "@unique")
+		}
+		if attribute := w.defaultAttribute(table, field, prismaType); attribute != "" {
+			line.attributes = append(line.attributes, attribute)
+		}
+		if line.name != field.Name {
+			line.attributes = append(line.attributes, fmt.Sprintf("@map(%q)", field.Name))
+		}
+		if native != "" {
+			line.attributes = append(line.attributes, native)
+		}
+		if field.Comment != "" {
+			line.doc = prismaDocComment(field.Comment, "  ")
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, w.relationLines(table, fieldName, inPrimaryKey)...)
+
+	var blockAttributes, notes []string
+	columns := func(fields []*models.Field) string {
+		names := make([]string, len(fields))
+		for i, field := range fields {
+			names[i] = fieldName[field]
+		}
+		return strings.Join(names, ", ")
+	}
+	if len(primaryKey) > 1 {
+		blockAttributes = append(blockAttributes, fmt.Sprintf("@@id([%s])", columns(primaryKey)))
+	}
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		switch constraintKind(constraint) {
+		case ConstraintUnique:
+			if fields := constraintFields(table, constraint); len(fields) > 0 {
+				blockAttributes = append(blockAttributes, prismaBlockAttribute("@@unique", columns(fields), constraint.Name, ""))
+			}
+		case ConstraintCheck:
+			notes = append(notes, fmt.Sprintf("// check constraint %s is not supported by Prisma: %s", constraint.Name, constraint.CheckCondition))
+		}
+	}
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+		var parts []string
+		expression := false
+		for _, column := range indexColumns(index) {
+			field := findField(table, column.FieldID)
+			if field == nil {
+				expression = true
+				break
+			}
+			part := fieldName[field]
+			if strings.EqualFold(column.Order, "desc") {
+				part += "(sort: Desc)"
+			}
+			parts = append(parts, part)
+		}
+		label := indexDisplayName(table, index)
+		if expression || len(parts) == 0 {
+			notes = append(notes, fmt.Sprintf("// expression index %s is not supported by Prisma", label))
+			continue
+		}
+		if strings.TrimSpace(index.Where) != "" {
+			notes = append(notes, fmt.Sprintf("// partial index %s is not supported by Prisma; WHERE %s omitted", label, strings.Join(strings.Fields(index.Where), " ")))
+		}
+		attribute, method := "@@index", ""
+		switch {
+		case index.IsUnique:
+			attribute = "@@unique"
+		case indexMethod(index) == IndexMethodFullText && w.provider == "mysql":
+			attribute = "@@fulltext"
+		case w.provider == "postgresql" && indexMethod(index) != IndexMethodBTree:
+			method = prismaIndexMethods[indexMethod(index)]
+		}
+		blockAttributes = append(blockAttributes, prismaBlockAttribute(attribute, strings.Join(parts, ", "), index.Name, method))
+	}
+	if w.models[table] != table.Name {
+		blockAttributes = append(blockAttributes, fmt.Sprintf("@@map(%q)", table.Name))
+	}
+	if attribute := w.schemaAttribute(table.Namespace); attribute != "" {
+		blockAttributes = append(blockAttributes, attribute)
+	}
+
+	nameWidth, typeWidth := 0, 0
+	for _, line := range lines {
+		if len(line.name) > nameWidth {
+			nameWidth = len(line.name)
+		}
+		if len(line.attributes) > 0 && len(line.fieldType) > typeWidth {
+			typeWidth = len(line.fieldType)
+		}
+	}
+
+	var b strings.Builder
+	if table.Description != "" {
+		b.WriteString(prismaDocComment(table.Description, ""))
+	}
+	fmt.Fprintf(&b, "model %s {\n", w.models[table])
+	for _, line := range lines {
+		b.WriteString(line.doc)
+		text := fmt.Sprintf("  %-*s %s", nameWidth, line.name, line.fieldType)
+		if len(line.attributes) > 0 {
+			text = fmt.Sprintf("  %-*s %-*s %s", nameWidth, line.name, typeWidth, line.fieldType, strings.Join(line.attributes, " "))
+		}
+		b.WriteString(text + "\n")
+	}
+	if len(blockAttributes) > 0 || len(notes) > 0 {
+		b.WriteString("\n")
+	}
+	for _, attribute := range blockAttributes {
+		fmt.Fprintf(&b, "  %s\n", attribute)
+	}
+	for _, note := range notes {
+		fmt.Fprintf(&b, "  %s\n", note)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// prismaUnsupportedModel returns why a table cannot be a Prisma model, or
+// an empty string if it can: a model needs a field and an @id, @@id or a
+// unique criteria over required fields only.
+func prismaUnsupportedModel(table *models.Table) string {
+	if len(table.Fields) == 0 {
+		return "it has no fields"
+	}
+	if len(primaryKeyFields(table)) > 0 {
+		return ""
+	}
+
+	required := func(fields []*models.Field) bool {
+		for _, field := range fields {
+			if !field.IsNotNull {
+				return false
+			}
+		}
+		return len(fields) > 0
+	}
+	for i := range table.Fields {
+		if table.Fields[i].IsUnique && table.Fields[i].IsNotNull {
+			return ""
+		}
+	}
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		if constraintKind(constraint) == ConstraintUnique && required(constraintFields(table, constraint)) {
+			return ""
+		}
+	}
+	for i := range table.Indexes {
+		if !table.Indexes[i].IsUnique {
+			continue
+		}
+		var fields []*models.Field
+		for _, column := range indexColumns(&table.Indexes[i]) {
+			field := findField(table, column.FieldID)
+			if field == nil {
+				// Expression indexes are not carried over.
+				fields = nil
+				break
+			}
+			fields = append(fields, field)
+		}
+		if required(fields) {
+			return ""
+		}
+	}
+	return "it has no primary key or unique key over required fields"
+}
+
+func prismaOptional(field *models.Field, inPrimaryKey map[*models.Field]bool) bool {
+	return !field.IsNotNull && !field.IsPrimaryKey && !inPrimaryKey[field]
+}
+
+func prismaBlockAttribute(attribute, columns, name, method string) string {
+	args := "[" + columns + "]"
+	if name != "" {
+		args += fmt.Sprintf(", map: %q", name)
+	}
+	if method != "" {
+		args += ", type: " + method
+	}
+	return attribute + "(" + args + ")"
+}
+
+// relationLines adds the relation fields of a model: the side holding the
+// foreign key gets @relation(fields, references), the referenced side gets
+// the back-relation list or optional field Prisma requires.
+func (w *prismaWriter) relationLines(table *models.Table, fieldName map[*models.Field]string, inPrimaryKey map[*models.Field]bool) []prismaLine {
+	names := w.fieldNames[table]
+	claim := func(base string) string {
+		candidate := prismaFieldName(base)
+		for i := 2; names[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", prismaFieldName(base), i)
+		}
+		names[candidate] = true
+		return candidate
+	}
+
+	pairs := make(map[[2]*models.Table]int)
+	for _, relation := range w.relations {
+		pairs[[2]*models.Table{relation.From, relation.To}]++
+		if relation.From != relation.To {
+			pairs[[2]*models.Table{relation.To, relation.From}]++
+		}
+	}
+
+	var lines []prismaLine
+	for i := range w.relations {
+		relation := &w.relations[i]
+		named := relation.From == relation.To || pairs[[2]*models.Table{relation.From, relation.To}] > 1
+		relationName := ""
+		if named {
+			relationName = fmt.Sprintf("%q", relation.Name)
+		}
+
+		if relation.From == table {
+			base := lowerFirst(w.models[relation.To])
+			if len(relation.FromFields) == 1 {
+				column := fieldName[relation.FromFields[0]]
+				if trimmed := strings.TrimSuffix(strings.TrimSuffix(column, "_id"), "Id"); trimmed != column && trimmed != "" {
+					base = trimmed
+				}
+			}
+
+			fields := make([]string, len(relation.FromFields))
+			for k, field := range relation.FromFields {
+				fields[k] = fieldName[field]
+			}
+			references := make([]string, len(relation.ToFields))
+			for k, field := range relation.ToFields {
+				references[k] = prismaFieldName(field.Name)
+			}
+
+			var args []string
+			if relationName != "" {
+				args = append(args, relationName)
+			}
+			args = append(args, fmt.Sprintf("fields: [%s]", strings.Join(fields, ", ")), fmt.Sprintf("references: [%s]", strings.Join(references, ", ")))
+			if action := prismaReferentialActions[strings.ToUpper(relation.OnDelete)]; action != "" {
+				args = append(args, "onDelete: "+action)
+			}
+			if action := prismaReferentialActions[strings.ToUpper(relation.OnUpdate)]; action != "" {
+				args = append(args, "onUpdate: "+action)
+			}
+			if relation.Constraint != nil && relation.Constraint.Name != "" {
+				args = append(args, fmt.Sprintf("map: %q", relation.Constraint.Name))
+			}
+
+			// Prisma requires the relation field to be optional exactly when
+			// one of its scalar fields is.
+			fieldType := w.models[relation.To]
+			for _, field := range relation.FromFields {
+				if prismaOptional(field, inPrimaryKey) {
+					fieldType += "?"
+					break
+				}
+			}
+			lines = append(lines, prismaLine{
+				name:       claim(base),
+				fieldType:  fieldType,
+				attributes: []string{"@relation(" + strings.Join(args, ", ") + ")"},
+			})
+		}
+
+		if relation.To == table {
+			fieldType := w.models[relation.From] + "[]"
+			base := pluralize(singularize(lowerFirst(w.models[relation.From])))
+			if relation.IsOneToOne() {
+				fieldType = w.models[relation.From] + "?"
+				base = lowerFirst(w.models[relation.From])
+			}
+			line := prismaLine{name: claim(base), fieldType: fieldType}
+			if relationName != "" {
+				line.attributes = []string{"@relation(" + relationName + ")"}
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,895 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"schema-builder-backend/internal/models"
+)
+
+type prismaTokenKind int
+
+const (
+	prismaTokenEOF prismaTokenKind = iota
+	prismaTokenNewline
+	prismaTokenDoc
+	prismaTokenIdent
+	prismaTokenString
+	prismaTokenPunct
+)
+
+type prismaToken struct {
+	Kind prismaTokenKind
+	Text string
+	Line int
+}
+
+func (t prismaToken) is(text string) bool {
+	return t.Kind == prismaTokenPunct && t.Text == text
+}
+
+func tokenizePrisma(content string) ([]prismaToken, error) {
+	var tokens []prismaToken
+	runes := []rune(content)
+	line := 1
+
+	isIdent := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			tokens = append(tokens, prismaToken{Kind: prismaTokenNewline, Line: line})
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			start := i
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			text := string(runes[start:i])
+			if strings.HasPrefix(text, "///") {
+				text = strings.TrimPrefix(text, "///")
+				text = strings.TrimPrefix(text, " ")
+				tokens = append(tokens, prismaToken{Kind: prismaTokenDoc, Text: strings.TrimRight(text, " \t\r"), Line: line})
+			}
+		case r == '"':
+			var b strings.Builder
+			startLine := line
+			i++
+			for {
+				if i >= len(runes) || runes[i] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", startLine)
+				}
+				if runes[i] == '"' {
+					i++
+					break
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						b.WriteRune('\n')
+					case 't':
+						b.WriteRune('\t')
+					default:
+						b.WriteRune(runes[i])
+					}
+					i++
+					continue
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, prismaToken{Kind: prismaTokenString, Text: b.String(), Line: startLine})
+		case isIdent(r) && (r != '-' || (i+1 < len(runes) && unicode.IsDigit(runes[i+1]))):
+			start := i
+			for i < len(runes) && isIdent(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, prismaToken{Kind: prismaTokenIdent, Text: string(runes[start:i]), Line: line})
+		case strings.ContainsRune("@()[]{},:?=", r):
+			tokens = append(tokens, prismaToken{Kind: prismaTokenPunct, Text: string(r), Line: line})
+			i++
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, r)
+		}
+	}
+	return append(tokens, prismaToken{Kind: prismaTokenEOF, Line: line}), nil
+}
+
+type prismaValueKind int
+
+const (
+	prismaValueIdent prismaValueKind = iota
+	prismaValueString
+	prismaValueCall
+	prismaValueList
+)
+
+// prismaValue is an attribute argument: a bare identifier or number, a
+// string, a function call such as now() or a list.
+type prismaValue struct {
+	Kind  prismaValueKind
+	Text  string
+	Args  []prismaArg
+	Items []prismaValue
+}
+
+type prismaArg struct {
+	Name  string
+	Value prismaValue
+}
+
+type prismaAttribute struct {
+	Name string
+	Args []prismaArg
+	Line int
+}
+
+// arg returns the named argument, falling back to the unnamed argument at
+// position when the attribute allows it positionally.
+func (a *prismaAttribute) arg(name string, position int) *prismaValue {
+	unnamed := 0
+	for i := range a.Args {
+		if a.Args[i].Name == name {
+			return &a.Args[i].Value
+		}
+		if a.Args[i].Name == "" {
+			if unnamed == position {
+				return &a.Args[i].Value
+			}
+			unnamed++
+		}
+	}
+	return nil
+}
+
+type prismaFieldDef struct {
+	Name       string
+	Type       string
+	Optional   bool
+	List       bool
+	Doc        string
+	Attributes []prismaAttribute
+	Line       int
+}
+
+type prismaBlock struct {
+	Kind       string
+	Name       string
+	Doc        string
+	Fields     []prismaFieldDef
+	Attributes []prismaAttribute
+	Settings   map[string]prismaValue
+	Line       int
+}
+
+func findPrismaAttribute(attributes []prismaAttribute, name string) *prismaAttribute {
+	for i := range attributes {
+		if attributes[i].Name == name {
+			return &attributes[i]
+		}
+	}
+	return nil
+}
+
+func prismaStringAttribute(attributes []prismaAttribute, name string) string {
+	if attribute := findPrismaAttribute(attributes, name); attribute != nil {
+		if value := attribute.arg("name", 0); value != nil && value.Kind == prismaValueString {
+			return value.Text
+		}
+	}
+	return ""
+}
+
+type prismaParser struct {
+	tokens []prismaToken
+	pos    int
+	blocks []prismaBlock
+}
+
+func ParsePrisma(content string) (*models.CreateSchemaRequest, error) {
+	tokens, err := tokenizePrisma(content)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &prismaParser{tokens: tokens}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return resolvePrisma(p.blocks)
+}
+
+func (p *prismaParser) peek() prismaToken {
+	return p.tokens[p.pos]
+}
+
+func (p *prismaParser) next() prismaToken {
+	token := p.tokens[p.pos]
+	if token.Kind != prismaTokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *prismaParser) errorf(token prismaToken, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", token.Line, fmt.Sprintf(format, args...))
+}
+
+func (p *prismaParser) expect(text string) error {
+	if token := p.next(); !token.is(text) {
+		return p.errorf(token, "expected %q, found %q", text, token.Text)
+	}
+	return nil
+}
+
+func (p *prismaParser) expectIdent() (string, error) {
+	token := p.next()
+	if token.Kind != prismaTokenIdent {
+		return "", p.errorf(token, "expected a name, found %q", token.Text)
+	}
+	return token.Text, nil
+}
+
+func (p *prismaParser) endOfLine() error {
+	token := p.peek()
+	switch {
+	case token.Kind == prismaTokenNewline:
+		p.next()
+		return nil
+	case token.Kind == prismaTokenEOF || token.is("}"):
+		return nil
+	}
+	return p.errorf(token, "unexpected %q", token.Text)
+}
+
+// readDoc consumes blank lines and returns the /// comments directly above
+// the next declaration.
+func (p *prismaParser) readDoc() string {
+	var lines []string
+	for {
+		switch token := p.peek(); token.Kind {
+		case prismaTokenNewline:
+			p.next()
+		case prismaTokenDoc:
+			lines = append(lines, token.Text)
+			p.next()
+		default:
+			return strings.Join(lines, "\n")
+		}
+	}
+}
+
+func (p *prismaParser) parse() error {
+	for {
+		doc := p.readDoc()
+		token := p.next()
+		if token.Kind == prismaTokenEOF {
+			return nil
+		}
+		if token.Kind != prismaTokenIdent {
+			return p.errorf(token, "unexpected %q", token.Text)
+		}
+
+		name, err := p.expectIdent()
+		if err != nil {
+			return err
+		}
+		block := prismaBlock{Kind: token.Text, Name: name, Doc: doc, Line: token.Line}
+		if err := p.expect("{"); err != nil {
+			return err
+		}
+
+		switch token.Text {
+		case "datasource", "generator":
+			err = p.parseSettings(&block)
+		case "model", "enum", "view", "type":
+			err = p.parseMembers(&block)
+		default:
+			return p.errorf(token, "unknown block %q", token.Text)
+		}
+		if err != nil {
+			return err
+		}
+		p.blocks = append(p.blocks, block)
+	}
+}
+
+func (p *prismaParser) parseSettings(block *prismaBlock) error {
+	block.Settings = make(map[string]prismaValue)
+	for {
+		p.readDoc()
+		token := p.peek()
+		switch {
+		case token.is("}"):
+			p.next()
+			return nil
+		case token.Kind == prismaTokenEOF:
+			return p.errorf(token, "unterminated %s %q", block.Kind, block.Name)
+		}
+
+		key, err := p.expectIdent()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("="); err != nil {
+			return err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		block.Settings[key] = value
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+// parseMembers reads the body of a model or enum: fields (or enum values)
+// with their attributes, and @@ block attributes.
+func (p *prismaParser) parseMembers(block *prismaBlock) error {
+	for {
+		doc := p.readDoc()
+		token := p.peek()
+		switch {
+		case token.is("}"):
+			p.next()
+			return nil
+		case token.Kind == prismaTokenEOF:
+			return p.errorf(token, "unterminated %s %q", block.Kind, block.Name)
+		case token.is("@"):
+			p.next()
+			if err := p.expect("@"); err != nil {
+				return err
+			}
+			attribute, err := p.parseAttribute()
+			if err != nil {
+				return err
+			}
+			block.Attributes = append(block.Attributes, *attribute)
+			if err := p.endOfLine(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name, err := p.expectIdent()
+		if err != nil {
+			return err
+		}
+		field := prismaFieldDef{Name: name, Doc: doc, Line: token.Line}
+		if block.Kind != "enum" {
+			if field.Type, err = p.parseFieldType(&field); err != nil {
+				return err
+			}
+		}
+		for p.peek().is("@") {
+			p.next()
+			attribute, err := p.parseAttribute()
+			if err != nil {
+				return err
+			}
+			field.Attributes = append(field.Attributes, *attribute)
+		}
+		block.Fields = append(block.Fields, field)
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *prismaParser) parseFieldType(field *prismaFieldDef) (string, error) {
+	token := p.next()
+	if token.Kind != prismaTokenIdent {
+		return "", p.errorf(token, "expected a type for field %q, found %q", field.Name, token.Text)
+	}
+	fieldType := token.Text
+	if fieldType == "Unsupported" {
+		if err := p.expect("("); err != nil {
+			return "", err
+		}
+		raw := p.next()
+		if raw.Kind != prismaTokenString {
+			return "", p.errorf(raw, "expected a string in Unsupported() for field %q", field.Name)
+		}
+		if err := p.expect(")"); err != nil {
+			return "", err
+		}
+		fieldType = "Unsupported:" + raw.Text
+	}
+	if p.peek().is("[") {
+		p.next()
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		field.List = true
+	}
+	if p.peek().is("?") {
+		p.next()
+		field.Optional = true
+	}
+	return fieldType, nil
+}
+
+// parseAttribute reads the name and arguments of an attribute; the leading
+// @ (or @@) has already been consumed.
+func (p *prismaParser) parseAttribute() (*prismaAttribute, error) {
+	token := p.peek()
+	name, err := p.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+	attribute := &prismaAttribute{Name: name, Line: token.Line}
+	if p.peek().is("(") {
+		p.next()
+		if attribute.Args, err = p.parseArgs(); err != nil {
+			return nil, err
+		}
+	}
+	return attribute, nil
+}
+
+// parseArgs reads a comma separated argument list up to and including the
+// closing parenthesis.
+func (p *prismaParser) parseArgs() ([]prismaArg, error) {
+	var args []prismaArg
+	for {
+		if p.peek().is(")") {
+			p.next()
+			return args, nil
+		}
+
+		var arg prismaArg
+		if p.peek().Kind == prismaTokenIdent && p.tokens[p.pos+1].is(":") {
+			arg.Name = p.next().Text
+			p.next()
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		arg.Value = value
+		args = append(args, arg)
+
+		if p.peek().is(",") {
+			p.next()
+		} else if !p.peek().is(")") {
+			return nil, p.errorf(p.peek(), "expected \",\" or \")\", found %q", p.peek().Text)
+		}
+	}
+}
+
+func (p *prismaParser) parseValue() (prismaValue, error) {
+	token := p.next()
+	switch {
+	case token.Kind == prismaTokenString:
+		return prismaValue{Kind: prismaValueString, Text: token.Text}, nil
+	case token.Kind == prismaTokenIdent:
+		if !p.peek().is("(") {
+			return prismaValue{Kind: prismaValueIdent, Text: token.Text}, nil
+		}
+		p.next()
+		args, err := p.parseArgs()
+		if err != nil {
+			return prismaValue{}, err
+		}
+		return prismaValue{Kind: prismaValueCall, Text: token.Text, Args: args}, nil
+	case token.is("["):
+		value := prismaValue{Kind: prismaValueList}
+		for !p.peek().is("]") {
+			item, err := p.parseValue()
+			if err != nil {
+				return prismaValue{}, err
+			}
+			value.Items = append(value.Items, item)
+			if p.peek().is(",") {
+				p.next()
+			} else if !p.peek().is("]") {
+				return prismaValue{}, p.errorf(p.peek(), "expected \",\" or \"]\", found %q", p.peek().Text)
+			}
+		}
+		p.next()
+		return value, nil
+	}
+	return prismaValue{}, p.errorf(token, "unexpected %q", token.Text)
+}
+
+type prismaModelState struct {
+	block  *prismaBlock
+	table  int
+	fields map[string]int
+}
+
+func prismaNamespace(attributes []prismaAttribute) string {
+	namespace := prismaStringAttribute(attributes, "schema")
+	if namespace == "public" {
+		return ""
+	}
+	return namespace
+}
+
+func resolvePrisma(blocks []prismaBlock) (*models.CreateSchemaRequest, error) {
+	request := &models.CreateSchemaRequest{}
+	provider := "postgresql"
+	enums := make(map[string]int)
+	enumValues := make(map[string]map[string]string)
+	var states []prismaModelState
+
+	for i := range blocks {
+		block := &blocks[i]
+		switch block.Kind {
+		case "datasource":
+			if value, ok := block.Settings["provider"]; ok {
+				switch value.Text {
+				case "postgresql", "postgres", "cockroachdb":
+					provider = "postgresql"
+				case "mysql":
+					provider = "mysql"
+				case "sqlite":
+					provider = "sqlite"
+				}
+				request.DatabaseType = provider
+			}
+		case "enum":
+			enum := models.Enum{
+				ID:        newElementID("enum"),
+				Name:      block.Name,
+				Namespace: prismaNamespace(block.Attributes),
+				Comment:   block.Doc,
+			}
+			if name := prismaStringAttribute(block.Attributes, "map"); name != "" {
+				enum.Name = name
+			}
+			values := make(map[string]string)
+			for _, member := range block.Fields {
+				value := member.Name
+				if mapped := prismaStringAttribute(member.Attributes, "map"); mapped != "" {
+					value = mapped
+				}
+				values[member.Name] = value
+				enum.Values = append(enum.Values, value)
+			}
+			enums[block.Name] = len(request.Enums)
+			enumValues[block.Name] = values
+			request.Enums = append(request.Enums, enum)
+		case "model":
+			states = append(states, prismaModelState{block: block, fields: make(map[string]int)})
+		}
+	}
+
+	modelIndex := make(map[string]int)
+	for i := range states {
+		state := &states[i]
+		block := state.block
+		table := models.Table{
+			ID:          newElementID("table"),
+			Name:        block.Name,
+			Namespace:   prismaNamespace(block.Attributes),
+			Description: block.Doc,
+		}
+		if name := prismaStringAttribute(block.Attributes, "map"); name != "" {
+			table.Name = name
+		}
+		state.table = len(request.Tables)
+		modelIndex[block.Name] = i
+		request.Tables = append(request.Tables, table)
+	}
+
+	// Scalar fields first, so that block attributes and relations can refer
+	// to any column by its Prisma field name.
+	for i := range states {
+		state := &states[i]
+		table := &request.Tables[state.table]
+		for _, def := range state.block.Fields {
+			if _, ok := modelIndex[def.Type]; ok {
+				continue
+			}
+			field, err := prismaScalarField(request, provider, table, &def, enums, enumValues)
+			if err != nil {
+				return nil, err
+			}
+			state.fields[def.Name] = len(table.Fields)
+			table.Fields = append(table.Fields, *field)
+		}
+	}
+
+	for i := range states {
+		state := &states[i]
+		table := &request.Tables[state.table]
+		columns := func(value *prismaValue, line int) ([]*models.Field, []models.IndexColumn, error) {
+			if value == nil || value.Kind != prismaValueList {
+				return nil, nil, fmt.Errorf("line %d: expected a list of fields", line)
+			}
+			var fields []*models.Field
+			var indexColumns []models.IndexColumn
+			for _, item := range value.Items {
+				index, ok := state.fields[item.Text]
+				if !ok {
+					return nil, nil, fmt.Errorf("line %d: unknown field %q in model %q", line, item.Text, state.block.Name)
+				}
+				column := models.IndexColumn{FieldID: table.Fields[index].ID}
+				if item.Kind == prismaValueCall {
+					attribute := prismaAttribute{Args: item.Args}
+					if sort := attribute.arg("sort", -1); sort != nil && strings.EqualFold(sort.Text, "desc") {
+						column.Order = "desc"
+					}
+				}
+				fields = append(fields, &table.Fields[index])
+				indexColumns = append(indexColumns, column)
+			}
+			return fields, indexColumns, nil
+		}
+
+		for _, attribute := range state.block.Attributes {
+			name := ""
+			if value := attribute.arg("map", -1); value != nil {
+				name = value.Text
+			} else if value := attribute.arg("name", -1); value != nil {
+				name = value.Text
+			}
+
+			switch attribute.Name {
+			case "id":
+				fields, _, err := columns(attribute.arg("fields", 0), attribute.Line)
+				if err != nil {
+					return nil, err
+				}
+				table.PrimaryKey = nil
+				for _, field := range fields {
+					field.IsPrimaryKey = true
+					field.IsNotNull = true
+					table.PrimaryKey = append(table.PrimaryKey, field.ID)
+				}
+			case "unique":
+				fields, _, err := columns(attribute.arg("fields", 0), attribute.Line)
+				if err != nil {
+					return nil, err
+				}
+				constraint := models.Constraint{Name: name, Type: ConstraintUnique}
+				for _, field := range fields {
+					constraint.Fields = append(constraint.Fields, field.ID)
+				}
+				table.Constraints = append(table.Constraints, constraint)
+			case "index", "fulltext":
+				fields, indexColumns, err := columns(attribute.arg("fields", 0), attribute.Line)
+				if err != nil {
+					return nil, err
+				}
+				index := models.Index{Name: name}
+				if attribute.Name == "fulltext" {
+					index.Type = IndexMethodFullText
+				} else if method := attribute.arg("type", -1); method != nil {
+					index.Type = strings.ToLower(method.Text)
+				}
+				ordered := false
+				for _, column := range indexColumns {
+					ordered = ordered || column.Order != ""
+				}
+				if ordered {
+					index.Columns = indexColumns
+				} else {
+					for _, field := range fields {
+						index.Fields = append(index.Fields, field.ID)
+					}
+				}
+				table.Indexes = append(table.Indexes, index)
+			}
+		}
+	}
+
+	for i := range states {
+		state := &states[i]
+		for _, def := range state.block.Fields {
+			target, ok := modelIndex[def.Type]
+			if !ok {
+				continue
+			}
+			relation := findPrismaAttribute(def.Attributes, "relation")
+			if relation == nil || relation.arg("fields", -1) == nil {
+				continue
+			}
+			if err := resolvePrismaRelation(request, &states[i], &states[target], relation); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	request.Namespaces = collectNamespaces(request.Tables, request.Enums)
+	arrangeTables(request.Tables)
+	return request, nil
+}
+
+func prismaScalarField(request *models.CreateSchemaRequest, provider string, table *models.Table, def *prismaFieldDef, enums map[string]int, enumValues map[string]map[string]string) (*models.Field, error) {
+	field := &models.Field{
+		ID:        newElementID("f"),
+		Name:      def.Name,
+		IsNotNull: !def.Optional && !def.List,
+		Comment:   def.Doc,
+	}
+	if name := prismaStringAttribute(def.Attributes, "map"); name != "" {
+		field.Name = name
+	}
+
+	var native *prismaAttribute
+	for i := range def.Attributes {
+		if strings.HasPrefix(def.Attributes[i].Name, "db.") {
+			native = &def.Attributes[i]
+		}
+	}
+
+	_, isEnum := enums[def.Type]
+	switch {
+	case isEnum:
+		enum := request.Enums[enums[def.Type]]
+		field.Type = enum.Name
+		if enum.Namespace != "" && enum.Namespace != table.Namespace {
+			field.Type = QualifiedName(enum.Namespace, enum.Name)
+		}
+	case strings.HasPrefix(def.Type, "Unsupported:"):
+		field.Type = strings.TrimPrefix(def.Type, "Unsupported:")
+	case native != nil:
+		nativeName := strings.TrimPrefix(native.Name, "db.")
+		sqlType, ok := prismaNativeTypes[provider][nativeName]
+		if !ok {
+			sqlType = strings.ToUpper(nativeName)
+		}
+		field.Type = sqlType
+		var args []string
+		for _, arg := range native.Args {
+			args = append(args, arg.Value.Text)
+		}
+		numbers := make([]int, 0, len(args))
+		for _, arg := range args {
+			if n, err := strconv.Atoi(arg); err == nil {
+				numbers = append(numbers, n)
+			}
+		}
+		switch {
+		case lengthTypes[sqlType] && len(numbers) == 1 && len(args) == 1:
+			field.Length = numbers[0]
+		case decimalTypes[sqlType] && len(args) >= 1 && len(args) <= 2 && len(numbers) == len(args):
+			field.Precision = numbers[0]
+			if len(numbers) == 2 {
+				field.Scale = numbers[1]
+			}
+		case len(args) > 0:
+			field.Type += "(" + strings.Join(args, ",") + ")"
+		}
+	default:
+		sqlType, ok := prismaDefaultTypes[provider][def.Type]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown type %q for field %q", def.Line, def.Type, def.Name)
+		}
+		field.Type = sqlType
+	}
+	if def.List {
+		field.Type += "[]"
+	}
+
+	if findPrismaAttribute(def.Attributes, "id") != nil {
+		field.IsPrimaryKey = true
+		field.IsNotNull = true
+	}
+	if findPrismaAttribute(def.Attributes, "unique") != nil {
+		field.IsUnique = true
+	}
+
+	if attribute := findPrismaAttribute(def.Attributes, "default"); attribute != nil {
+		value := attribute.arg("value", 0)
+		if value == nil {
+			return nil, fmt.Errorf("line %d: @default of field %q needs a value", def.Line, def.Name)
+		}
+		switch {
+		case value.Kind == prismaValueCall && (value.Text == "autoincrement" || value.Text == "sequence"):
+			switch field.Type {
+			case "BIGINT":
+				field.Type = "BIGSERIAL"
+			case "SMALLINT":
+				field.Type = "SMALLSERIAL"
+			default:
+				field.Type = "SERIAL"
+			}
+		case value.Kind == prismaValueCall && value.Text == "now":
+			field.DefaultValue = "CURRENT_TIMESTAMP"
+		case value.Kind == prismaValueCall && value.Text == "dbgenerated":
+			if len(value.Args) > 0 {
+				field.DefaultValue = value.Args[0].Value.Text
+			}
+		case value.Kind == prismaValueCall:
+			field.DefaultValue = value.Text + "()"
+		case value.Kind == prismaValueString:
+			if numericLiteralPattern.MatchString(value.Text) || sqlKeywordDefaults[strings.ToUpper(value.Text)] {
+				field.DefaultValue = sqlString(value.Text)
+			} else {
+				field.DefaultValue = value.Text
+			}
+		case value.Kind == prismaValueIdent && isEnum:
+			field.DefaultValue = enumValues[def.Type][value.Text]
+		case value.Kind == prismaValueIdent:
+			field.DefaultValue = strings.ToUpper(value.Text)
+		}
+	}
+	return field, nil
+}
+
+// resolvePrismaRelation turns the @relation(fields, references) side of a
+// relation into a foreign key. A plain single-column relation becomes a
+// field reference, anything named or carrying referential actions a
+// constraint.
+func resolvePrismaRelation(request *models.CreateSchemaRequest, from, to *prismaModelState, relation *prismaAttribute) error {
+	fromTable := &request.Tables[from.table]
+	toTable := &request.Tables[to.table]
+	lookup := func(state *prismaModelState, table *models.Table, value *prismaValue) ([]*models.Field, error) {
+		if value == nil || value.Kind != prismaValueList {
+			return nil, fmt.Errorf("line %d: @relation needs fields and references lists", relation.Line)
+		}
+		fields := make([]*models.Field, len(value.Items))
+		for i, item := range value.Items {
+			index, ok := state.fields[item.Text]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown field %q in model %q", relation.Line, item.Text, state.block.Name)
+			}
+			fields[i] = &table.Fields[index]
+		}
+		return fields, nil
+	}
+
+	fromFields, err := lookup(from, fromTable, relation.arg("fields", -1))
+	if err != nil {
+		return err
+	}
+	toFields, err := lookup(to, toTable, relation.arg("references", -1))
+	if err != nil {
+		return err
+	}
+	if len(fromFields) != len(toFields) {
+		return fmt.Errorf("line %d: relation fields and references do not match in number", relation.Line)
+	}
+
+	action := func(name string) string {
+		if value := relation.arg(name, -1); value != nil {
+			for sql, prisma := range prismaReferentialActions {
+				if prisma == value.Text {
+					return sql
+				}
+			}
+		}
+		return ""
+	}
+	name := ""
+	if value := relation.arg("map", -1); value != nil {
+		name = value.Text
+	}
+	onDelete, onUpdate := action("onDelete"), action("onUpdate")
+
+	if len(fromFields) == 1 && name == "" && onDelete == "" && onUpdate == "" && fromFields[0].References == nil {
+		fromFields[0].IsForeignKey = true
+		fromFields[0].References = &models.Reference{Namespace: toTable.Namespace, TableID: toTable.ID, FieldID: toFields[0].ID}
+		return nil
+	}
+
+	constraint := models.Constraint{
+		Name:               name,
+		Type:               ConstraintForeignKey,
+		ReferenceNamespace: toTable.Namespace,
+		ReferenceTable:     toTable.ID,
+		OnDelete:           onDelete,
+		OnUpdate:           onUpdate,
+	}
+	for i := range fromFields {
+		fromFields[i].IsForeignKey = true
+		constraint.Fields = append(constraint.Fields, fromFields[i].ID)
+		constraint.ReferenceFields = append(constraint.ReferenceFields, toFields[i].ID)
+	}
+	fromTable.Constraints = append(fromTable.Constraints, constraint)
+	return nil
+}
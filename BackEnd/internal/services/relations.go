@@ -0,0 +1,140 @@
+package services
+
+import (
+	"schema-builder-backend/internal/models"
+)
+
+// schemaRelation is a resolved foreign key, whether it was drawn on a field
+// through Field.References or declared as a foreign key constraint. Constraint
+// is nil for field references.
+type schemaRelation struct {
+	Name       string
+	Constraint *models.Constraint
+	From       *models.Table
+	FromFields []*models.Field
+	To         *models.Table
+	ToFields   []*models.Field
+	OnDelete   string
+	OnUpdate   string
+}
+
+// IsOneToOne reports whether each row of the target is referenced at most
+// once, which holds when the referencing columns are themselves a key.
+func (r *schemaRelation) IsOneToOne() bool {
+	return isCandidateKey(r.From, r.FromFields)
+}
+
+// IsOptional reports whether a referencing row may exist without a target.
+func (r *schemaRelation) IsOptional() bool {
+	for _, field := range r.FromFields {
+		if !field.IsNotNull && !field.IsPrimaryKey {
+			return true
+		}
+	}
+	return false
+}
+
+func collectRelations(schema *models.Schema) []schemaRelation {
+	tablesByID := make(map[string]*models.Table, len(schema.Tables))
+	for i := range schema.Tables {
+		tablesByID[schema.Tables[i].ID] = &schema.Tables[i]
+	}
+
+	var relations []schemaRelation
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+
+		for j := range table.Fields {
+			field := &table.Fields[j]
+			if field.References == nil {
+				continue
+			}
+			target, ok := tablesByID[field.References.TableID]
+			if !ok {
+				continue
+			}
+			targetField := findField(target, field.References.FieldID)
+			if targetField == nil {
+				continue
+			}
+			relations = append(relations, schemaRelation{
+				Name:       "fk_" + table.Name + "_" + field.Name,
+				From:       table,
+				FromFields: []*models.Field{field},
+				To:         target,
+				ToFields:   []*models.Field{targetField},
+			})
+		}
+
+		for j := range table.Constraints {
+			constraint := &table.Constraints[j]
+			if constraintKind(constraint) != ConstraintForeignKey {
+				continue
+			}
+			fields := constraintFields(table, constraint)
+			target := findConstraintTarget(schema, constraint)
+			if len(fields) == 0 || target == nil {
+				continue
+			}
+			targetFields := constraintReferenceFields(target, constraint)
+			if len(targetFields) != len(fields) {
+				continue
+			}
+			name := constraint.Name
+			if name == "" {
+				name = "fk_" + table.Name + "_" + target.Name
+			}
+			relations = append(relations, schemaRelation{
+				Name:       name,
+				Constraint: constraint,
+				From:       table,
+				FromFields: fields,
+				To:         target,
+				ToFields:   targetFields,
+				OnDelete:   constraint.OnDelete,
+				OnUpdate:   constraint.OnUpdate,
+			})
+		}
+	}
+
+	return relations
+}
+
+// fieldKeyMarkers maps field IDs of the table to their PK, UQ and FK roles.
+func fieldKeyMarkers(table *models.Table, relations []schemaRelation) map[string][]string {
+	markers := make(map[string][]string)
+	add := func(field *models.Field, marker string) {
+		for _, existing := range markers[field.ID] {
+			if existing == marker {
+				return
+			}
+		}
+		markers[field.ID] = append(markers[field.ID], marker)
+	}
+
+	for _, field := range primaryKeyFields(table) {
+		add(field, "PK")
+	}
+	for i := range table.Fields {
+		if table.Fields[i].IsUnique && !table.Fields[i].IsPrimaryKey {
+			add(&table.Fields[i], "UQ")
+		}
+	}
+	for _, relation := range relations {
+		if relation.From != table {
+			continue
+		}
+		for _, field := range relation.FromFields {
+			add(field, "FK")
+		}
+	}
+	return markers
+}
+
+func fieldNames(fields []*models.Field) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
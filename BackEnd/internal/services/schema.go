@@ -13,31 +13,55 @@ import (
 )
 
 type SchemaService struct {
-	schemaRepo repository.SchemaRepository
-	userRepo   repository.UserRepository
-	log        *logrus.Logger
+	schemaRepo  repository.SchemaRepository
+	versionRepo repository.SchemaVersionRepository
+	branchRepo  repository.SchemaBranchRepository
+	changeRepo  repository.ChangeRequestRepository
+	commentRepo repository.CommentThreadRepository
+	shareRepo   repository.ShareLinkRepository
+	starRepo    repository.SchemaStarRepository
+	userRepo    repository.UserRepository
+	log         *logrus.Logger
 }
 
-func NewSchemaService(schemaRepo repository.SchemaRepository, userRepo repository.UserRepository) *SchemaService {
+func NewSchemaService(schemaRepo repository.SchemaRepository, versionRepo repository.SchemaVersionRepository, branchRepo repository.SchemaBranchRepository, changeRepo repository.ChangeRequestRepository, commentRepo repository.CommentThreadRepository, shareRepo repository.ShareLinkRepository, starRepo repository.SchemaStarRepository, userRepo repository.UserRepository) *SchemaService {
 	return &SchemaService{
-		schemaRepo: schemaRepo,
-		userRepo:   userRepo,
-		log:        logger.GetLogger(),
+		schemaRepo:  schemaRepo,
+		versionRepo: versionRepo,
+		branchRepo:  branchRepo,
+		changeRepo:  changeRepo,
+		commentRepo: commentRepo,
+		shareRepo:   shareRepo,
+		starRepo:    starRepo,
+		userRepo:    userRepo,
+		log:         logger.GetLogger(),
 	}
 }
 
 func (s *SchemaService) CreateSchema(ctx context.Context, userID primitive.ObjectID, req *models.CreateSchemaRequest) (*models.Schema, error) {
+	return s.createSchema(ctx, userID, &models.Schema{
+		UserID:       userID,
+		Name:         req.Name,
+		Description:  req.Description,
+		DatabaseType: req.DatabaseType,
+		Namespaces:   req.Namespaces,
+		Tables:       req.Tables,
+		Enums:        req.Enums,
+		Views:        req.Views,
+		IsPublic:     req.IsPublic,
+	})
+}
+
+func (s *SchemaService) createSchema(ctx context.Context, userID primitive.ObjectID, schema *models.Schema) (*models.Schema, error) {
 	_, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %v", err)
 	}
 
-	schema := &models.Schema{
-		UserID:      userID,
-		Name:        req.Name,
-		Description: req.Description,
-		Tables:      req.Tables,
-		IsPublic:    req.IsPublic,
+	normalizeKeys(schema.Tables)
+	normalizeReferences(schema.Tables)
+	if err := ValidateSchemaDefinition(schema); err != nil {
+		return nil, err
 	}
 
 	if err := s.schemaRepo.Create(ctx, schema); err != nil {
@@ -45,6 +69,8 @@ func (s *SchemaService) CreateSchema(ctx context.Context, userID primitive.Objec
 		return nil, fmt.Errorf("failed to create schema: %v", err)
 	}
 
+	s.recordVersion(ctx, schema, userID)
+
 	s.log.Infof("Schema created successfully: %s for user: %s", schema.ID.Hex(), userID.Hex())
 	return schema, nil
 }
@@ -62,7 +88,7 @@ func (s *SchemaService) GetSchemaByID(ctx context.Context, id primitive.ObjectID
 	return schema, nil
 }
 
-func (s *SchemaService) GetUserSchemas(ctx context.Context, userID primitive.ObjectID, page, limit int) ([]*models.Schema, int64, error) {
+func (s *SchemaService) GetUserSchemas(ctx context.Context, userID primitive.ObjectID, page, limit int, filter repository.SchemaListFilter) ([]*models.Schema, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -70,7 +96,7 @@ func (s *SchemaService) GetUserSchemas(ctx context.Context, userID primitive.Obj
 		limit = 10
 	}
 
-	schemas, total, err := s.schemaRepo.GetByUserID(ctx, userID, page, limit)
+	schemas, total, err := s.schemaRepo.GetByUserID(ctx, userID, page, limit, filter)
 	if err != nil {
 		s.log.Errorf("Failed to get user schemas: %v", err)
 		return nil, 0, fmt.Errorf("failed to get user schemas: %v", err)
@@ -79,7 +105,7 @@ func (s *SchemaService) GetUserSchemas(ctx context.Context, userID primitive.Obj
 	return schemas, total, nil
 }
 
-func (s *SchemaService) GetPublicSchemas(ctx context.Context, page, limit int) ([]*models.Schema, int64, error) {
+func (s *SchemaService) GetPublicSchemas(ctx context.Context, page, limit int, sort string) ([]*models.Schema, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -87,7 +113,7 @@ func (s *SchemaService) GetPublicSchemas(ctx context.Context, page, limit int) (
 		limit = 10
 	}
 
-	schemas, total, err := s.schemaRepo.GetPublicSchemas(ctx, page, limit)
+	schemas, total, err := s.schemaRepo.GetPublicSchemas(ctx, page, limit, sort)
 	if err != nil {
 		s.log.Errorf("Failed to get public schemas: %v", err)
 		return nil, 0, fmt.Errorf("failed to get public schemas: %v", err)
@@ -123,6 +149,30 @@ func (s *SchemaService) UpdateSchema(ctx context.Context, id primitive.ObjectID,
 		return nil, fmt.Errorf("access denied: you can only update your own schemas")
 	}
 
+	if req.DatabaseType != "" || req.Namespaces != nil || req.Tables != nil || req.Enums != nil || req.Views != nil {
+		candidate := *schema
+		if req.DatabaseType != "" {
+			candidate.DatabaseType = req.DatabaseType
+		}
+		if req.Namespaces != nil {
+			candidate.Namespaces = req.Namespaces
+		}
+		if req.Tables != nil {
+			candidate.Tables = req.Tables
+		}
+		if req.Enums != nil {
+			candidate.Enums = req.Enums
+		}
+		if req.Views != nil {
+			candidate.Views = req.Views
+		}
+		normalizeKeys(candidate.Tables)
+		normalizeReferences(candidate.Tables)
+		if err := ValidateSchemaDefinition(&candidate); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.schemaRepo.Update(ctx, id, req); err != nil {
 		s.log.Errorf("Failed to update schema: %v", err)
 		return nil, fmt.Errorf("failed to update schema: %v", err)
@@ -133,6 +183,10 @@ func (s *SchemaService) UpdateSchema(ctx context.Context, id primitive.ObjectID,
 		return schema, nil
 	}
 
+	if updatedSchema.Version != schema.Version {
+		s.recordVersion(ctx, updatedSchema, userID)
+	}
+
 	s.log.Infof("Schema updated successfully: %s", id.Hex())
 	return updatedSchema, nil
 }
@@ -147,12 +201,63 @@ func (s *SchemaService) DeleteSchema(ctx context.Context, id primitive.ObjectID,
 		return fmt.Errorf("access denied: you can only delete your own schemas")
 	}
 
+	if err := s.deleteSchema(ctx, schema); err != nil {
+		return err
+	}
+
+	s.log.Infof("Schema deleted successfully: %s", id.Hex())
+	return nil
+}
+
+// deleteSchema deletes a schema with everything recorded for it, including
+// the schemas of its branches.
+func (s *SchemaService) deleteSchema(ctx context.Context, schema *models.Schema) error {
+	id := schema.ID
+	branches, err := s.branchRepo.ListBySchema(ctx, id)
+	if err != nil {
+		s.log.Errorf("Failed to list branches of schema %s: %v", id.Hex(), err)
+	}
+
 	if err := s.schemaRepo.Delete(ctx, id); err != nil {
 		s.log.Errorf("Failed to delete schema: %v", err)
 		return fmt.Errorf("failed to delete schema: %v", err)
 	}
 
-	s.log.Infof("Schema deleted successfully: %s", id.Hex())
+	for _, branch := range branches {
+		branchSchema, err := s.schemaRepo.GetByID(ctx, branch.BranchSchemaID)
+		if err != nil {
+			// Already deleted on its own.
+			continue
+		}
+		if err := s.deleteSchema(ctx, branchSchema); err != nil {
+			s.log.Errorf("Failed to delete branch schema %s: %v", branch.BranchSchemaID.Hex(), err)
+		}
+	}
+
+	if err := s.versionRepo.DeleteBySchema(ctx, id); err != nil {
+		s.log.Errorf("Failed to delete versions of schema %s: %v", id.Hex(), err)
+	}
+	if err := s.branchRepo.DeleteBySchema(ctx, id); err != nil {
+		s.log.Errorf("Failed to delete branches of schema %s: %v", id.Hex(), err)
+	}
+	if err := s.changeRepo.DeleteBySchema(ctx, id); err != nil {
+		s.log.Errorf("Failed to delete change requests of schema %s: %v", id.Hex(), err)
+	}
+	if err := s.commentRepo.DeleteBySchema(ctx, id); err != nil {
+		s.log.Errorf("Failed to delete comment threads of schema %s: %v", id.Hex(), err)
+	}
+	if err := s.shareRepo.DeleteBySchema(ctx, id); err != nil {
+		s.log.Errorf("Failed to delete share links of schema %s: %v", id.Hex(), err)
+	}
+	if err := s.starRepo.DeleteBySchema(ctx, id); err != nil {
+		s.log.Errorf("Failed to delete stars of schema %s: %v", id.Hex(), err)
+	}
+	if schema.ForkedFrom != nil {
+		if err := s.schemaRepo.AdjustCount(ctx, schema.ForkedFrom.SchemaID, "fork_count", -1); err != nil {
+			s.log.Errorf("Failed to update fork count of schema %s: %v", schema.ForkedFrom.SchemaID.Hex(), err)
+		}
+	}
+
 	return nil
 }
 
@@ -163,10 +268,14 @@ func (s *SchemaService) DuplicateSchema(ctx context.Context, id primitive.Object
 	}
 
 	duplicateReq := &models.CreateSchemaRequest{
-		Name:        newName,
-		Description: fmt.Sprintf("Copy of %s", originalSchema.Name),
-		Tables:      originalSchema.Tables,
-		IsPublic:    false,
+		Name:         newName,
+		Description:  fmt.Sprintf("Copy of %s", originalSchema.Name),
+		DatabaseType: originalSchema.DatabaseType,
+		Namespaces:   originalSchema.Namespaces,
+		Tables:       originalSchema.Tables,
+		Enums:        originalSchema.Enums,
+		Views:        originalSchema.Views,
+		IsPublic:     false,
 	}
 
 	return s.CreateSchema(ctx, userID, duplicateReq)
@@ -189,3 +298,26 @@ func (s *SchemaService) ToggleSchemaVisibility(ctx context.Context, id primitive
 
 	return s.UpdateSchema(ctx, id, userID, updateReq)
 }
+
+func (s *SchemaService) MigrateKeyDefinitions(ctx context.Context) (int, error) {
+	migrated := 0
+
+	err := s.schemaRepo.ForEach(ctx, func(schema *models.Schema) error {
+		if !normalizeKeys(schema.Tables) {
+			return nil
+		}
+		if err := s.schemaRepo.ReplaceTables(ctx, schema.ID, schema.Tables); err != nil {
+			return err
+		}
+		migrated++
+		return nil
+	})
+	if err != nil {
+		return migrated, fmt.Errorf("failed to migrate key definitions: %v", err)
+	}
+
+	if migrated > 0 {
+		s.log.Infof("Migrated key definitions of %d schemas", migrated)
+	}
+	return migrated, nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// collationPattern matches collation names such as utf8mb4_bin, en_US.utf8
+// and en-US-x-icu. They cannot hold quotes, spaces or semicolons, as MySQL
+// takes them unquoted.
+var collationPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.@-]*$`)
+
+type SchemaValidationError struct {
+	Issues []string
+}
+
+func (e *SchemaValidationError) Error() string {
+	return fmt.Sprintf("invalid schema: %s", strings.Join(e.Issues, "; "))
+}
+
+func ValidateSchemaDefinition(schema *models.Schema) error {
+	var issues []string
+	addIssue := func(format string, args ...interface{}) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	declared := make(map[string]bool)
+	for _, namespace := range schema.Namespaces {
+		if !identifierPattern.MatchString(namespace.Name) {
+			addIssue("namespace %q is not a valid identifier", namespace.Name)
+		}
+		if declared[namespace.Name] {
+			addIssue("namespace %q is declared more than once", namespace.Name)
+		}
+		declared[namespace.Name] = true
+	}
+
+	checkNamespace := func(kind, name, namespace string) {
+		if namespace != "" && !identifierPattern.MatchString(namespace) {
+			addIssue("%s %q has invalid namespace %q", kind, name, namespace)
+		}
+	}
+
+	relations := make(map[string]string)
+	claimName := func(kind, namespace, name string) {
+		key := strings.ToLower(QualifiedName(namespace, name))
+		if existing, ok := relations[key]; ok {
+			addIssue("%s %q conflicts with %s of the same name", kind, QualifiedName(namespace, name), existing)
+			return
+		}
+		relations[key] = kind
+	}
+
+	tablesByID := make(map[string]*models.Table, len(schema.Tables))
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		if strings.TrimSpace(table.Name) == "" {
+			addIssue("table %q has no name", table.ID)
+			continue
+		}
+		checkNamespace("table", table.Name, table.Namespace)
+		claimName("table", table.Namespace, table.Name)
+		tablesByID[table.ID] = table
+	}
+
+	for _, view := range schema.Views {
+		checkNamespace("view", view.Name, view.Namespace)
+		claimName("view", view.Namespace, view.Name)
+	}
+
+	types := make(map[string]bool)
+	for _, enum := range schema.Enums {
+		checkNamespace("enum", enum.Name, enum.Namespace)
+		key := strings.ToLower(QualifiedName(enum.Namespace, enum.Name))
+		if types[key] {
+			addIssue("enum %q is declared more than once", QualifiedName(enum.Namespace, enum.Name))
+		}
+		types[key] = true
+		if len(enum.Values) == 0 {
+			addIssue("enum %q has no values", QualifiedName(enum.Namespace, enum.Name))
+		}
+	}
+
+	for _, table := range schema.Tables {
+		for _, field := range table.Fields {
+			ref := field.References
+			if ref == nil {
+				continue
+			}
+			target, ok := tablesByID[ref.TableID]
+			if !ok {
+				continue
+			}
+			if ref.Namespace != "" && ref.Namespace != target.Namespace {
+				addIssue("field %q references %q but declares namespace %q",
+					QualifiedName(table.Namespace, table.Name)+"."+field.Name,
+					QualifiedName(target.Namespace, target.Name),
+					ref.Namespace)
+			}
+		}
+	}
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		tableName := QualifiedName(table.Namespace, table.Name)
+
+		seen := make(map[string]bool, len(table.PrimaryKey))
+		for _, ref := range table.PrimaryKey {
+			field := findField(table, ref)
+			if field == nil {
+				addIssue("primary key of %q references unknown field %q", tableName, ref)
+				continue
+			}
+			if seen[field.ID] {
+				addIssue("primary key of %q lists field %q more than once", tableName, field.Name)
+			}
+			seen[field.ID] = true
+		}
+
+		validateIndexes(schema, table, addIssue)
+		validateTableOptions(schema, table, addIssue)
+
+		for j := range table.Constraints {
+			constraint := &table.Constraints[j]
+			kind := constraintKind(constraint)
+			if kind != ConstraintUnique && kind != ConstraintForeignKey {
+				continue
+			}
+
+			label := constraint.Name
+			if label == "" {
+				label = kind
+			}
+
+			fields := constraintFields(table, constraint)
+			if len(fields) == 0 {
+				addIssue("constraint %q on %q must list existing fields", label, tableName)
+				continue
+			}
+			if kind != ConstraintForeignKey {
+				continue
+			}
+
+			target := findConstraintTarget(schema, constraint)
+			if target == nil {
+				addIssue("foreign key %q on %q references unknown table %q", label, tableName, constraint.ReferenceTable)
+				continue
+			}
+			if constraint.ReferenceNamespace != "" && constraint.ReferenceNamespace != target.Namespace {
+				addIssue("foreign key %q on %q declares namespace %q but %q is in %q",
+					label, tableName, constraint.ReferenceNamespace, target.Name, target.Namespace)
+			}
+			targetFields := constraintReferenceFields(target, constraint)
+			if len(targetFields) != len(fields) {
+				addIssue("foreign key %q on %q has %d columns but references %d existing columns of %q",
+					label, tableName, len(fields), len(targetFields), QualifiedName(target.Namespace, target.Name))
+				continue
+			}
+			if !isCandidateKey(target, targetFields) {
+				addIssue("foreign key %q on %q must reference the primary key or a unique key of %q",
+					label, tableName, QualifiedName(target.Namespace, target.Name))
+			}
+		}
+	}
+
+	validateSQLFragments(schema, addIssue)
+
+	if len(issues) > 0 {
+		return &SchemaValidationError{Issues: issues}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+)
+
+func versionSnapshot(schema *models.Schema, userID primitive.ObjectID) *models.SchemaVersion {
+	return &models.SchemaVersion{
+		SchemaID:     schema.ID,
+		Version:      schema.Version,
+		DatabaseType: schema.DatabaseType,
+		Namespaces:   schema.Namespaces,
+		Tables:       schema.Tables,
+		Enums:        schema.Enums,
+		Views:        schema.Views,
+		CreatedBy:    userID,
+		CreatedAt:    schema.UpdatedAt,
+	}
+}
+
+// recordVersion stores a snapshot of the schema as it is now. Failures are
+// logged only: the schema itself has already been saved.
+func (s *SchemaService) recordVersion(ctx context.Context, schema *models.Schema, userID primitive.ObjectID) {
+	if err := s.versionRepo.Save(ctx, versionSnapshot(schema, userID)); err != nil {
+		s.log.Errorf("Failed to record version %d of schema %s: %v", schema.Version, schema.ID.Hex(), err)
+	}
+}
+
+// ListSchemaVersions returns the recorded versions, newest first. Schemas
+// saved before versions were recorded list their current version only.
+func (s *SchemaService) ListSchemaVersions(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) ([]*models.SchemaVersion, error) {
+	schema, err := s.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.versionRepo.List(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema versions: %v", err)
+	}
+
+	if len(versions) == 0 || versions[0].Version != schema.Version {
+		current := versionSnapshot(schema, schema.UserID)
+		current.Namespaces, current.Tables, current.Enums, current.Views = nil, nil, nil, nil
+		versions = append([]*models.SchemaVersion{current}, versions...)
+	}
+
+	return versions, nil
+}
+
+// GetSchemaVersion returns the definition of a schema at a version. The
+// current version always resolves, recorded or not.
+func (s *SchemaService) GetSchemaVersion(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, version int) (*models.SchemaVersion, error) {
+	schema, err := s.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.schemaVersion(ctx, schema, version)
+}
+
+func (s *SchemaService) schemaVersion(ctx context.Context, schema *models.Schema, version int) (*models.SchemaVersion, error) {
+	if version == schema.Version {
+		return versionSnapshot(schema, schema.UserID), nil
+	}
+
+	snapshot, err := s.versionRepo.Get(ctx, schema.ID, version)
+	if err != nil {
+		return nil, fmt.Errorf("schema version not found: %v", err)
+	}
+
+	return snapshot, nil
+}
+
+// versionSchema returns the schema as it was at the snapshot.
+func versionSchema(schema *models.Schema, snapshot *models.SchemaVersion) *models.Schema {
+	versioned := *schema
+	versioned.Version = snapshot.Version
+	versioned.DatabaseType = snapshot.DatabaseType
+	versioned.Namespaces = snapshot.Namespaces
+	versioned.Tables = snapshot.Tables
+	versioned.Enums = snapshot.Enums
+	versioned.Views = snapshot.Views
+	return &versioned
+}
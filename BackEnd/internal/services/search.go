@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/repository"
+)
+
+// SchemaSearchResult summarizes a matching schema. MatchedTables and
+// MatchedFields list the tables and table.field names the search terms
+// appear in.
+type SchemaSearchResult struct {
+	ID            primitive.ObjectID `json:"id"`
+	UserID        primitive.ObjectID `json:"user_id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description,omitempty"`
+	DatabaseType  string             `json:"database_type,omitempty"`
+	IsPublic      bool               `json:"is_public"`
+	ForkedFrom    *models.ForkSource `json:"forked_from,omitempty"`
+	StarCount     int                `json:"star_count"`
+	ForkCount     int                `json:"fork_count"`
+	TableCount    int                `json:"table_count"`
+	MatchedTables []string           `json:"matched_tables,omitempty"`
+	MatchedFields []string           `json:"matched_fields,omitempty"`
+	Score         float64            `json:"score,omitempty"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// EnsureSearchIndex creates the index SearchSchemas needs.
+func (s *SchemaService) EnsureSearchIndex(ctx context.Context) error {
+	return s.schemaRepo.EnsureSearchIndex(ctx)
+}
+
+// SearchSchemas searches the public schemas and the user's own.
+func (s *SchemaService) SearchSchemas(ctx context.Context, userID primitive.ObjectID, req *models.SearchSchemasRequest) ([]*SchemaSearchResult, int64, error) {
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	query := repository.SchemaSearchQuery{
+		Text:      strings.TrimSpace(req.Query),
+		ViewerID:  userID,
+		MinTables: req.MinTables,
+		MaxTables: req.MaxTables,
+		HasTable:  strings.TrimSpace(req.HasTable),
+	}
+	switch req.Owner {
+	case "":
+	case "me":
+		query.OwnerID = &userID
+	default:
+		owner, err := s.userRepo.GetByUsername(ctx, req.Owner)
+		if err != nil {
+			return []*SchemaSearchResult{}, 0, nil
+		}
+		query.OwnerID = &owner.ID
+	}
+
+	hits, total, err := s.schemaRepo.Search(ctx, query, page, limit)
+	if err != nil {
+		s.log.Errorf("Failed to search schemas: %v", err)
+		return nil, 0, fmt.Errorf("failed to search schemas: %v", err)
+	}
+
+	terms := searchTerms(query.Text)
+	results := make([]*SchemaSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		schema := hit.Schema
+		result := &SchemaSearchResult{
+			ID:           schema.ID,
+			UserID:       schema.UserID,
+			Name:         schema.Name,
+			Description:  schema.Description,
+			DatabaseType: schema.DatabaseType,
+			IsPublic:     schema.IsPublic,
+			ForkedFrom:   schema.ForkedFrom,
+			StarCount:    schema.StarCount,
+			ForkCount:    schema.ForkCount,
+			TableCount:   len(schema.Tables),
+			Score:        hit.Score,
+			UpdatedAt:    schema.UpdatedAt,
+		}
+		for _, table := range schema.Tables {
+			if matchesSearch(terms, table.Name, table.Description) {
+				result.MatchedTables = append(result.MatchedTables, table.Name)
+			}
+			for _, field := range table.Fields {
+				if matchesSearch(terms, field.Name, field.Comment) {
+					result.MatchedFields = append(result.MatchedFields, table.Name+"."+field.Name)
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, total, nil
+}
+
+// searchTerms splits a text search into the lower-cased words it looks for,
+// leaving out negated words.
+func searchTerms(text string) []string {
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(strings.ReplaceAll(text, `"`, " "))) {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// matchesSearch approximates the index's stemming by also matching singular
+// forms, so "orders" finds an order table.
+func matchesSearch(terms []string, values ...string) bool {
+	for _, value := range values {
+		value = strings.ToLower(value)
+		if value == "" {
+			continue
+		}
+		for _, term := range terms {
+			if strings.Contains(value, term) || (len(term) > 3 && strings.Contains(value, strings.TrimSuffix(term, "s"))) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,1043 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	seedDefaultRows  = 10
+	seedMaxRows      = 10000
+	seedMaxTotalRows = 100000
+	seedBatchSize    = 100
+	seedNullPercent  = 10
+	seedRowAttempts  = 20
+)
+
+// Generated dates fall in a fixed window so output does not depend on the
+// day it was generated.
+var (
+	seedEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	seedSpan  = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Sub(seedEpoch)
+)
+
+var seedIntegerMax = map[string]int64{
+	"TINYINT": math.MaxInt8, "SMALLINT": math.MaxInt16, "INT2": math.MaxInt16, "SMALLSERIAL": math.MaxInt16,
+	"MEDIUMINT": 1<<23 - 1, "INTEGER": math.MaxInt32, "INT": math.MaxInt32, "INT4": math.MaxInt32,
+	"SERIAL": math.MaxInt32, "BIGINT": math.MaxInt64, "INT8": math.MaxInt64, "BIGSERIAL": math.MaxInt64,
+	"OID": math.MaxInt32,
+}
+
+var seedFloatTypes = map[string]bool{
+	"REAL": true, "FLOAT4": true, "FLOAT": true, "FLOAT8": true, "DOUBLE": true, "DOUBLE PRECISION": true,
+}
+
+var (
+	seedFirstNames = []string{"Alice", "Bruno", "Chloe", "Daniel", "Elena", "Farid", "Grace", "Hiro", "Isabel", "Jonas",
+		"Kara", "Liam", "Maya", "Noah", "Olivia", "Pavel", "Quinn", "Rosa", "Samir", "Tessa", "Umar", "Vera", "Wen", "Yara"}
+	seedLastNames = []string{"Anderson", "Bauer", "Costa", "Dubois", "Evans", "Fischer", "Garcia", "Hansen", "Ito", "Jensen",
+		"Kowalski", "Lopez", "Moreau", "Nakamura", "Okafor", "Petrov", "Rossi", "Silva", "Tanaka", "Weber"}
+	seedCities    = []string{"Amsterdam", "Austin", "Berlin", "Bogotá", "Cape Town", "Lisbon", "Lyon", "Melbourne", "Montreal", "Nairobi", "Osaka", "Oslo", "Seoul", "Toronto", "Valencia"}
+	seedCountries = []string{"Australia", "Brazil", "Canada", "France", "Germany", "Japan", "Kenya", "Netherlands", "Norway", "Portugal", "Spain", "United States"}
+	seedStreets   = []string{"Maple Street", "Oak Avenue", "Harbor Road", "Station Lane", "Park Boulevard", "Mill Street", "River Drive", "Church Road", "Elm Court", "Hill Crescent"}
+	seedCompanies = []string{"Acme", "Brightline", "Cobalt", "Driftwood", "Evergreen", "Foxglove", "Granite", "Helix", "Ironbark", "Juniper"}
+	seedStatuses  = []string{"active", "inactive", "pending", "archived"}
+	seedDomains   = []string{"example.com", "example.org", "example.net"}
+	seedWords     = []string{"alpha", "amber", "anchor", "atlas", "beacon", "bridge", "canvas", "cedar", "circuit", "coral",
+		"delta", "ember", "falcon", "field", "garden", "harbor", "horizon", "island", "journey", "lantern", "meadow",
+		"nebula", "orbit", "pepper", "quartz", "river", "signal", "summit", "timber", "velvet", "willow", "zenith"}
+)
+
+type SeedOptions struct {
+	Format      string
+	Seed        int64
+	Rows        map[string]int
+	DefaultRows int
+}
+
+// Generated values are Go values, plus these for the ones whose textual form
+// matters: exact decimals, raw JSON documents and dates with their layout.
+type (
+	seedDecimal string
+	seedJSON    string
+	seedTime    struct {
+		value  time.Time
+		layout string
+	}
+)
+
+type seedTable struct {
+	table    *models.Table
+	fields   map[*models.Field]int
+	required map[*models.Field]bool
+	rows     [][]interface{}
+}
+
+// optional reports whether a foreign key may be left NULL, which needs one
+// of its columns to be nullable.
+func (t *seedTable) optional(relation *schemaRelation) bool {
+	for _, field := range relation.FromFields {
+		if !t.required[field] {
+			return true
+		}
+	}
+	return false
+}
+
+type seedGenerator struct {
+	schema    *models.Schema
+	opts      SeedOptions
+	order     []*models.Table
+	tables    map[*models.Table]*seedTable
+	relations []schemaRelation
+	deferred  map[*schemaRelation]bool
+	notes     []string
+}
+
+func (s *ExportService) GenerateSeedData(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, opts SeedOptions) (*ExportResult, error) {
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := GenerateSeedData(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infof("Seed data for schema %s generated as %s", id.Hex(), opts.Format)
+	return result, nil
+}
+
+// GenerateSeedData generates rows for every table and renders them as INSERT
+// statements for a SQL dialect, a zip of CSV files or a JSON document.
+// Parents are generated before the tables referencing them so foreign keys
+// point at existing rows, and the same seed always yields the same data.
+func GenerateSeedData(schema *models.Schema, opts SeedOptions) (*ExportResult, error) {
+	format := strings.ToLower(strings.TrimSpace(opts.Format))
+	if format == "" {
+		format = string(DialectPostgreSQL)
+		if dialect, ok := ParseSQLDialect(schema.DatabaseType); ok {
+			format = string(dialect)
+		}
+	}
+	dialect, isSQL := ParseSQLDialect(format)
+	if !isSQL && format != "csv" && format != "json" {
+		return nil, fmt.Errorf("unsupported seed format: %s", opts.Format)
+	}
+
+	counts, err := seedRowCounts(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &seedGenerator{
+		schema:    schema,
+		opts:      opts,
+		tables:    make(map[*models.Table]*seedTable, len(schema.Tables)),
+		relations: collectRelations(schema),
+		deferred:  make(map[*schemaRelation]bool),
+	}
+	g.orderTables()
+	for _, table := range g.order {
+		g.generateTable(table, counts[table])
+	}
+	g.fillDeferred()
+
+	switch {
+	case isSQL:
+		return &ExportResult{
+			Content:     []byte(g.renderSQL(dialect)),
+			ContentType: "application/sql; charset=utf-8",
+			FileName:    exportFileName(schema.Name, "seed."+string(dialect)+".sql"),
+		}, nil
+	case format == "csv":
+		content, err := g.renderCSV()
+		if err != nil {
+			return nil, err
+		}
+		return &ExportResult{
+			Content:     content,
+			ContentType: "application/zip",
+			FileName:    exportFileName(schema.Name, "seed.csv.zip"),
+		}, nil
+	}
+	content, err := g.renderJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &ExportResult{
+		Content:     content,
+		ContentType: "application/json; charset=utf-8",
+		FileName:    exportFileName(schema.Name, "seed.json"),
+	}, nil
+}
+
+// seedRowCounts resolves the requested counts, keyed by table ID or name,
+// falling back to the default for unlisted tables.
+func seedRowCounts(schema *models.Schema, opts SeedOptions) (map[*models.Table]int, error) {
+	defaultRows := opts.DefaultRows
+	if defaultRows == 0 {
+		defaultRows = seedDefaultRows
+	}
+	if defaultRows < 0 || defaultRows > seedMaxRows {
+		return nil, fmt.Errorf("invalid seed options: default rows must be between 0 and %d", seedMaxRows)
+	}
+
+	counts := make(map[*models.Table]int, len(schema.Tables))
+	for i := range schema.Tables {
+		counts[&schema.Tables[i]] = defaultRows
+	}
+	for ref, count := range opts.Rows {
+		table := findTable(schema, ref)
+		if table == nil {
+			return nil, fmt.Errorf("unknown seed table: %s", ref)
+		}
+		if count < 0 || count > seedMaxRows {
+			return nil, fmt.Errorf("invalid seed options: rows for %s must be between 0 and %d", ref, seedMaxRows)
+		}
+		counts[table] = count
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if total > seedMaxTotalRows {
+		return nil, fmt.Errorf("invalid seed options: at most %d rows can be generated at once", seedMaxTotalRows)
+	}
+	return counts, nil
+}
+
+// orderTables sorts tables so referenced tables come first. Tables caught
+// in a reference cycle keep schema order, and the relations pointing
+// forward are filled in once every table has rows.
+func (g *seedGenerator) orderTables() {
+	placed := make(map[*models.Table]bool, len(g.schema.Tables))
+	for len(g.order) < len(g.schema.Tables) {
+		progress := false
+		for i := range g.schema.Tables {
+			table := &g.schema.Tables[i]
+			if placed[table] {
+				continue
+			}
+			ready := true
+			for k := range g.relations {
+				relation := &g.relations[k]
+				if relation.From == table && relation.To != table && !placed[relation.To] && !g.deferred[relation] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				placed[table] = true
+				g.order = append(g.order, table)
+				progress = true
+			}
+		}
+		if progress {
+			continue
+		}
+		for i := range g.schema.Tables {
+			table := &g.schema.Tables[i]
+			if placed[table] {
+				continue
+			}
+			for k := range g.relations {
+				relation := &g.relations[k]
+				if relation.From == table && relation.To != table && !placed[relation.To] {
+					g.deferred[relation] = true
+				}
+			}
+			break
+		}
+	}
+}
+
+func seedUniqueSets(table *models.Table) [][]*models.Field {
+	var sets [][]*models.Field
+	if primaryKey := primaryKeyFields(table); len(primaryKey) > 0 {
+		sets = append(sets, primaryKey)
+	}
+	for i := range table.Fields {
+		if table.Fields[i].IsUnique && !table.Fields[i].IsPrimaryKey {
+			sets = append(sets, []*models.Field{&table.Fields[i]})
+		}
+	}
+	for i := range table.Constraints {
+		if constraintKind(&table.Constraints[i]) != ConstraintUnique {
+			continue
+		}
+		if fields := constraintFields(table, &table.Constraints[i]); len(fields) > 0 {
+			sets = append(sets, fields)
+		}
+	}
+	for i := range table.Indexes {
+		if !table.Indexes[i].IsUnique {
+			continue
+		}
+		var fields []*models.Field
+		for _, column := range indexColumns(&table.Indexes[i]) {
+			field := findField(table, column.FieldID)
+			if field == nil {
+				fields = nil
+				break
+			}
+			fields = append(fields, field)
+		}
+		if len(fields) > 0 {
+			sets = append(sets, fields)
+		}
+	}
+	return sets
+}
+
+func seedRNG(seed int64, table *models.Table) *rand.Rand {
+	hash := fnv.New64a()
+	hash.Write([]byte(QualifiedName(table.Namespace, table.Name)))
+	return rand.New(rand.NewSource(seed ^ int64(hash.Sum64())))
+}
+
+func (g *seedGenerator) generateTable(table *models.Table, count int) {
+	state := &seedTable{table: table, fields: make(map[*models.Field]int, len(table.Fields)), required: make(map[*models.Field]bool)}
+	for i := range table.Fields {
+		state.fields[&table.Fields[i]] = i
+		if table.Fields[i].IsNotNull || table.Fields[i].IsPrimaryKey {
+			state.required[&table.Fields[i]] = true
+		}
+	}
+	for _, field := range primaryKeyFields(table) {
+		state.required[field] = true
+	}
+	g.tables[table] = state
+	// Each table draws from its own source, so changing the row count of one
+	// table leaves the data of the others unchanged.
+	rng := seedRNG(g.opts.Seed, table)
+
+	var relations []*schemaRelation
+	for i := range g.relations {
+		if g.relations[i].From == table {
+			relations = append(relations, &g.relations[i])
+		}
+	}
+	// One-to-one relations hand out each parent row once.
+	queues := make(map[*schemaRelation][]int)
+	for _, relation := range relations {
+		if relation.To != table && !g.deferred[relation] && relation.IsOneToOne() {
+			queues[relation] = rng.Perm(len(g.tables[relation.To].rows))
+		}
+	}
+
+	sets := seedUniqueSets(table)
+	singleUnique := make(map[*models.Field]bool)
+	for _, set := range sets {
+		if len(set) == 1 {
+			singleUnique[set[0]] = true
+		}
+	}
+	seen := make([]map[string]bool, len(sets))
+	for i := range seen {
+		seen[i] = make(map[string]bool)
+	}
+
+	dropped := 0
+	for n := 0; n < count; n++ {
+		accepted := false
+		for attempt := 0; attempt < seedRowAttempts && !accepted; attempt++ {
+			row, picked, ok := g.generateRow(rng, state, relations, queues, singleUnique, count, attempt)
+			if !ok {
+				break
+			}
+			keys := make([]string, len(sets))
+			collision := false
+			for i, set := range sets {
+				keys[i] = seedKey(state, row, set)
+				if keys[i] != "" && seen[i][keys[i]] {
+					collision = true
+					break
+				}
+			}
+			if collision {
+				continue
+			}
+			for i, key := range keys {
+				if key != "" {
+					seen[i][key] = true
+				}
+			}
+			for relation := range picked {
+				queues[relation] = queues[relation][1:]
+			}
+			state.rows = append(state.rows, row)
+			accepted = true
+		}
+		if !accepted {
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		g.notes = append(g.notes, fmt.Sprintf("%s: generated %d of %d rows; there were not enough parent rows or distinct unique values.",
+			QualifiedName(table.Namespace, table.Name), count-dropped, count))
+	}
+}
+
+// generateRow fills the foreign key columns from parent rows and the rest
+// with generated values. picked lists the one-to-one relations that used the
+// head of their queue; ok is false when a required parent has no rows.
+func (g *seedGenerator) generateRow(rng *rand.Rand, state *seedTable, relations []*schemaRelation, queues map[*schemaRelation][]int,
+	singleUnique map[*models.Field]bool, count, attempt int) ([]interface{}, map[*schemaRelation]bool, bool) {
+	table := state.table
+	row := make([]interface{}, len(table.Fields))
+	assigned := make([]bool, len(table.Fields))
+	picked := make(map[*schemaRelation]bool)
+	var selfReferences []*schemaRelation
+
+	for _, relation := range relations {
+		// Parents inserted later are filled in by fillDeferred.
+		if g.deferred[relation] {
+			g.assignNull(state, relation, row, assigned)
+			continue
+		}
+		parent := g.tables[relation.To]
+		var candidates []int
+		if queue, ok := queues[relation]; ok {
+			if len(queue) > 0 {
+				candidates = []int{queue[0]}
+			}
+		} else {
+			for index, parentRow := range parent.rows {
+				matches := true
+				for k, field := range relation.FromFields {
+					position := state.fields[field]
+					if assigned[position] && seedKey(parent, parentRow, relation.ToFields[k:k+1]) != seedKey(state, row, []*models.Field{field}) {
+						matches = false
+						break
+					}
+				}
+				if matches {
+					candidates = append(candidates, index)
+				}
+			}
+		}
+
+		if len(candidates) == 0 || (state.optional(relation) && rng.Intn(100) < seedNullPercent) {
+			switch {
+			case state.optional(relation):
+				g.assignNull(state, relation, row, assigned)
+			case relation.To == table:
+				selfReferences = append(selfReferences, relation)
+			default:
+				return nil, nil, false
+			}
+			continue
+		}
+
+		index := candidates[rng.Intn(len(candidates))]
+		if _, ok := queues[relation]; ok {
+			picked[relation] = true
+		}
+		for k, field := range relation.FromFields {
+			position := state.fields[field]
+			row[position] = parent.rows[index][parent.fields[relation.ToFields[k]]]
+			assigned[position] = true
+		}
+	}
+
+	rowContext := &seedRowContext{row: len(state.rows) + 1, sequence: len(state.rows) + 1 + attempt*count}
+	for i := range table.Fields {
+		if assigned[i] {
+			continue
+		}
+		field := &table.Fields[i]
+		if !state.required[field] && !singleUnique[field] && strings.TrimSpace(field.DefaultValue) == "" && rng.Intn(100) < seedNullPercent {
+			continue
+		}
+		row[i] = g.value(rng, table, field, singleUnique[field], rowContext)
+	}
+
+	// The first rows of a required self-reference point at themselves.
+	for _, relation := range selfReferences {
+		for k, field := range relation.FromFields {
+			row[state.fields[field]] = row[state.fields[relation.ToFields[k]]]
+		}
+	}
+	return row, picked, true
+}
+
+// assignNull leaves the nullable columns of a foreign key NULL, which is
+// enough for the key not to be checked.
+func (g *seedGenerator) assignNull(state *seedTable, relation *schemaRelation, row []interface{}, assigned []bool) {
+	for _, field := range relation.FromFields {
+		if !state.required[field] {
+			position := state.fields[field]
+			row[position], assigned[position] = nil, true
+		}
+	}
+}
+
+// seedKey joins the values of fields into a comparable key, or returns ""
+// when one of them is NULL, since NULLs never collide in SQL.
+func seedKey(state *seedTable, row []interface{}, fields []*models.Field) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		value := row[state.fields[field]]
+		if value == nil {
+			return ""
+		}
+		parts[i] = fmt.Sprintf("%T:%s", value, seedText(value))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// fillDeferred points the foreign keys left open by reference cycles at
+// rows of the now generated parent tables.
+func (g *seedGenerator) fillDeferred() {
+	for i := range g.relations {
+		relation := &g.relations[i]
+		if !g.deferred[relation] {
+			continue
+		}
+		child, parent := g.tables[relation.From], g.tables[relation.To]
+		if child.optional(relation) {
+			g.notes = append(g.notes, fmt.Sprintf("%s.%s is left NULL because %s is inserted later.",
+				QualifiedName(relation.From.Namespace, relation.From.Name), strings.Join(fieldNames(relation.FromFields), ", "),
+				QualifiedName(relation.To.Namespace, relation.To.Name)))
+			continue
+		}
+		if len(parent.rows) == 0 {
+			g.notes = append(g.notes, fmt.Sprintf("%s references %s, which has no rows.",
+				QualifiedName(relation.From.Namespace, relation.From.Name), QualifiedName(relation.To.Namespace, relation.To.Name)))
+			continue
+		}
+		rng := seedRNG(g.opts.Seed, relation.From)
+		for _, row := range child.rows {
+			parentRow := parent.rows[rng.Intn(len(parent.rows))]
+			for k, field := range relation.FromFields {
+				row[child.fields[field]] = parentRow[parent.fields[relation.ToFields[k]]]
+			}
+		}
+		g.notes = append(g.notes, fmt.Sprintf("%s references %s, which is inserted later; load with foreign key checks deferred or disabled.",
+			QualifiedName(relation.From.Namespace, relation.From.Name), QualifiedName(relation.To.Namespace, relation.To.Name)))
+	}
+}
+
+// seedRowContext carries what values of one row share: its position among
+// the accepted rows, the counter unique values are built from (moved past
+// earlier attempts when a row is retried) and the creation time, which
+// later modification times follow.
+type seedRowContext struct {
+	row      int
+	sequence int
+	created  *time.Time
+}
+
+func seedPick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
+
+func seedWordList(rng *rand.Rand, min, max int) []string {
+	words := make([]string, min+rng.Intn(max-min+1))
+	for i := range words {
+		words[i] = seedPick(rng, seedWords)
+	}
+	return words
+}
+
+func truncateRunes(value string, length int) string {
+	if length <= 0 {
+		return value
+	}
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
+
+// seedNameHint reduces a column name to lowercase letters and digits for
+// the name heuristics.
+func seedNameHint(name string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(name))
+}
+
+func (g *seedGenerator) value(rng *rand.Rand, table *models.Table, field *models.Field, unique bool, rowContext *seedRowContext) interface{} {
+	if enum := resolveEnum(g.schema, table.Namespace, field.Type); enum != nil {
+		if len(enum.Values) == 0 {
+			return nil
+		}
+		if unique {
+			return enum.Values[(rowContext.sequence-1)%len(enum.Values)]
+		}
+		return seedPick(rng, enum.Values)
+	}
+
+	base, args := splitColumnType(field)
+	if strings.HasSuffix(base, "[]") {
+		element := *field
+		element.Type = strings.TrimSuffix(base, "[]")
+		element.Length = 0
+		values := make([]interface{}, 1+rng.Intn(3))
+		for i := range values {
+			values[i] = g.value(rng, table, &element, false, &seedRowContext{row: rowContext.row, sequence: rowContext.sequence})
+		}
+		return values
+	}
+
+	hint := seedNameHint(field.Name)
+	switch {
+	case isSerialField(field):
+		return int64(rowContext.row)
+	case base == "YEAR":
+		return int64(1990 + rng.Intn(36))
+	case seedIntegerMax[base] > 0:
+		maximum := seedIntegerMax[base]
+		if unique && int64(rowContext.sequence) <= maximum {
+			return int64(rowContext.sequence)
+		}
+		low, high := seedNumberRange(hint)
+		if int64(high) > maximum {
+			high = float64(maximum)
+		}
+		if int64(low) > int64(high) {
+			low = 0
+		}
+		return int64(low) + rng.Int63n(int64(high)-int64(low)+1)
+	case seedFloatTypes[base]:
+		low, high := seedNumberRange(hint)
+		return math.Round((low+rng.Float64()*(high-low))*1000) / 1000
+	case decimalTypes[base] || base == "MONEY":
+		return seedDecimalValue(rng, field, args, hint, unique, rowContext.sequence)
+	case base == "BOOLEAN" || base == "BOOL":
+		return rng.Intn(2) == 0
+	case base == "DATE":
+		return seedTime{value: seedDateValue(rng, hint, rowContext), layout: "2006-01-02"}
+	case tsDateTimeTypes[base]:
+		return seedTime{value: seedDateValue(rng, hint, rowContext), layout: "2006-01-02 15:04:05"}
+	case base == "TIME" || base == "TIMETZ":
+		return seedTime{value: seedEpoch.Add(time.Duration(rng.Intn(86400)) * time.Second), layout: "15:04:05"}
+	case base == "UUID":
+		return seedUUID(rng)
+	case base == "JSON" || base == "JSONB":
+		document, _ := json.Marshal(map[string]interface{}{"label": seedPick(rng, seedWords), "value": rng.Intn(1000)})
+		return seedJSON(document)
+	case openAPIBinaryTypes[base]:
+		size := 8 + rng.Intn(9)
+		if field.Length > 0 && size > field.Length {
+			size = field.Length
+		}
+		data := make([]byte, size)
+		rng.Read(data)
+		return data
+	case base == "INET" || base == "CIDR":
+		return fmt.Sprintf("10.%d.%d.%d", rng.Intn(256), rng.Intn(256), 1+rng.Intn(254))
+	}
+	return seedString(rng, field.Name, field.Length, unique, rowContext.sequence)
+}
+
+func seedUUID(rng *rand.Rand) string {
+	id := make([]byte, 16)
+	rng.Read(id)
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	encoded := hex.EncodeToString(id)
+	return encoded[:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:]
+}
+
+func seedNumberRange(hint string) (float64, float64) {
+	switch {
+	case hint == "age":
+		return 18, 90
+	case strings.Contains(hint, "price") || strings.Contains(hint, "amount") || strings.Contains(hint, "total") ||
+		strings.Contains(hint, "cost") || strings.Contains(hint, "balance") || strings.Contains(hint, "salary") || strings.Contains(hint, "fee"):
+		return 1, 1000
+	case strings.Contains(hint, "quantity") || strings.Contains(hint, "qty") || strings.Contains(hint, "count") || strings.Contains(hint, "stock"):
+		return 0, 50
+	case strings.Contains(hint, "rating") || strings.Contains(hint, "stars"):
+		return 1, 5
+	case strings.Contains(hint, "score"):
+		return 0, 100
+	case strings.Contains(hint, "percent") || strings.HasSuffix(hint, "pct") || strings.Contains(hint, "discount"):
+		return 0, 100
+	case strings.HasPrefix(hint, "lat"):
+		return -90, 90
+	case strings.HasPrefix(hint, "lng") || strings.HasPrefix(hint, "lon"):
+		return -180, 180
+	case strings.Contains(hint, "year"):
+		return 1990, 2025
+	}
+	return 1, 1000
+}
+
+// seedDecimalValue keeps values inside the declared precision and scale.
+func seedDecimalValue(rng *rand.Rand, field *models.Field, args, hint string, unique bool, sequence int) seedDecimal {
+	precision, scale := field.Precision, field.Scale
+	if parts := strings.Split(args, ","); args != "" {
+		precision, _ = strconv.Atoi(parts[0])
+		scale = 0
+		if len(parts) > 1 {
+			scale, _ = strconv.Atoi(parts[1])
+		}
+	}
+	if precision == 0 {
+		precision, scale = 12, 2
+	}
+	if scale > precision {
+		scale = precision
+	}
+
+	low, high := seedNumberRange(hint)
+	if maximum := math.Pow(10, float64(precision-scale)) - math.Pow(10, -float64(scale)); high > maximum {
+		high = maximum
+	}
+	if low > high {
+		low = 0
+	}
+	value := low + rng.Float64()*(high-low)
+	if unique {
+		value = float64(sequence)
+	}
+	return seedDecimal(strconv.FormatFloat(value, 'f', scale, 64))
+}
+
+func seedDateValue(rng *rand.Rand, hint string, rowContext *seedRowContext) time.Time {
+	switch {
+	case strings.Contains(hint, "birth") || hint == "dob":
+		return time.Date(1950+rng.Intn(56), time.Month(1+rng.Intn(12)), 1+rng.Intn(28), 0, 0, 0, 0, time.UTC)
+	case (strings.Contains(hint, "updated") || strings.Contains(hint, "modified") || strings.Contains(hint, "deleted")) && rowContext.created != nil:
+		return rowContext.created.Add(time.Duration(rng.Int63n(int64(90 * 24 * time.Hour)))).Truncate(time.Second)
+	}
+	value := seedEpoch.Add(time.Duration(rng.Int63n(int64(seedSpan)))).Truncate(time.Second)
+	if strings.Contains(hint, "created") || strings.Contains(hint, "inserted") || strings.Contains(hint, "registered") {
+		rowContext.created = &value
+	}
+	return value
+}
+
+// seedString picks a value from the column name: emails, people and place
+// names, URLs and so on, falling back to a few words. Unique columns get
+// the row sequence appended; everything is cut to the column length.
+func seedString(rng *rand.Rand, name string, length int, unique bool, sequence int) string {
+	hint := seedNameHint(name)
+	first, last := seedPick(rng, seedFirstNames), seedPick(rng, seedLastNames)
+	separator := " "
+
+	var value string
+	switch {
+	case strings.Contains(hint, "email"):
+		local := strings.ToLower(first + "." + last)
+		if unique {
+			local += strconv.Itoa(sequence)
+		}
+		domain := "@" + seedPick(rng, seedDomains)
+		if length > 0 && len(local)+len(domain) > length {
+			return fitWithSuffix(local, strconv.Itoa(sequence), length)
+		}
+		return local + domain
+	case hint == "uid" || strings.Contains(hint, "uuid") || strings.Contains(hint, "guid"):
+		return truncateRunes(seedUUID(rng), length)
+	case strings.Contains(hint, "firstname") || strings.Contains(hint, "givenname") || hint == "forename":
+		value = first
+	case strings.Contains(hint, "lastname") || strings.Contains(hint, "surname") || strings.Contains(hint, "familyname"):
+		value = last
+	case strings.Contains(hint, "username") || strings.Contains(hint, "login") || strings.Contains(hint, "handle") || hint == "nickname":
+		value, separator = strings.ToLower(first+last), ""
+		if !unique {
+			value += strconv.Itoa(rng.Intn(100))
+		}
+	case strings.Contains(hint, "company") || strings.Contains(hint, "organization") || strings.Contains(hint, "organisation") || strings.Contains(hint, "employer"):
+		value = seedPick(rng, seedCompanies) + " " + seedPick(rng, []string{"Labs", "Group", "Systems", "Partners", "Works"})
+	case strings.Contains(hint, "phone") || strings.Contains(hint, "mobile") || hint == "fax":
+		value = fmt.Sprintf("+1-555-%03d-%04d", rng.Intn(1000), rng.Intn(10000))
+	case strings.Contains(hint, "city") || hint == "town":
+		value = seedPick(rng, seedCities)
+	case strings.Contains(hint, "country"):
+		value = seedPick(rng, seedCountries)
+	case strings.Contains(hint, "street") || strings.Contains(hint, "address"):
+		value = fmt.Sprintf("%d %s", 1+rng.Intn(400), seedPick(rng, seedStreets))
+	case strings.Contains(hint, "zip") || strings.Contains(hint, "postal") || strings.Contains(hint, "postcode"):
+		value = fmt.Sprintf("%05d", rng.Intn(100000))
+	case strings.Contains(hint, "url") || strings.Contains(hint, "website") || strings.Contains(hint, "link") || strings.Contains(hint, "homepage"):
+		value, separator = "https://"+seedPick(rng, seedDomains)+"/"+strings.Join(seedWordList(rng, 1, 2), "-"), "-"
+	case strings.Contains(hint, "slug"):
+		value, separator = strings.Join(seedWordList(rng, 2, 3), "-"), "-"
+	case strings.Contains(hint, "currency"):
+		value = seedPick(rng, []string{"USD", "EUR", "GBP", "JPY", "CAD"})
+	case strings.Contains(hint, "status"):
+		value = seedPick(rng, seedStatuses)
+	case strings.Contains(hint, "color") || strings.Contains(hint, "colour"):
+		value = fmt.Sprintf("#%06x", rng.Intn(1<<24))
+	case strings.Contains(hint, "password") || strings.Contains(hint, "hash") || strings.Contains(hint, "token") || strings.Contains(hint, "secret"):
+		data := make([]byte, 32)
+		rng.Read(data)
+		value, separator = hex.EncodeToString(data), ""
+	case hint == "ip" || strings.Contains(hint, "ipaddr") || strings.HasSuffix(strings.ToLower(name), "_ip"):
+		value = fmt.Sprintf("192.168.%d.%d", rng.Intn(256), 1+rng.Intn(254))
+	case strings.Contains(hint, "code") || strings.Contains(hint, "sku") || strings.HasSuffix(hint, "ref") || strings.Contains(hint, "reference"):
+		value, separator = strings.ToUpper(seedPick(rng, seedWords)[:3])+fmt.Sprintf("-%04d", rng.Intn(10000)), "-"
+	case strings.Contains(hint, "name"):
+		if hint == "name" || strings.Contains(hint, "full") || strings.Contains(hint, "display") || strings.Contains(hint, "contact") ||
+			strings.Contains(hint, "author") || strings.Contains(hint, "customer") || strings.Contains(hint, "user") {
+			value = first + " " + last
+		} else {
+			value = seedTitle(seedWordList(rng, 1, 2))
+		}
+	case strings.Contains(hint, "title") || strings.Contains(hint, "subject") || strings.Contains(hint, "headline") || strings.Contains(hint, "label"):
+		value = seedTitle(seedWordList(rng, 2, 5))
+	case strings.Contains(hint, "description") || strings.Contains(hint, "bio") || strings.Contains(hint, "comment") || strings.Contains(hint, "body") ||
+		strings.Contains(hint, "content") || strings.Contains(hint, "note") || strings.Contains(hint, "summary") || strings.Contains(hint, "message") ||
+		strings.Contains(hint, "text"):
+		sentence := strings.Join(seedWordList(rng, 6, 14), " ")
+		value = strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+	default:
+		value = strings.Join(seedWordList(rng, 1, 3), " ")
+	}
+	if unique {
+		return fitWithSuffix(value, separator+strconv.Itoa(sequence), length)
+	}
+	return truncateRunes(value, length)
+}
+
+func seedTitle(words []string) string {
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// fitWithSuffix appends suffix, shortening value so the result fits in
+// length when one is set.
+func fitWithSuffix(value, suffix string, length int) string {
+	if length <= 0 {
+		return value + suffix
+	}
+	keep := length - len(suffix)
+	if keep <= 0 {
+		return truncateRunes(strings.TrimLeft(suffix, " -"), length)
+	}
+	return truncateRunes(value, keep) + suffix
+}
+
+// seedText renders a value as plain text, as used in CSV files.
+func seedText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case seedDecimal:
+		return string(v)
+	case seedJSON:
+		return string(v)
+	case seedTime:
+		return v.value.Format(v.layout)
+	case []byte:
+		return `\x` + hex.EncodeToString(v)
+	case []interface{}:
+		return seedArrayLiteral(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// seedArrayLiteral renders a PostgreSQL array literal such as {"a","b"}.
+func seedArrayLiteral(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		if value == nil {
+			parts[i] = "NULL"
+			continue
+		}
+		text := seedText(value)
+		parts[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func (g *seedGenerator) sqlLiteral(dialect SQLDialect, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case int64, float64, seedDecimal:
+		return seedText(v)
+	case bool:
+		if dialect == DialectSQLite {
+			if v {
+				return "1"
+			}
+			return "0"
+		}
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case []byte:
+		if dialect == DialectPostgreSQL {
+			return `'\x` + hex.EncodeToString(v) + `'`
+		}
+		return "X'" + hex.EncodeToString(v) + "'"
+	case []interface{}:
+		if dialect == DialectPostgreSQL {
+			return sqlString(seedArrayLiteral(v))
+		}
+		document, _ := json.Marshal(seedJSONValue(v))
+		return g.sqlStringLiteral(dialect, string(document))
+	}
+	return g.sqlStringLiteral(dialect, seedText(value))
+}
+
+// MySQL treats backslashes in string literals as escapes by default.
+func (g *seedGenerator) sqlStringLiteral(dialect SQLDialect, value string) string {
+	if dialect == DialectMySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return sqlString(value)
+}
+
+func (g *seedGenerator) renderSQL(dialect SQLDialect) string {
+	ddl := &ddlGenerator{schema: g.schema, dialect: dialect}
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- %s seed data\n-- Generated by Schema Builder for %s with seed %d\n", g.schema.Name, dialect, g.opts.Seed)
+	for _, note := range g.notes {
+		fmt.Fprintf(&b, "-- Note: %s\n", note)
+	}
+
+	for _, table := range g.order {
+		state := g.tables[table]
+		if len(state.rows) == 0 {
+			continue
+		}
+		// Postgres and SQLite have no empty column list; rows of a table
+		// without fields are inserted one by one with their defaults.
+		if len(table.Fields) == 0 && dialect != DialectMySQL {
+			b.WriteString("\n")
+			for range state.rows {
+				fmt.Fprintf(&b, "INSERT INTO %s DEFAULT VALUES;\n", ddl.tableName(table))
+			}
+			continue
+		}
+		columns := make([]string, len(table.Fields))
+		for i := range table.Fields {
+			columns[i] = ddl.quote(table.Fields[i].Name)
+		}
+		for start := 0; start < len(state.rows); start += seedBatchSize {
+			end := start + seedBatchSize
+			if end > len(state.rows) {
+				end = len(state.rows)
+			}
+			fmt.Fprintf(&b, "\nINSERT INTO %s (%s) VALUES\n", ddl.tableName(table), strings.Join(columns, ", "))
+			for i, row := range state.rows[start:end] {
+				values := make([]string, len(row))
+				for k, value := range row {
+					values[k] = g.sqlLiteral(dialect, value)
+				}
+				separator := ","
+				if start+i == end-1 {
+					separator = ";"
+				}
+				fmt.Fprintf(&b, "  (%s)%s\n", strings.Join(values, ", "), separator)
+			}
+		}
+
+		// Explicit values bypass the sequences behind serial columns.
+		if dialect == DialectPostgreSQL {
+			for i := range table.Fields {
+				if isSerialField(&table.Fields[i]) {
+					fmt.Fprintf(&b, "SELECT setval(pg_get_serial_sequence(%s, %s), (SELECT MAX(%s) FROM %s));\n",
+						sqlString(ddl.tableName(table)), sqlString(table.Fields[i].Name), columns[i], ddl.tableName(table))
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// renderCSV writes one CSV file per table with a header row; the numeric
+// prefix gives an order in which the files can be loaded.
+func (g *seedGenerator) renderCSV() ([]byte, error) {
+	fileNames := tableFileNames(g.schema)
+	var files []archiveFile
+	for position, table := range g.order {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		header := make([]string, len(table.Fields))
+		for i := range table.Fields {
+			header[i] = table.Fields[i].Name
+		}
+		if err := writer.Write(header); err != nil {
+			return nil, fmt.Errorf("failed to write seed data: %v", err)
+		}
+		for _, row := range g.tables[table].rows {
+			record := make([]string, len(row))
+			for i, value := range row {
+				record[i] = seedText(value)
+			}
+			if err := writer.Write(record); err != nil {
+				return nil, fmt.Errorf("failed to write seed data: %v", err)
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write seed data: %v", err)
+		}
+		files = append(files, archiveFile{name: fmt.Sprintf("%02d_%s.csv", position+1, fileNames[table]), content: buf.Bytes()})
+	}
+	if len(g.notes) > 0 {
+		files = append(files, archiveFile{name: "NOTES.txt", content: []byte(strings.Join(g.notes, "\n") + "\n")})
+	}
+	return zipArchive(files)
+}
+
+func seedJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case seedDecimal:
+		return json.Number(v)
+	case seedJSON:
+		return json.RawMessage(v)
+	case seedTime:
+		return v.value.Format(v.layout)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = seedJSONValue(item)
+		}
+		return values
+	}
+	return value
+}
+
+// renderJSON writes an object keyed by qualified table name, in insert
+// order, holding the rows as objects.
+func (g *seedGenerator) renderJSON() ([]byte, error) {
+	document := newOrderedMap()
+	for _, table := range g.order {
+		rows := make([]interface{}, 0, len(g.tables[table].rows))
+		for _, row := range g.tables[table].rows {
+			object := newOrderedMap()
+			for i, value := range row {
+				object.Set(table.Fields[i].Name, seedJSONValue(value))
+			}
+			rows = append(rows, object)
+		}
+		document.Set(QualifiedName(table.Namespace, table.Name), rows)
+	}
+	return marshalDocument(document)
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/internal/repository"
+	"schema-builder-backend/pkg/logger"
+)
+
+type ShareService struct {
+	schemaService   *SchemaService
+	commentService  *CommentService
+	shareRepo       repository.ShareLinkRepository
+	passwordService *PasswordService
+	log             *logrus.Logger
+}
+
+// SharedSchema is what a share link shows. Threads are included for links
+// with comment access.
+type SharedSchema struct {
+	Schema    *models.Schema          `json:"schema"`
+	Access    string                  `json:"access"`
+	ExpiresAt *time.Time              `json:"expires_at,omitempty"`
+	Threads   []*CommentThreadDetails `json:"threads,omitempty"`
+}
+
+func NewShareService(schemaService *SchemaService, commentService *CommentService, shareRepo repository.ShareLinkRepository, passwordService *PasswordService) *ShareService {
+	return &ShareService{
+		schemaService:   schemaService,
+		commentService:  commentService,
+		shareRepo:       shareRepo,
+		passwordService: passwordService,
+		log:             logger.GetLogger(),
+	}
+}
+
+// EnsureTokenIndex creates the index shared links are looked up by.
+func (s *ShareService) EnsureTokenIndex(ctx context.Context) error {
+	return s.shareRepo.EnsureTokenIndex(ctx)
+}
+
+// CreateShareLink creates a link to one of the user's schemas. The returned
+// link carries the token; it cannot be retrieved later.
+func (s *ShareService) CreateShareLink(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, req *models.CreateShareLinkRequest) (*models.ShareLink, error) {
+	if _, err := s.ownSchema(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("invalid share link: expires_at must be in the future")
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %v", err)
+	}
+
+	link := &models.ShareLink{
+		SchemaID:  id,
+		TokenHash: hashShareToken(token),
+		Access:    req.Access,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: userID,
+	}
+	if req.Password != "" {
+		link.PasswordHash, err = s.passwordService.HashPassword(req.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash share link password: %v", err)
+		}
+		link.HasPassword = true
+	}
+
+	if err := s.shareRepo.Create(ctx, link); err != nil {
+		s.log.Errorf("Failed to create share link: %v", err)
+		return nil, err
+	}
+
+	link.Token = token
+	s.log.Infof("Share link %s created for schema %s", link.ID.Hex(), id.Hex())
+	return link, nil
+}
+
+func (s *ShareService) ListShareLinks(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) ([]*models.ShareLink, error) {
+	if _, err := s.ownSchema(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.shareRepo.ListBySchema(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %v", err)
+	}
+
+	return links, nil
+}
+
+func (s *ShareService) RevokeShareLink(ctx context.Context, id, shareID primitive.ObjectID, userID primitive.ObjectID) error {
+	if _, err := s.ownSchema(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.shareRepo.Revoke(ctx, shareID, id); err != nil {
+		return err
+	}
+
+	s.log.Infof("Share link %s of schema %s revoked", shareID.Hex(), id.Hex())
+	return nil
+}
+
+// GetSharedSchema returns the schema a link shares and counts the view.
+func (s *ShareService) GetSharedSchema(ctx context.Context, token, password string) (*SharedSchema, error) {
+	link, schema, err := s.resolve(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.shareRepo.RecordView(ctx, link.ID); err != nil {
+		s.log.Errorf("Failed to count view of share link %s: %v", link.ID.Hex(), err)
+	}
+
+	shared := &SharedSchema{
+		Schema:    schema,
+		Access:    link.Access,
+		ExpiresAt: link.ExpiresAt,
+	}
+	if link.Access == models.ShareAccessComment {
+		shared.Threads, err = s.commentService.listThreads(ctx, schema, repository.CommentThreadFilter{SchemaID: schema.ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return shared, nil
+}
+
+// CreateSharedThread starts a comment thread through a link with comment
+// access. Commenting still requires an account.
+func (s *ShareService) CreateSharedThread(ctx context.Context, token, password string, userID primitive.ObjectID, req *models.CreateCommentThreadRequest) (*CommentThreadDetails, error) {
+	schema, err := s.resolveForComment(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentService.createThread(ctx, schema, userID, req)
+}
+
+func (s *ShareService) AddSharedComment(ctx context.Context, token, password string, threadID primitive.ObjectID, userID primitive.ObjectID, req *models.AddCommentRequest) (*CommentThreadDetails, error) {
+	schema, err := s.resolveForComment(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentService.addComment(ctx, schema, threadID, userID, req)
+}
+
+func (s *ShareService) resolveForComment(ctx context.Context, token, password string) (*models.Schema, error) {
+	link, schema, err := s.resolve(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.Access != models.ShareAccessComment {
+		return nil, fmt.Errorf("access denied: share link is read-only")
+	}
+
+	return schema, nil
+}
+
+// resolve looks up a usable link and its schema.
+func (s *ShareService) resolve(ctx context.Context, token, password string) (*models.ShareLink, *models.Schema, error) {
+	link, err := s.shareRepo.GetByTokenHash(ctx, hashShareToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if link.RevokedAt != nil {
+		return nil, nil, fmt.Errorf("share link revoked")
+	}
+	if link.ExpiresAt != nil && !link.ExpiresAt.After(time.Now()) {
+		return nil, nil, fmt.Errorf("share link expired")
+	}
+	if link.PasswordHash != "" {
+		if password == "" {
+			return nil, nil, fmt.Errorf("password required")
+		}
+		if err := s.passwordService.CheckPassword(link.PasswordHash, password); err != nil {
+			return nil, nil, fmt.Errorf("invalid password")
+		}
+	}
+
+	schema, err := s.schemaService.schemaRepo.GetByID(ctx, link.SchemaID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("share link not found")
+	}
+
+	return link, schema, nil
+}
+
+func (s *ShareService) ownSchema(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (*models.Schema, error) {
+	schema, err := s.schemaService.schemaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("schema not found: %v", err)
+	}
+
+	if schema.UserID != userID {
+		return nil, fmt.Errorf("access denied: you can only share your own schemas")
+	}
+
+	return schema, nil
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+var dollarQuotePattern = regexp.MustCompile(`^\$(?:[A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// validateSQLFragments checks the raw SQL a schema embeds in its DDL: view
+// definitions, check conditions, index predicates and expressions, default
+// values and partition bounds. Each must be a single statement under every
+// dialect's quoting rules, so none can end the statement it is embedded in.
+// Defaults are checked as sqlDefault renders them, since plain strings are
+// quoted there.
+func validateSQLFragments(schema *models.Schema, addIssue func(format string, args ...interface{})) {
+	check := func(value, format string, args ...interface{}) {
+		if strings.TrimSpace(value) != "" && !isSingleSQLFragment(value) {
+			addIssue(format+" must be a single SQL statement", args...)
+		}
+	}
+
+	for _, view := range schema.Views {
+		check(view.Definition, "definition of view %q", QualifiedName(view.Namespace, view.Name))
+	}
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		tableName := QualifiedName(table.Namespace, table.Name)
+
+		for _, field := range table.Fields {
+			if strings.TrimSpace(field.DefaultValue) != "" && !isSingleSQLDefault(field.DefaultValue) {
+				addIssue("default of field %q of %q must be a single SQL statement", field.Name, tableName)
+			}
+		}
+		for j := range table.Constraints {
+			constraint := &table.Constraints[j]
+			check(constraint.CheckCondition, "check condition %q on %q", constraint.Name, tableName)
+		}
+		for j := range table.Indexes {
+			index := &table.Indexes[j]
+			label := indexDisplayName(table, index)
+			check(index.Where, "predicate of index %q on %q", label, tableName)
+			for _, column := range index.Columns {
+				check(column.Expression, "expression of index %q on %q", label, tableName)
+			}
+		}
+		if table.Options != nil && table.Options.Partitioning != nil {
+			for _, partition := range table.Options.Partitioning.Partitions {
+				for _, bounds := range [][]string{partition.From, partition.To, partition.In} {
+					for _, bound := range bounds {
+						check(bound, "bound of partition %q of %q", partition.Name, tableName)
+					}
+				}
+			}
+		}
+	}
+}
+
+func isSingleSQLFragment(value string) bool {
+	for _, dialect := range []SQLDialect{DialectPostgreSQL, DialectMySQL, DialectSQLite} {
+		statements, complete := splitSQLStatements(value, dialect)
+		if !complete || len(statements) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func isSingleSQLDefault(value string) bool {
+	for _, dialect := range []SQLDialect{DialectPostgreSQL, DialectMySQL, DialectSQLite} {
+		statements, complete := splitSQLStatements(sqlDefault(value, dialect), dialect)
+		if !complete || len(statements) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// singleSQLStatement returns the one statement in sql without its closing
+// semicolon.
+func singleSQLStatement(sql string, dialect SQLDialect) (string, error) {
+	statements, complete := splitSQLStatements(sql, dialect)
+	if !complete {
+		return "", fmt.Errorf("statement has an unterminated quote or comment and was not executed")
+	}
+	if len(statements) != 1 {
+		return "", fmt.Errorf("statement splits into %d statements and was not executed", len(statements))
+	}
+	return statements[0], nil
+}
+
+// splitSQLStatements splits sql at the semicolons outside quotes and
+// comments, following the dialect's syntax, and drops empty statements.
+// complete is false when a quote or comment is left open.
+func splitSQLStatements(sql string, dialect SQLDialect) (statements []string, complete bool) {
+	start, hasCode := 0, false
+	flush := func(end int) {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(sql[start:end]))
+		}
+		start, hasCode = end+1, false
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == ';':
+			flush(i)
+		case c == '-' && strings.HasPrefix(sql[i:], "--") && (dialect != DialectMySQL || i+2 == len(sql) || sql[i+2] <= ' '),
+			c == '#' && dialect == DialectMySQL:
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			i += end
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			// MySQL runs the contents of /*! ... */ comments.
+			if dialect == DialectMySQL && (strings.HasPrefix(sql[i:], "/*!") || strings.HasPrefix(sql[i:], "/*+")) {
+				hasCode = true
+				i += 2
+				continue
+			}
+			end, ok := blockCommentEnd(sql, i, dialect == DialectPostgreSQL)
+			if !ok {
+				return statements, false
+			}
+			i = end - 1
+		case c == '\'' || c == '"' || c == '`' || (c == '[' && dialect == DialectSQLite):
+			end, ok := quotedEnd(sql, i, dialect)
+			if !ok {
+				return statements, false
+			}
+			hasCode = true
+			i = end - 1
+		case c == '$' && dialect == DialectPostgreSQL && (i == 0 || !isIdentifierByte(sql[i-1])):
+			tag := dollarQuotePattern.FindString(sql[i:])
+			if tag == "" {
+				hasCode = true
+				continue
+			}
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				return statements, false
+			}
+			hasCode = true
+			i += len(tag) + end + len(tag) - 1
+		case c > ' ':
+			hasCode = true
+		}
+	}
+	flush(len(sql))
+
+	return statements, true
+}
+
+// blockCommentEnd returns the offset just past the comment opening at i.
+// Postgres comments nest.
+func blockCommentEnd(sql string, i int, nested bool) (int, bool) {
+	depth := 0
+	for j := i; j+1 < len(sql); j++ {
+		switch {
+		case sql[j] == '/' && sql[j+1] == '*' && (depth == 0 || nested):
+			depth++
+			j++
+		case sql[j] == '*' && sql[j+1] == '/':
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// quotedEnd returns the offset just past the quoted string or identifier
+// opening at i. Doubling the quote escapes it; MySQL strings and Postgres
+// escape strings (E'...') also take backslash escapes.
+func quotedEnd(sql string, i int, dialect SQLDialect) (int, bool) {
+	open := sql[i]
+	closing := open
+	if open == '[' {
+		closing = ']'
+	}
+	backslash := (dialect == DialectMySQL && (open == '\'' || open == '"')) ||
+		(dialect == DialectPostgreSQL && open == '\'' && i > 0 && (sql[i-1] == 'E' || sql[i-1] == 'e'))
+
+	for j := i + 1; j < len(sql); j++ {
+		switch {
+		case backslash && sql[j] == '\\':
+			j++
+		case sql[j] == closing:
+			if closing != ']' && j+1 < len(sql) && sql[j+1] == closing {
+				j++
+				continue
+			}
+			return j + 1, true
+		}
+	}
+	return 0, false
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+const (
+	PartitionRange = "range"
+	PartitionList  = "list"
+	PartitionHash  = "hash"
+)
+
+func partitionStrategy(partitioning *models.Partitioning) string {
+	return strings.ToLower(strings.TrimSpace(partitioning.Strategy))
+}
+
+func validateTableOptions(schema *models.Schema, table *models.Table, addIssue func(format string, args ...interface{})) {
+	tableName := QualifiedName(table.Namespace, table.Name)
+
+	for _, field := range table.Fields {
+		if field.Charset != "" && !identifierPattern.MatchString(field.Charset) {
+			addIssue("field %q of %q has invalid charset %q", field.Name, tableName, field.Charset)
+		}
+		if field.Collation != "" && !collationPattern.MatchString(field.Collation) {
+			addIssue("field %q of %q has invalid collation %q", field.Name, tableName, field.Collation)
+		}
+	}
+
+	options := table.Options
+	if options != nil {
+		if options.Engine != "" && !identifierPattern.MatchString(options.Engine) {
+			addIssue("table %q has invalid engine %q", tableName, options.Engine)
+		}
+		if options.Charset != "" && !identifierPattern.MatchString(options.Charset) {
+			addIssue("table %q has invalid charset %q", tableName, options.Charset)
+		}
+		if options.Collation != "" && !collationPattern.MatchString(options.Collation) {
+			addIssue("table %q has invalid collation %q", tableName, options.Collation)
+		}
+		if options.Tablespace != "" && !identifierPattern.MatchString(options.Tablespace) {
+			addIssue("table %q has invalid tablespace %q", tableName, options.Tablespace)
+		}
+		if options.Partitioning != nil {
+			validatePartitioning(table, options.Partitioning, addIssue)
+		}
+	}
+
+	if dialect, ok := ParseSQLDialect(schema.DatabaseType); ok {
+		for _, issue := range unsupportedTableOptions(dialect, table) {
+			addIssue("table %q: %s", tableName, issue)
+		}
+	}
+}
+
+func validatePartitioning(table *models.Table, partitioning *models.Partitioning, addIssue func(format string, args ...interface{})) {
+	tableName := QualifiedName(table.Namespace, table.Name)
+	strategy := partitionStrategy(partitioning)
+
+	if strategy != PartitionRange && strategy != PartitionList && strategy != PartitionHash {
+		addIssue("table %q has unknown partitioning strategy %q", tableName, partitioning.Strategy)
+		return
+	}
+
+	if len(partitioning.Fields) == 0 {
+		addIssue("table %q is partitioned without a partition key", tableName)
+	}
+	keyFields := make(map[string]bool)
+	for _, ref := range partitioning.Fields {
+		field := findField(table, ref)
+		if field == nil {
+			addIssue("partition key of %q references unknown field %q", tableName, ref)
+			continue
+		}
+		keyFields[field.ID] = true
+	}
+	if strategy == PartitionList && len(partitioning.Fields) > 1 {
+		addIssue("list partitioning of %q accepts a single key column", tableName)
+	}
+
+	if primaryKey := primaryKeyFields(table); len(primaryKey) > 0 {
+		inKey := make(map[string]bool, len(primaryKey))
+		for _, field := range primaryKey {
+			inKey[field.ID] = true
+		}
+		for id := range keyFields {
+			if !inKey[id] {
+				addIssue("primary key of partitioned table %q must include every partition key column", tableName)
+				break
+			}
+		}
+	}
+
+	names := make(map[string]bool)
+	defaults := 0
+	for _, partition := range partitioning.Partitions {
+		if !identifierPattern.MatchString(partition.Name) {
+			addIssue("partition %q of %q is not a valid identifier", partition.Name, tableName)
+		}
+		if names[strings.ToLower(partition.Name)] {
+			addIssue("partition %q of %q is declared more than once", partition.Name, tableName)
+		}
+		names[strings.ToLower(partition.Name)] = true
+		if partition.Tablespace != "" && !identifierPattern.MatchString(partition.Tablespace) {
+			addIssue("partition %q of %q has invalid tablespace %q", partition.Name, tableName, partition.Tablespace)
+		}
+
+		if partition.IsDefault {
+			defaults++
+			if strategy == PartitionHash {
+				addIssue("hash partitioned table %q cannot have a default partition", tableName)
+			}
+			continue
+		}
+
+		switch strategy {
+		case PartitionRange:
+			if len(partition.From) != len(partitioning.Fields) || len(partition.To) != len(partitioning.Fields) {
+				addIssue("range partition %q of %q needs one FROM and TO bound per key column", partition.Name, tableName)
+			}
+		case PartitionList:
+			if len(partition.In) == 0 {
+				addIssue("list partition %q of %q has no values", partition.Name, tableName)
+			}
+		case PartitionHash:
+			if partition.Modulus <= 0 || partition.Remainder < 0 || partition.Remainder >= partition.Modulus {
+				addIssue("hash partition %q of %q needs a positive modulus and a remainder below it", partition.Name, tableName)
+			}
+		}
+	}
+	if defaults > 1 {
+		addIssue("table %q has more than one default partition", tableName)
+	}
+}
+
+// unsupportedTableOptions lists the physical options of a table and its
+// columns that the dialect cannot express.
+func unsupportedTableOptions(dialect SQLDialect, table *models.Table) []string {
+	var issues []string
+
+	if options := table.Options; options != nil {
+		if dialect != DialectMySQL {
+			if options.Engine != "" {
+				issues = append(issues, fmt.Sprintf("storage engine is not supported by %s", dialect))
+			}
+			if options.Charset != "" {
+				issues = append(issues, fmt.Sprintf("table charset is not supported by %s", dialect))
+			}
+			if options.Collation != "" {
+				issues = append(issues, fmt.Sprintf("table collation is not supported by %s", dialect))
+			}
+		}
+		if dialect != DialectPostgreSQL {
+			if options.Tablespace != "" {
+				issues = append(issues, fmt.Sprintf("tablespaces are not supported by %s", dialect))
+			}
+			if options.Partitioning != nil {
+				issues = append(issues, fmt.Sprintf("declarative partitioning is not supported by %s", dialect))
+			}
+		}
+	}
+
+	if dialect != DialectMySQL {
+		for _, field := range table.Fields {
+			if field.Charset != "" {
+				issues = append(issues, fmt.Sprintf("column charset of %q is not supported by %s", field.Name, dialect))
+			}
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"schema-builder-backend/internal/models"
+)
+
+var tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+var tsNumberTypes = map[string]bool{
+	"TINYINT": true, "SMALLINT": true, "INT2": true, "SMALLSERIAL": true, "YEAR": true, "INTEGER": true, "INT": true,
+	"INT4": true, "MEDIUMINT": true, "SERIAL": true, "BIGINT": true, "INT8": true, "BIGSERIAL": true, "OID": true,
+	"REAL": true, "FLOAT4": true, "FLOAT": true, "FLOAT8": true, "DOUBLE": true, "DOUBLE PRECISION": true,
+	"DECIMAL": true, "NUMERIC": true, "MONEY": true,
+}
+
+var tsIntegerTypes = map[string]bool{
+	"TINYINT": true, "SMALLINT": true, "INT2": true, "SMALLSERIAL": true, "YEAR": true, "INTEGER": true, "INT": true,
+	"INT4": true, "MEDIUMINT": true, "SERIAL": true, "BIGINT": true, "INT8": true, "BIGSERIAL": true, "OID": true,
+}
+
+var tsDateTimeTypes = map[string]bool{
+	"TIMESTAMP": true, "TIMESTAMPTZ": true, "TIMESTAMP WITH TIME ZONE": true, "TIMESTAMP WITHOUT TIME ZONE": true,
+	"DATETIME": true,
+}
+
+func tsPropertyName(name string) string {
+	if tsIdentifierPattern.MatchString(name) {
+		return name
+	}
+	return tsString(name)
+}
+
+// tsString quotes a value as a JSON string, which is always a valid
+// JavaScript string literal.
+func tsString(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
+
+type tsGenerator struct {
+	schema     *models.Schema
+	zod        bool
+	interfaces map[*models.Table]string
+	enums      map[*models.Enum]string
+	relations  []schemaRelation
+}
+
+// GenerateTypeScript renders an interface per table and a union type per
+// enum. With zod set, each type also gets a matching Zod schema.
+func GenerateTypeScript(schema *models.Schema, zod bool) string {
+	g := &tsGenerator{
+		schema:     schema,
+		zod:        zod,
+		interfaces: make(map[*models.Table]string),
+		enums:      make(map[*models.Enum]string),
+		relations:  collectRelations(schema),
+	}
+	taken := make(map[string]bool)
+	name := func(namespace, base string) string {
+		candidate := base
+		if taken[candidate] && namespace != "" {
+			candidate = pascalCase(namespace) + base
+		}
+		for i := 2; taken[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", base, i)
+		}
+		taken[candidate] = true
+		taken[candidate+"Schema"] = true
+		return candidate
+	}
+	for i := range schema.Enums {
+		g.enums[&schema.Enums[i]] = name(schema.Enums[i].Namespace, pascalCase(schema.Enums[i].Name))
+	}
+	for i := range schema.Tables {
+		g.interfaces[&schema.Tables[i]] = name(schema.Tables[i].Namespace, pascalCase(singularize(schema.Tables[i].Name)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by Schema Builder from %s\n", tsString(schema.Name))
+	if zod {
+		b.WriteString("\nimport { z } from \"zod\";\n")
+	}
+
+	for i := range schema.Enums {
+		b.WriteString("\n")
+		b.WriteString(g.enum(&schema.Enums[i]))
+	}
+	for i := range schema.Tables {
+		b.WriteString("\n")
+		b.WriteString(g.table(&schema.Tables[i]))
+	}
+	return b.String()
+}
+
+func tsDocComment(text, indent string) string {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(text, "*/", "* /")), "\n")
+	if len(lines) == 1 {
+		return fmt.Sprintf("%s/** %s */\n", indent, lines[0])
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		fmt.Fprintf(&b, "%s * %s\n", indent, strings.TrimRight(line, " \t\r"))
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
+
+func (g *tsGenerator) enum(enum *models.Enum) string {
+	name := g.enums[enum]
+	values := make([]string, len(enum.Values))
+	for i, value := range enum.Values {
+		values[i] = tsString(value)
+	}
+
+	var b strings.Builder
+	if enum.Comment != "" {
+		b.WriteString(tsDocComment(enum.Comment, ""))
+	}
+	if len(values) == 0 {
+		fmt.Fprintf(&b, "export type %s = never;\n", name)
+		if g.zod {
+			fmt.Fprintf(&b, "\nexport const %sSchema = z.never();\n", name)
+		}
+		return b.String()
+	}
+	fmt.Fprintf(&b, "export type %s = %s;\n", name, strings.Join(values, " | "))
+	if g.zod {
+		fmt.Fprintf(&b, "\nexport const %sSchema = z.enum([%s]);\n", name, strings.Join(values, ", "))
+	}
+	return b.String()
+}
+
+// fieldType returns the TypeScript type of a column and its Zod schema
+// without the nullability modifiers.
+func (g *tsGenerator) fieldType(table *models.Table, field *models.Field) (string, string) {
+	if enum := resolveEnum(g.schema, table.Namespace, field.Type); enum != nil {
+		return g.enums[enum], g.enums[enum] + "Schema"
+	}
+
+	base, _ := splitColumnType(field)
+	array := strings.HasSuffix(base, "[]")
+	base = strings.TrimSuffix(base, "[]")
+
+	tsType, zodType := "string", "z.string()"
+	switch {
+	case tsNumberTypes[base]:
+		tsType, zodType = "number", "z.number()"
+		if tsIntegerTypes[base] {
+			zodType += ".int()"
+		}
+	case base == "BOOLEAN" || base == "BOOL":
+		tsType, zodType = "boolean", "z.boolean()"
+	case base == "JSON" || base == "JSONB":
+		tsType, zodType = "unknown", "z.unknown()"
+	case tsDateTimeTypes[base]:
+		zodType = "z.string().datetime({ offset: true })"
+	case base == "DATE":
+		zodType = "z.string().date()"
+	case base == "UUID":
+		zodType = "z.string().uuid()"
+	default:
+		if field.Length > 0 && !array {
+			zodType += fmt.Sprintf(".max(%d)", field.Length)
+		}
+	}
+
+	if array {
+		return tsType + "[]", "z.array(" + zodType + ")"
+	}
+	return tsType, zodType
+}
+
+type tsProperty struct {
+	doc      string
+	name     string
+	tsType   string
+	zodType  string
+	optional bool
+}
+
+func (g *tsGenerator) table(table *models.Table) string {
+	primaryKey := primaryKeyFields(table)
+	inPrimaryKey := make(map[*models.Field]bool, len(primaryKey))
+	for _, field := range primaryKey {
+		inPrimaryKey[field] = true
+	}
+
+	names := make(map[string]bool)
+	var properties []tsProperty
+	for i := range table.Fields {
+		field := &table.Fields[i]
+		names[field.Name] = true
+		tsType, zodType := g.fieldType(table, field)
+		property := tsProperty{name: field.Name, tsType: tsType, zodType: zodType}
+		if !field.IsNotNull && !field.IsPrimaryKey && !inPrimaryKey[field] {
+			property.optional = true
+			property.tsType += " | null"
+			property.zodType += ".nullish()"
+		}
+		if field.Comment != "" {
+			property.doc = tsDocComment(field.Comment, "  ")
+		}
+		properties = append(properties, property)
+	}
+	properties = append(properties, g.relationProperties(table, names)...)
+
+	name := g.interfaces[table]
+	var b strings.Builder
+	if table.Description != "" {
+		b.WriteString(tsDocComment(table.Description, ""))
+	}
+	fmt.Fprintf(&b, "export interface %s {\n", name)
+	for _, property := range properties {
+		optional := ""
+		if property.optional {
+			optional = "?"
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", property.doc, tsPropertyName(property.name), optional, property.tsType)
+	}
+	b.WriteString("}\n")
+
+	if g.zod {
+		// The explicit annotation lets schemas of related tables refer to each
+		// other through z.lazy.
+		fmt.Fprintf(&b, "\nexport const %sSchema: z.ZodType<%s> = z.object({\n", name, name)
+		for _, property := range properties {
+			fmt.Fprintf(&b, "  %s: %s,\n", tsPropertyName(property.name), property.zodType)
+		}
+		b.WriteString("});\n")
+	}
+	return b.String()
+}
+
+// relationProperties adds the related rows as optional nested objects: the
+// referenced row on the table holding the foreign key and the referencing
+// rows on the other side.
+func (g *tsGenerator) relationProperties(table *models.Table, names map[string]bool) []tsProperty {
+	claim := func(base, fallback string) string {
+		candidate := base
+		if names[candidate] {
+			candidate = base + fallback
+		}
+		for i := 2; names[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", base+fallback, i)
+		}
+		names[candidate] = true
+		return candidate
+	}
+
+	var properties []tsProperty
+	for i := range g.relations {
+		relation := &g.relations[i]
+		if relation.From == table {
+			target := g.interfaces[relation.To]
+			base := lowerFirst(target)
+			if len(relation.FromFields) == 1 {
+				column := lowerFirst(pascalCase(relation.FromFields[0].Name))
+				if trimmed := strings.TrimSuffix(strings.TrimSuffix(column, "Id"), "ID"); trimmed != column && trimmed != "" {
+					base = trimmed
+				}
+			}
+			properties = append(properties, tsProperty{
+				name:     claim(base, target),
+				tsType:   target,
+				zodType:  fmt.Sprintf("z.lazy(() => %sSchema).optional()", target),
+				optional: true,
+			})
+		}
+
+		if relation.To == table {
+			source := g.interfaces[relation.From]
+			by := "By" + pascalCase(strings.Join(fieldNames(relation.FromFields), "_"))
+			property := tsProperty{
+				name:     claim(pluralize(singularize(lowerFirst(pascalCase(relation.From.Name)))), by),
+				tsType:   source + "[]",
+				zodType:  fmt.Sprintf("z.array(z.lazy(() => %sSchema)).optional()", source),
+				optional: true,
+			}
+			if relation.IsOneToOne() {
+				property = tsProperty{
+					name:     claim(lowerFirst(source), by),
+					tsType:   source,
+					zodType:  fmt.Sprintf("z.lazy(() => %sSchema).optional()", source),
+					optional: true,
+				}
+			}
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
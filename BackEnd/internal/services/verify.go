@@ -0,0 +1,506 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"modernc.org/sqlite"
+
+	"schema-builder-backend/internal/config"
+	"schema-builder-backend/internal/models"
+	"schema-builder-backend/pkg/logger"
+)
+
+const (
+	VerifyPassed  = "passed"
+	VerifyFailed  = "failed"
+	VerifySkipped = "skipped"
+	VerifyError   = "error"
+)
+
+var (
+	sqliteErrorPattern   = regexp.MustCompile(`^[A-Za-z ]+ error(?: \(\d+\))?: | \(\d+\)$`)
+	mysqlLinePattern     = regexp.MustCompile(`at line (\d+)$`)
+	quotedFieldPattern   = `["'` + "`" + `]%s["'` + "`" + `]|[:.] ?%s$`
+	verifyDatabasePrefix = "verify_"
+)
+
+// VerifyService runs generated DDL in scratch databases: an embedded SQLite
+// database, and Postgres and MySQL servers when those are configured.
+// Statements are sent one at a time through drivers that only accept a
+// single statement per call.
+type VerifyService struct {
+	schemaService *SchemaService
+	config        *config.VerifyConfig
+	log           *logrus.Logger
+}
+
+type DDLVerification struct {
+	SchemaID primitive.ObjectID      `json:"schema_id"`
+	Passed   bool                    `json:"passed"`
+	Results  []DDLVerificationResult `json:"results"`
+}
+
+type DDLVerificationResult struct {
+	Dialect    SQLDialect  `json:"dialect"`
+	Status     string      `json:"status"`
+	Statements int         `json:"statements"`
+	Executed   int         `json:"executed"`
+	Message    string      `json:"message,omitempty"`
+	Failure    *DDLFailure `json:"failure,omitempty"`
+}
+
+// DDLFailure locates the first failing statement. Line is relative to the
+// statement; TableID and FieldID point back at the designer when known.
+type DDLFailure struct {
+	Index     int    `json:"index"`
+	Statement string `json:"statement"`
+	Error     string `json:"error"`
+	Line      int    `json:"line,omitempty"`
+	Element   string `json:"element"`
+	ElementID string `json:"element_id,omitempty"`
+	TableID   string `json:"table_id,omitempty"`
+	FieldID   string `json:"field_id,omitempty"`
+}
+
+// statementError is a statement rejected by the database, as opposed to a
+// client or connection failure.
+type statementError struct {
+	Message string
+	Line    int
+}
+
+func (e *statementError) Error() string {
+	return e.Message
+}
+
+// verifySession executes statements one at a time in a scratch database.
+type verifySession struct {
+	exec    func(ctx context.Context, sql string) error
+	cleanup func()
+}
+
+func NewVerifyService(schemaService *SchemaService, verifyConfig *config.VerifyConfig) *VerifyService {
+	return &VerifyService{
+		schemaService: schemaService,
+		config:        verifyConfig,
+		log:           logger.GetLogger(),
+	}
+}
+
+// VerifyDDL executes the schema's DDL for each dialect, SQLite plus every
+// configured server when none are given, and stops each at its first error.
+func (s *VerifyService) VerifyDDL(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, dialects []string) (*DDLVerification, error) {
+	var targets []SQLDialect
+	for _, value := range dialects {
+		dialect, ok := ParseSQLDialect(value)
+		if !ok {
+			return nil, fmt.Errorf("unsupported dialect: %s", value)
+		}
+		targets = append(targets, dialect)
+	}
+	if len(targets) == 0 {
+		targets = append(targets, DialectSQLite)
+		if s.config.PostgresURL != "" {
+			targets = append(targets, DialectPostgreSQL)
+		}
+		if s.config.MySQLURL != "" {
+			targets = append(targets, DialectMySQL)
+		}
+	}
+
+	schema, err := s.schemaService.GetSchemaByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verification runs the schema's SQL on the configured servers.
+	if schema.UserID != userID {
+		return nil, fmt.Errorf("access denied: only the schema owner can verify its DDL")
+	}
+
+	// Passing needs at least one dialect executed and none failing.
+	verification := &DDLVerification{SchemaID: id}
+	seen := make(map[SQLDialect]bool)
+	failed := false
+	for _, dialect := range targets {
+		if seen[dialect] {
+			continue
+		}
+		seen[dialect] = true
+
+		result := s.verify(ctx, schema, dialect)
+		verification.Results = append(verification.Results, result)
+		switch result.Status {
+		case VerifyPassed:
+			verification.Passed = true
+		case VerifyFailed, VerifyError:
+			failed = true
+		}
+	}
+	verification.Passed = verification.Passed && !failed
+
+	s.log.Infof("DDL of schema %s verified: passed=%t", id.Hex(), verification.Passed)
+	return verification, nil
+}
+
+func (s *VerifyService) verify(ctx context.Context, schema *models.Schema, dialect SQLDialect) DDLVerificationResult {
+	statements := newDDLGenerator(schema, dialect).statements()
+	result := DDLVerificationResult{Dialect: dialect, Statements: len(statements)}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	// MySQL namespaces are databases of their own, so they are renamed into
+	// the scratch area. Statements line up one to one with the originals.
+	executed := statements
+	scratch := verifyDatabasePrefix + primitive.NewObjectID().Hex()
+	if dialect == DialectMySQL {
+		executed = newDDLGenerator(prefixNamespaces(schema, scratch+"_"), dialect).statements()
+	}
+
+	session, err := s.openSession(ctx, schema, dialect, scratch)
+	if err != nil {
+		var skipped *verifySkippedError
+		if errors.As(err, &skipped) {
+			result.Status = VerifySkipped
+		} else {
+			result.Status = VerifyError
+			s.log.Warnf("DDL verification for %s could not start: %v", dialect, err)
+		}
+		result.Message = err.Error()
+		return result
+	}
+	defer session.cleanup()
+
+	for i, statement := range executed {
+		// View definitions and expressions are embedded as written, so a
+		// statement is only run when it is still exactly one statement.
+		single, err := singleSQLStatement(statement.SQL, dialect)
+		if err != nil {
+			err = &statementError{Message: err.Error()}
+		} else {
+			err = session.exec(ctx, single)
+		}
+		if err == nil {
+			result.Executed++
+			continue
+		}
+
+		var rejected *statementError
+		if !errors.As(err, &rejected) {
+			result.Status = VerifyError
+			result.Message = err.Error()
+			return result
+		}
+		result.Status = VerifyFailed
+		result.Failure = ddlFailure(i, statements[i], rejected)
+		return result
+	}
+
+	result.Status = VerifyPassed
+	return result
+}
+
+type verifySkippedError struct {
+	reason string
+}
+
+func (e *verifySkippedError) Error() string {
+	return e.reason
+}
+
+func (s *VerifyService) openSession(ctx context.Context, schema *models.Schema, dialect SQLDialect, scratch string) (*verifySession, error) {
+	switch dialect {
+	case DialectSQLite:
+		return s.openSQLite(ctx)
+	case DialectPostgreSQL:
+		if s.config.PostgresURL == "" {
+			return nil, &verifySkippedError{"postgresql verification is not configured: set DDL_VERIFY_POSTGRES_URL"}
+		}
+		return s.openPostgres(ctx, scratch)
+	case DialectMySQL:
+		if s.config.MySQLURL == "" {
+			return nil, &verifySkippedError{"mysql verification is not configured: set DDL_VERIFY_MYSQL_URL"}
+		}
+		return s.openMySQL(ctx, schema, scratch)
+	}
+	return nil, fmt.Errorf("unsupported dialect: %s", dialect)
+}
+
+// openSQLite runs statements against an embedded in-memory database. The
+// pool holds a single connection, as each connection to :memory: opens a
+// database of its own.
+func (s *VerifyService) openSQLite(ctx context.Context) (*verifySession, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open scratch database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open scratch database: %v", err)
+	}
+
+	return &verifySession{
+		exec: func(ctx context.Context, statement string) error {
+			if _, err := db.ExecContext(ctx, statement); err != nil {
+				var sqliteErr *sqlite.Error
+				if !errors.As(err, &sqliteErr) {
+					return err
+				}
+				return &statementError{Message: sqliteErrorPattern.ReplaceAllString(sqliteErr.Error(), "")}
+			}
+			return nil
+		},
+		cleanup: func() {
+			db.Close()
+		},
+	}, nil
+}
+
+// openPostgres creates a scratch database next to the configured one and
+// connects to it. Statements go through the extended query protocol, which
+// takes a single statement per call.
+func (s *VerifyService) openPostgres(ctx context.Context, scratch string) (*verifySession, error) {
+	serverConfig, err := pgx.ParseConfig(s.config.PostgresURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DDL_VERIFY_POSTGRES_URL: %v", err)
+	}
+	server, err := pgx.ConnectConfig(ctx, serverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgresql: %v", err)
+	}
+	if _, err := server.Exec(ctx, "CREATE DATABASE "+scratch); err != nil {
+		server.Close(context.Background())
+		return nil, fmt.Errorf("failed to create scratch database: %v", err)
+	}
+	drop := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := server.Exec(ctx, "DROP DATABASE IF EXISTS "+scratch); err != nil {
+			s.log.Errorf("Failed to drop scratch database %s: %v", scratch, err)
+		}
+		server.Close(ctx)
+	}
+
+	scratchConfig := serverConfig.Copy()
+	scratchConfig.Database = scratch
+	conn, err := pgx.ConnectConfig(ctx, scratchConfig)
+	if err != nil {
+		drop()
+		return nil, fmt.Errorf("failed to connect to scratch database: %v", err)
+	}
+
+	return &verifySession{
+		exec: func(ctx context.Context, statement string) error {
+			_, err := conn.PgConn().ExecParams(ctx, statement, nil, nil, nil, nil).Close()
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) {
+				return err
+			}
+			failure := &statementError{Message: pgErr.Message}
+			// Position counts characters from 1.
+			if runes := []rune(statement); pgErr.Position > 0 && int(pgErr.Position) <= len(runes) {
+				failure.Line = strings.Count(string(runes[:pgErr.Position-1]), "\n") + 1
+			}
+			return failure
+		},
+		cleanup: func() {
+			conn.Close(context.Background())
+			drop()
+		},
+	}, nil
+}
+
+// openMySQL creates a scratch database, plus one per namespace, and drops
+// them all afterwards. Multi-statement queries stay disabled, so the server
+// runs one statement per call.
+func (s *VerifyService) openMySQL(ctx context.Context, schema *models.Schema, scratch string) (*verifySession, error) {
+	serverURL, err := url.Parse(s.config.MySQLURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DDL_VERIFY_MYSQL_URL: %v", err)
+	}
+	serverConfig := mysql.NewConfig()
+	serverConfig.Net = "tcp"
+	serverConfig.Addr = serverURL.Host
+	if serverURL.Port() == "" {
+		serverConfig.Addr = net.JoinHostPort(serverURL.Hostname(), "3306")
+	}
+	if serverURL.User != nil {
+		serverConfig.User = serverURL.User.Username()
+		serverConfig.Passwd, _ = serverURL.User.Password()
+	}
+
+	server, err := openMySQLDatabase(serverConfig)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := server.ExecContext(ctx, "CREATE DATABASE `"+scratch+"`"); err != nil {
+		server.Close()
+		return nil, fmt.Errorf("failed to create scratch database: %v", err)
+	}
+
+	databases := []string{scratch}
+	for _, namespace := range schemaNamespaces(schema) {
+		databases = append(databases, scratch+"_"+namespace)
+	}
+	drop := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		for _, database := range databases {
+			if _, err := server.ExecContext(ctx, "DROP DATABASE IF EXISTS `"+database+"`"); err != nil {
+				s.log.Errorf("Failed to drop scratch database %s: %v", database, err)
+			}
+		}
+		server.Close()
+	}
+
+	scratchConfig := serverConfig.Clone()
+	scratchConfig.DBName = scratch
+	db, err := openMySQLDatabase(scratchConfig)
+	if err != nil {
+		drop()
+		return nil, err
+	}
+
+	return &verifySession{
+		exec: func(ctx context.Context, statement string) error {
+			_, err := db.ExecContext(ctx, statement)
+			var mysqlErr *mysql.MySQLError
+			if !errors.As(err, &mysqlErr) {
+				return err
+			}
+			failure := &statementError{Message: strings.NewReplacer(scratch+"_", "", scratch+".", "").Replace(mysqlErr.Message)}
+			if line := mysqlLinePattern.FindStringSubmatch(mysqlErr.Message); line != nil {
+				failure.Line, _ = strconv.Atoi(line[1])
+			}
+			return failure
+		},
+		cleanup: func() {
+			db.Close()
+			drop()
+		},
+	}, nil
+}
+
+func openMySQLDatabase(config *mysql.Config) (*sql.DB, error) {
+	connector, err := mysql.NewConnector(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DDL_VERIFY_MYSQL_URL: %v", err)
+	}
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+func schemaNamespaces(schema *models.Schema) []string {
+	var namespaces []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			namespaces = append(namespaces, name)
+		}
+	}
+	for _, namespace := range schema.Namespaces {
+		add(namespace.Name)
+	}
+	for _, table := range schema.Tables {
+		add(table.Namespace)
+	}
+	for _, enum := range schema.Enums {
+		add(enum.Namespace)
+	}
+	for _, view := range schema.Views {
+		add(view.Namespace)
+	}
+	return namespaces
+}
+
+// prefixNamespaces returns a copy of the schema with every namespace
+// renamed. View definitions are raw SQL and keep their original names.
+func prefixNamespaces(schema *models.Schema, prefix string) *models.Schema {
+	prefixed := *schema
+	rename := func(name string) string {
+		if name == "" {
+			return ""
+		}
+		return prefix + name
+	}
+
+	prefixed.Namespaces = make([]models.Namespace, len(schema.Namespaces))
+	for i, namespace := range schema.Namespaces {
+		namespace.Name = rename(namespace.Name)
+		prefixed.Namespaces[i] = namespace
+	}
+	prefixed.Tables = make([]models.Table, len(schema.Tables))
+	for i, table := range schema.Tables {
+		table.Namespace = rename(table.Namespace)
+		prefixed.Tables[i] = table
+	}
+	prefixed.Enums = make([]models.Enum, len(schema.Enums))
+	for i, enum := range schema.Enums {
+		enum.Namespace = rename(enum.Namespace)
+		prefixed.Enums[i] = enum
+	}
+	prefixed.Views = make([]models.View, len(schema.Views))
+	for i, view := range schema.Views {
+		view.Namespace = rename(view.Namespace)
+		prefixed.Views[i] = view
+	}
+	return &prefixed
+}
+
+// ddlFailure traces a rejected statement back to the schema, using the
+// failing line of a CREATE TABLE or a column named in the error.
+func ddlFailure(index int, statement ddlStatement, rejected *statementError) *DDLFailure {
+	failure := &DDLFailure{
+		Index:     index + 1,
+		Statement: statement.SQL,
+		Error:     rejected.Message,
+		Line:      rejected.Line,
+		Element:   statement.Element,
+		ElementID: statement.ID,
+	}
+	if statement.Table == nil {
+		return failure
+	}
+	failure.TableID = statement.Table.ID
+
+	lines := strings.Split(statement.SQL, "\n")
+	if rejected.Line > 0 && rejected.Line <= len(lines) {
+		line := strings.TrimSpace(lines[rejected.Line-1])
+		for _, field := range statement.Table.Fields {
+			for _, quoted := range []string{`"` + field.Name + `" `, "`" + field.Name + "` "} {
+				if strings.HasPrefix(line, quoted) {
+					failure.FieldID = field.ID
+					return failure
+				}
+			}
+		}
+	}
+	// Later fields first, so a duplicate column points at the duplicate.
+	for i := len(statement.Table.Fields) - 1; i >= 0; i-- {
+		field := statement.Table.Fields[i]
+		name := regexp.QuoteMeta(field.Name)
+		if regexp.MustCompile(fmt.Sprintf(quotedFieldPattern, name, name)).MatchString(rejected.Message) {
+			failure.FieldID = field.ID
+			return failure
+		}
+	}
+	return failure
+}